@@ -12,6 +12,8 @@ import (
 	"log_book/internal/config"
 	"log_book/internal/database"
 	"log_book/internal/handlers"
+	"log_book/internal/jobs"
+	"log_book/internal/mail"
 	"log_book/internal/middleware"
 	"log_book/internal/repository"
 	"log_book/internal/scheduler"
@@ -32,6 +34,11 @@ func main() {
 
 	middleware.InitClerk(cfg.ClerkSecret)
 
+	mailSender, err := mail.New(cfg.Mail)
+	if err != nil {
+		log.Fatalf("Failed to set up mail: %v", err)
+	}
+
 	db, err := database.New(cfg.DatabaseURL)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
@@ -41,34 +48,78 @@ func main() {
 	// Repositories
 	userRepo := repository.NewUserRepository(db)
 	sessionRepo := repository.NewSessionRepository(db)
+	breakRepo := repository.NewBreakRepository(db)
 	documentRepo := repository.NewDocumentRepository(db)
 	mediaRepo := repository.NewMediaRepository(db)
 	feedbackRepo := repository.NewFeedbackRepository(db)
 	adminRepo := repository.NewAdminRepository(db)
+	policyRepo := repository.NewPolicyRepository(db)
+	scheduleRuleRepo := repository.NewScheduleRuleRepository(db)
+	plannedStartRepo := repository.NewPlannedStartRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	optProfileRepo := repository.NewOPTProfileRepository(db)
+	employerRepo := repository.NewEmployerRepository(db)
+	trainingPlanRepo := repository.NewTrainingPlanRepository(db)
+	timesheetRepo := repository.NewTimesheetRepository(db)
+	signatureRepo := repository.NewSignatureRepository(db)
+	calendarRepo := repository.NewCalendarRepository(db)
+	revisionRepo := repository.NewRevisionRepository(db)
 
 	// Services
-	timeService := services.NewTimeService(sessionRepo, userRepo)
-	scheduleService := services.NewScheduleService(sessionRepo, userRepo)
-	documentService := services.NewDocumentService(documentRepo, userRepo)
-	storageService := services.NewStorageService(mediaRepo, documentRepo, userRepo, cfg.R2Config)
+	jobService := services.NewJobService(jobRepo)
+	policyService := services.NewPolicyService(policyRepo, sessionRepo, userRepo)
+	scheduleService := services.NewScheduleService(sessionRepo, scheduleRuleRepo, userRepo, policyService)
+	timesheetService := services.NewTimesheetService(timesheetRepo, employerRepo, sessionRepo, userRepo)
+	signatureService := services.NewSignatureService(signatureRepo, employerRepo, userRepo, timesheetService, jobService, mailSender, cfg.Signing)
+	timeService := services.NewTimeService(sessionRepo, breakRepo, userRepo, employerRepo, policyService, scheduleService, timesheetService)
+	plannedStartService := services.NewPlannedStartService(plannedStartRepo, userRepo, timeService)
+	documentService := services.NewDocumentService(documentRepo, userRepo, employerRepo, timesheetService)
+	storageService := services.NewStorageService(mediaRepo, documentRepo, userRepo, timesheetService, cfg.R2Config)
 	feedbackService := services.NewFeedbackService(feedbackRepo, userRepo)
-	summarizeService := services.NewSummarizeService(documentRepo, userRepo, adminRepo, cfg.ClaudeAPIKey)
+	complianceService := services.NewComplianceService(optProfileRepo, sessionRepo, employerRepo, userRepo, jobService)
+	reportService := services.NewReportService(sessionRepo, userRepo, employerRepo)
+	exportService := services.NewExportService(sessionRepo, documentRepo, employerRepo, userRepo)
+	importService := services.NewImportService(sessionRepo, documentRepo, employerRepo, userRepo, policyService, timesheetService)
+	correctionService := services.NewCorrectionService(revisionRepo, sessionRepo, breakRepo, userRepo, policyService, timesheetService, cfg.CorrectionWindow)
+	calendarService := services.NewCalendarService(calendarRepo, sessionRepo, employerRepo, userRepo, cfg.PublicURL, cfg.AppURL)
+	employerService := services.NewEmployerService(employerRepo, userRepo)
+	trainingPlanService := services.NewTrainingPlanService(trainingPlanRepo, employerRepo, documentRepo, userRepo, timesheetService)
+	summarizeService := services.NewSummarizeService(documentRepo, userRepo, adminRepo, jobService, trainingPlanService, cfg.ClaudeAPIKey)
 	adminService := services.NewAdminService(adminRepo)
+	userService := services.NewUserService(userRepo)
 
 	// Handlers
 	healthHandler := handlers.NewHealthHandler(db)
 	timeHandler := handlers.NewTimeHandler(timeService)
 	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	plannedStartHandler := handlers.NewPlannedStartHandler(plannedStartService)
 	documentHandler := handlers.NewDocumentHandler(documentService)
 	uploadHandler := handlers.NewUploadHandler(storageService)
 	feedbackHandler := handlers.NewFeedbackHandler(feedbackService)
+	complianceHandler := handlers.NewComplianceHandler(complianceService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	importHandler := handlers.NewImportHandler(importService)
+	correctionHandler := handlers.NewCorrectionHandler(correctionService)
+	employerHandler := handlers.NewEmployerHandler(employerService)
+	trainingPlanHandler := handlers.NewTrainingPlanHandler(trainingPlanService)
+	timesheetHandler := handlers.NewTimesheetHandler(timesheetService)
+	signatureHandler := handlers.NewSignatureHandler(signatureService)
 	authHandler := handlers.NewAuthHandler(userRepo)
 	summarizeHandler := handlers.NewSummarizeHandler(summarizeService)
 	adminHandler := handlers.NewAdminHandler(adminService)
+	profileHandler := handlers.NewProfileHandler(userService)
+	policyHandler := handlers.NewPolicyHandler(policyService)
+	jobHandler := handlers.NewJobHandler(jobService)
 
 	rateLimiter := middleware.NewRateLimiter(100, time.Minute)
 
 	router := gin.Default()
+	// Only the reverse proxy may set the client IP, which sign-offs record
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 	router.Use(middleware.CORSMiddleware(cfg.AllowedOrigins))
 
 	// Request body size limit (10MB)
@@ -78,6 +129,13 @@ func main() {
 	router.GET("/health", healthHandler.Health)
 	router.GET("/ready", healthHandler.Ready)
 
+	// Emailed sign-off links (no auth; the token is the credential)
+	router.GET("/sign/:token", signatureHandler.SigningPage)
+	router.POST("/sign/:token", signatureHandler.Sign)
+
+	// Calendar feed (no auth; calendar clients cannot send a bearer token)
+	router.GET("/calendar/:token", calendarHandler.Feed)
+
 	// API v1 (auth + rate limit)
 	v1 := router.Group("/api/v1")
 	v1.Use(middleware.AuthMiddleware(userRepo))
@@ -85,23 +143,45 @@ func main() {
 	{
 		// Auth
 		v1.GET("/me", authHandler.GetMe)
+		v1.PATCH("/me", profileHandler.UpdateProfile)
 
 		// Time tracking
 		time := v1.Group("/time")
 		{
 			time.POST("/start", timeHandler.StartSession)
 			time.POST("/stop", timeHandler.StopSession)
+			time.POST("/pause", timeHandler.PauseSession)
+			time.POST("/resume", timeHandler.ResumeSession)
+			time.POST("/heartbeat", timeHandler.Heartbeat)
+			time.POST("/cancel", timeHandler.CancelSession)
 			time.GET("/active", timeHandler.GetActiveSession)
+			time.GET("/policy", policyHandler.GetEffectivePolicy)
 		}
 
 		// Sessions
 		v1.GET("/sessions", timeHandler.ListSessions)
+		v1.GET("/sessions/export", exportHandler.ExportSessions)
 		v1.POST("/sessions/manual", timeHandler.CreateManualSession)
+		v1.POST("/sessions/import", importHandler.ImportSessions)
+		v1.POST("/sessions/import/tracker", importHandler.ImportTracker)
+		v1.POST("/sessions/:id/void", timeHandler.VoidSession)
+		v1.PUT("/sessions/:id/end-time", correctionHandler.CorrectEndTime)
+		v1.POST("/sessions/:id/corrections", correctionHandler.RequestCorrection)
+		v1.GET("/sessions/:id/revisions", correctionHandler.ListRevisions)
+		v1.GET("/sessions/:id/events", timeHandler.ListSessionEvents)
 
 		// Schedule
 		schedule := v1.Group("/schedule")
 		{
 			schedule.POST("", scheduleHandler.CreateSchedule)
+			schedule.GET("/rules", scheduleHandler.ListRules)
+			schedule.POST("/rules", scheduleHandler.CreateRule)
+			schedule.PUT("/rules/:id", scheduleHandler.UpdateRule)
+			schedule.DELETE("/rules/:id", scheduleHandler.DeleteRule)
+			schedule.GET("/starts", plannedStartHandler.ListPlannedStarts)
+			schedule.POST("/starts", plannedStartHandler.CreatePlannedStart)
+			schedule.PUT("/starts/:id", plannedStartHandler.UpdatePlannedStart)
+			schedule.DELETE("/starts/:id", plannedStartHandler.DeletePlannedStart)
 			schedule.GET("/:id", scheduleHandler.GetSchedule)
 			schedule.DELETE("/:id", scheduleHandler.CancelSchedule)
 		}
@@ -113,10 +193,13 @@ func main() {
 			documents.GET("", documentHandler.ListDocuments)
 			documents.GET("/summarize/quota", summarizeHandler.GetQuota)
 			documents.GET("/summarize", summarizeHandler.Summarize)
+			documents.GET("/export", exportHandler.ExportDocuments)
 
 			documents.GET("/:id", documentHandler.GetDocument)
 			documents.PUT("/:id", documentHandler.UpdateDocument)
 			documents.DELETE("/:id", documentHandler.DeleteDocument)
+			documents.GET("/:id/goals", trainingPlanHandler.GetDocumentGoals)
+			documents.PUT("/:id/goals", trainingPlanHandler.SetDocumentGoals)
 		}
 
 		// Upload
@@ -134,6 +217,63 @@ func main() {
 		v1.POST("/feedback", feedbackHandler.CreateFeedback)
 		v1.GET("/feedback", feedbackHandler.ListFeedback)
 
+		// Employers
+		employers := v1.Group("/employers")
+		{
+			employers.GET("", employerHandler.ListEmployers)
+			employers.POST("", employerHandler.CreateEmployer)
+			employers.GET("/:id", employerHandler.GetEmployer)
+			employers.PUT("/:id", employerHandler.UpdateEmployer)
+			employers.DELETE("/:id", employerHandler.DeleteEmployer)
+			employers.GET("/:id/training-plan", trainingPlanHandler.GetTrainingPlan)
+			employers.POST("/:id/goals", trainingPlanHandler.CreateGoal)
+			employers.POST("/:id/evaluations/:type/submit", trainingPlanHandler.SubmitEvaluation)
+			employers.GET("/:id/evaluations/:type/draft", summarizeHandler.DraftEvaluation)
+		}
+
+		// Training plan goals
+		v1.PUT("/training-goals/:id", trainingPlanHandler.UpdateGoal)
+		v1.DELETE("/training-goals/:id", trainingPlanHandler.DeleteGoal)
+
+		// Timesheets
+		timesheets := v1.Group("/timesheets")
+		{
+			timesheets.POST("", timesheetHandler.CreateTimesheet)
+			timesheets.GET("", timesheetHandler.ListTimesheets)
+			timesheets.GET("/reviews", timesheetHandler.ListPendingReviews)
+			timesheets.GET("/:id", timesheetHandler.GetTimesheet)
+			timesheets.POST("/:id/submit", timesheetHandler.SubmitTimesheet)
+			timesheets.POST("/:id/withdraw", timesheetHandler.WithdrawTimesheet)
+			timesheets.POST("/:id/approve", timesheetHandler.ApproveTimesheet)
+			timesheets.POST("/:id/reject", timesheetHandler.RejectTimesheet)
+			timesheets.POST("/:id/reopen", timesheetHandler.ReopenTimesheet)
+			timesheets.POST("/:id/signature-requests", signatureHandler.RequestSignature)
+			timesheets.GET("/:id/signature-requests", signatureHandler.ListSignatureRequests)
+		}
+
+		// Compliance
+		compliance := v1.Group("/compliance")
+		{
+			compliance.GET("/opt", complianceHandler.GetOPTStatus)
+			compliance.PUT("/opt/profile", complianceHandler.SetOPTProfile)
+			compliance.GET("/weekly", complianceHandler.GetWeeklyCompliance)
+		}
+
+		// Calendar feed
+		calendar := v1.Group("/calendar")
+		{
+			calendar.GET("/feed", calendarHandler.GetFeed)
+			calendar.POST("/feed", calendarHandler.RegenerateFeed)
+			calendar.DELETE("/feed", calendarHandler.RevokeFeed)
+		}
+
+		// Reports
+		reports := v1.Group("/reports")
+		{
+			reports.GET("/timesheet.pdf", reportHandler.TimesheetPDF)
+			reports.GET("/hours", reportHandler.HoursTotals)
+		}
+
 		// Admin
 		admin := v1.Group("/admin")
 		admin.Use(middleware.AdminMiddleware(userRepo))
@@ -143,14 +283,33 @@ func main() {
 			admin.GET("/ai-usage", adminHandler.GetAIUsageStats)
 			admin.GET("/ai-usage/users", adminHandler.GetPerUserAIUsage)
 			admin.GET("/feedback", adminHandler.GetFeedback)
+			admin.GET("/policies", policyHandler.ListPolicies)
+			admin.POST("/policies", policyHandler.CreatePolicy)
+			admin.PUT("/policies/:id", policyHandler.UpdatePolicy)
+			admin.DELETE("/policies/:id", policyHandler.DeletePolicy)
+			admin.GET("/jobs", jobHandler.ListJobs)
+			admin.GET("/jobs/:id", jobHandler.GetJob)
+			admin.POST("/jobs/:id/retry", jobHandler.RetryJob)
+			admin.GET("/corrections", correctionHandler.ListPendingCorrections)
+			admin.POST("/corrections/:id/approve", correctionHandler.ApproveCorrection)
+			admin.POST("/corrections/:id/reject", correctionHandler.RejectCorrection)
 		}
 	}
 
+	// Background jobs
+	worker := jobs.NewWorker(jobService, cfg.JobWorkers, time.Second)
+	jobs.Handle(worker, services.JobTypeRecordAIUsage, summarizeService.RecordUsage)
+	jobs.Handle(worker, services.JobTypeSendMail, mailSender.Send)
+	jobs.Handle(worker, services.JobTypeSendSignatureRequest, signatureService.SendSignatureRequest)
+	scheduler.RegisterJobs(worker, scheduleService, plannedStartService, complianceService, jobService, cfg.Idle)
+	worker.Start()
+
 	// Background scheduler
-	sched := scheduler.New(scheduleService, time.Minute)
+	sched := scheduler.New(jobService, time.Minute, cfg.Idle)
 	sched.Start()
 
-	// HTTP server — WriteTimeout set high enough for SSE streaming
+	// HTTP server — WriteTimeout set high enough for SSE streaming; exports
+	// lift it for their own responses
 	srv := &http.Server{
 		Addr:              ":" + cfg.Port,
 		Handler:           router,
@@ -176,6 +335,7 @@ func main() {
 	log.Println("Shutting down server...")
 
 	sched.Stop()
+	worker.Stop()
 	rateLimiter.Stop()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,7 +17,44 @@ type Config struct {
 	ClerkSecret    string
 	ClaudeAPIKey   string
 	AllowedOrigins []string
+	// TrustedProxies are the reverse proxies whose X-Forwarded-For is
+	// believed for the client IP. Empty trusts none.
+	TrustedProxies []string
 	R2Config       R2Config
+	Idle           IdleConfig
+	JobWorkers     int
+	Mail           MailConfig
+	Signing        SigningConfig
+	// PublicURL is the externally reachable base URL of this backend and
+	// AppURL that of the frontend, for links that leave the app
+	PublicURL string
+	AppURL    string
+	// CorrectionWindow is how long after a session ends its owner may correct
+	// it without admin approval. Zero sends every correction for approval.
+	CorrectionWindow time.Duration
+}
+
+// MailConfig selects where outgoing mail goes. "log" writes messages to the
+// server log; "file" writes one .eml file per message to FileDir.
+type MailConfig struct {
+	Sender  string
+	FileDir string
+	From    string
+}
+
+// SigningConfig controls emailed sign-off links. An empty Secret disables
+// them.
+type SigningConfig struct {
+	Secret  string
+	BaseURL string
+	LinkTTL time.Duration
+}
+
+// IdleConfig controls what happens to sessions whose device stops sending
+// heartbeats. A zero Timeout disables idle detection.
+type IdleConfig struct {
+	Timeout time.Duration
+	Action  string // "stop" or "flag"
 }
 
 type R2Config struct {
@@ -30,12 +69,13 @@ func Load() (*Config, error) {
 	godotenv.Load()
 
 	cfg := &Config{
-		Port:        getEnv("PORT", "8080"),
-		Environment: getEnv("ENV", "development"),
-		DatabaseURL: getEnv("DATABASE_URL", ""),
-		ClerkSecret:  getEnv("CLERK_SECRET_KEY", ""),
-		ClaudeAPIKey: getEnv("ANTHROPIC_API_KEY", ""),
-		AllowedOrigins: parseOrigins(getEnv("ALLOWED_ORIGINS", "http://localhost:5173,http://localhost:3000")),
+		Port:           getEnv("PORT", "8080"),
+		Environment:    getEnv("ENV", "development"),
+		DatabaseURL:    getEnv("DATABASE_URL", ""),
+		ClerkSecret:    getEnv("CLERK_SECRET_KEY", ""),
+		ClaudeAPIKey:   getEnv("ANTHROPIC_API_KEY", ""),
+		AllowedOrigins: parseList(getEnv("ALLOWED_ORIGINS", "http://localhost:5173,http://localhost:3000")),
+		TrustedProxies: parseList(getEnv("TRUSTED_PROXIES", "")),
 		R2Config: R2Config{
 			AccountID:       getEnv("R2_ACCOUNT_ID", ""),
 			AccessKeyID:     getEnv("R2_ACCESS_KEY_ID", ""),
@@ -43,7 +83,44 @@ func Load() (*Config, error) {
 			BucketName:      getEnv("R2_BUCKET_NAME", "logbook-media"),
 			PublicURL:       getEnv("R2_PUBLIC_URL", ""),
 		},
+		Idle: IdleConfig{
+			Action: getEnv("IDLE_ACTION", "stop"),
+		},
+		Mail: MailConfig{
+			Sender:  getEnv("MAIL_SENDER", "log"),
+			FileDir: getEnv("MAIL_FILE_DIR", "mail"),
+			From:    getEnv("MAIL_FROM", "LogBook <no-reply@logbook.local>"),
+		},
+		Signing: SigningConfig{
+			Secret: getEnv("SIGNING_SECRET", ""),
+		},
+		PublicURL: strings.TrimRight(getEnv("PUBLIC_URL", "http://localhost:8080"), "/"),
+		AppURL:    strings.TrimRight(getEnv("APP_URL", "http://localhost:5173"), "/"),
 	}
+	cfg.Signing.BaseURL = cfg.PublicURL
+
+	idleMinutes, err := strconv.Atoi(getEnv("IDLE_TIMEOUT_MINUTES", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("IDLE_TIMEOUT_MINUTES must be a number of minutes: %w", err)
+	}
+	cfg.Idle.Timeout = time.Duration(idleMinutes) * time.Minute
+
+	cfg.JobWorkers, err = strconv.Atoi(getEnv("JOB_WORKERS", "2"))
+	if err != nil {
+		return nil, fmt.Errorf("JOB_WORKERS must be a number: %w", err)
+	}
+
+	linkHours, err := strconv.Atoi(getEnv("SIGNING_LINK_TTL_HOURS", "72"))
+	if err != nil {
+		return nil, fmt.Errorf("SIGNING_LINK_TTL_HOURS must be a number of hours: %w", err)
+	}
+	cfg.Signing.LinkTTL = time.Duration(linkHours) * time.Hour
+
+	correctionDays, err := strconv.Atoi(getEnv("CORRECTION_APPROVAL_DAYS", "7"))
+	if err != nil {
+		return nil, fmt.Errorf("CORRECTION_APPROVAL_DAYS must be a number of days: %w", err)
+	}
+	cfg.CorrectionWindow = time.Duration(correctionDays) * 24 * time.Hour
 
 	if err := cfg.validate(); err != nil {
 		return nil, err
@@ -59,10 +136,28 @@ func (c *Config) validate() error {
 	if c.ClerkSecret == "" {
 		return fmt.Errorf("CLERK_SECRET_KEY is required")
 	}
+	if c.Idle.Timeout < 0 {
+		return fmt.Errorf("IDLE_TIMEOUT_MINUTES cannot be negative")
+	}
+	if c.Idle.Action != "stop" && c.Idle.Action != "flag" {
+		return fmt.Errorf("IDLE_ACTION must be \"stop\" or \"flag\"")
+	}
+	if c.JobWorkers < 1 {
+		return fmt.Errorf("JOB_WORKERS must be at least 1")
+	}
+	if c.Mail.Sender != "log" && c.Mail.Sender != "file" {
+		return fmt.Errorf("MAIL_SENDER must be \"log\" or \"file\"")
+	}
+	if c.Signing.LinkTTL <= 0 {
+		return fmt.Errorf("SIGNING_LINK_TTL_HOURS must be at least 1")
+	}
+	if c.CorrectionWindow < 0 {
+		return fmt.Errorf("CORRECTION_APPROVAL_DAYS cannot be negative")
+	}
 	return nil
 }
 
-func parseOrigins(s string) []string {
+func parseList(s string) []string {
 	parts := strings.Split(s, ",")
 	origins := make([]string, 0, len(parts))
 	for _, p := range parts {
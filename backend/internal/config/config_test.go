@@ -0,0 +1,73 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// setRequired sets the variables Load cannot do without.
+func setRequired(t *testing.T) {
+	t.Helper()
+	t.Setenv("DATABASE_URL", "postgres://localhost/logbook_test")
+	t.Setenv("CLERK_SECRET_KEY", "sk_test")
+}
+
+func TestLoadIdleDefaults(t *testing.T) {
+	setRequired(t)
+	t.Setenv("IDLE_ACTION", "")
+	t.Setenv("IDLE_TIMEOUT_MINUTES", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Idle.Action != "stop" {
+		t.Errorf("Idle.Action = %q, want stop", cfg.Idle.Action)
+	}
+	if cfg.Idle.Timeout != 30*time.Minute {
+		t.Errorf("Idle.Timeout = %v, want 30m", cfg.Idle.Timeout)
+	}
+}
+
+func TestLoadIdleInvalid(t *testing.T) {
+	tests := []struct {
+		name, action, minutes string
+	}{
+		{"unknown action", "pause", "30"},
+		{"non-numeric timeout", "flag", "half an hour"},
+		{"negative timeout", "stop", "-5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequired(t)
+			t.Setenv("IDLE_ACTION", tt.action)
+			t.Setenv("IDLE_TIMEOUT_MINUTES", tt.minutes)
+			if _, err := Load(); err == nil {
+				t.Error("Load() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestLoadTrustedProxies(t *testing.T) {
+	setRequired(t)
+
+	t.Setenv("TRUSTED_PROXIES", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.TrustedProxies) != 0 {
+		t.Errorf("TrustedProxies = %v, want none by default", cfg.TrustedProxies)
+	}
+
+	t.Setenv("TRUSTED_PROXIES", " 10.0.0.1, ,10.1.0.0/16 ")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.TrustedProxies) != 2 || cfg.TrustedProxies[0] != "10.0.0.1" || cfg.TrustedProxies[1] != "10.1.0.0/16" {
+		t.Errorf("TrustedProxies = %q", cfg.TrustedProxies)
+	}
+}
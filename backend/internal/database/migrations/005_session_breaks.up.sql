@@ -0,0 +1,17 @@
+-- Migration: 005_session_breaks
+-- Description: Pause/resume support. Each row is one break inside a time session;
+-- an open break (end_time IS NULL) means the session is currently paused.
+
+CREATE TABLE session_breaks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES time_sessions(id) ON DELETE CASCADE,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (end_time IS NULL OR end_time >= start_time)
+);
+
+CREATE INDEX idx_breaks_session ON session_breaks(session_id, start_time);
+
+-- A session can only have one open break at a time
+CREATE UNIQUE INDEX idx_breaks_one_open ON session_breaks(session_id) WHERE end_time IS NULL;
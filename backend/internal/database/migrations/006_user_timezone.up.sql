@@ -0,0 +1,5 @@
+-- Migration: 006_user_timezone
+-- Description: Per-user IANA time zone used for all day-boundary logic
+-- (one-session-per-day, date filters, document log dates, daily usage).
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
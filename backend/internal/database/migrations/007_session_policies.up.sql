@@ -0,0 +1,34 @@
+-- Migration: 007_session_policies
+-- Description: Configurable session rules. A policy row is scoped globally, to a
+-- role or to a single user; NULL fields inherit from the less specific scope.
+
+CREATE TABLE session_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('global', 'role', 'user')),
+    role VARCHAR(50),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    min_duration_minutes INT CHECK (min_duration_minutes >= 0),
+    max_duration_minutes INT CHECK (max_duration_minutes > 0),
+    max_sessions_per_day INT CHECK (max_sessions_per_day >= 0),
+    allow_future_end BOOLEAN,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (
+        (scope = 'global' AND role IS NULL AND user_id IS NULL) OR
+        (scope = 'role' AND role IS NOT NULL AND user_id IS NULL) OR
+        (scope = 'user' AND user_id IS NOT NULL AND role IS NULL)
+    )
+);
+
+CREATE UNIQUE INDEX idx_policies_global ON session_policies(scope) WHERE scope = 'global';
+CREATE UNIQUE INDEX idx_policies_role ON session_policies(role) WHERE scope = 'role';
+CREATE UNIQUE INDEX idx_policies_user ON session_policies(user_id) WHERE scope = 'user';
+
+CREATE TRIGGER update_session_policies_updated_at
+    BEFORE UPDATE ON session_policies
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Seed the global policy with the rules that used to be hard-coded
+INSERT INTO session_policies (scope, min_duration_minutes, max_duration_minutes, max_sessions_per_day, allow_future_end)
+VALUES ('global', 240, 1440, 1, false);
@@ -0,0 +1,6 @@
+-- Migration: 008_session_cancellation
+-- Description: Record why and when a session was cancelled (discarded while active)
+-- or voided (completed but excluded from totals, kept for audit).
+
+ALTER TABLE time_sessions ADD COLUMN cancel_reason TEXT;
+ALTER TABLE time_sessions ADD COLUMN cancelled_at TIMESTAMP WITH TIME ZONE;
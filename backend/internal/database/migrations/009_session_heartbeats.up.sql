@@ -0,0 +1,9 @@
+-- Migration: 009_session_heartbeats
+-- Description: Last device heartbeat per session, used to stop or flag sessions
+-- whose device has gone silent.
+
+ALTER TABLE time_sessions ADD COLUMN last_heartbeat_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE time_sessions ADD COLUMN idle_detected_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_sessions_heartbeat ON time_sessions(last_heartbeat_at)
+    WHERE status = 'active' AND last_heartbeat_at IS NOT NULL;
@@ -0,0 +1,7 @@
+-- Migration: 010_session_end_reason
+-- Description: Record why a session ended, so automatically closed sessions are
+-- visible to the user and can be corrected.
+
+ALTER TABLE time_sessions ADD COLUMN end_reason VARCHAR(30);
+
+CREATE INDEX idx_sessions_active_start ON time_sessions(start_time) WHERE status = 'active';
@@ -0,0 +1,22 @@
+-- Migration: 011_schedule_rules
+-- Description: Recurring auto-stop rules ("weekdays stop at 17:30"). Days use
+-- 0 = Sunday .. 6 = Saturday and stop_time is wall-clock time in the user's zone.
+
+CREATE TABLE schedule_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100),
+    days INT[] NOT NULL,
+    stop_time VARCHAR(5) NOT NULL CHECK (stop_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (cardinality(days) > 0 AND days <@ ARRAY[0, 1, 2, 3, 4, 5, 6])
+);
+
+CREATE INDEX idx_schedule_rules_user ON schedule_rules(user_id) WHERE enabled;
+
+CREATE TRIGGER update_schedule_rules_updated_at
+    BEFORE UPDATE ON schedule_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
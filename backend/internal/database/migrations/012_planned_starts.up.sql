@@ -0,0 +1,29 @@
+-- Migration: 012_planned_starts
+-- Description: Planned shift starts. The scheduler opens a session at start_at
+-- (one-off) or at start_time on the listed days in the user's zone (recurring).
+
+CREATE TABLE planned_starts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100),
+    start_at TIMESTAMP WITH TIME ZONE,
+    days INT[],
+    start_time VARCHAR(5) CHECK (start_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    last_fired_at TIMESTAMP WITH TIME ZONE,
+    last_result VARCHAR(255),
+    last_session_id UUID REFERENCES time_sessions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (
+        (start_at IS NOT NULL AND days IS NULL AND start_time IS NULL) OR
+        (start_at IS NULL AND cardinality(days) > 0 AND days <@ ARRAY[0, 1, 2, 3, 4, 5, 6] AND start_time IS NOT NULL)
+    )
+);
+
+CREATE INDEX idx_planned_starts_enabled ON planned_starts(user_id) WHERE enabled;
+
+CREATE TRIGGER update_planned_starts_updated_at
+    BEFORE UPDATE ON planned_starts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
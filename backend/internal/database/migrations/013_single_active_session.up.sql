@@ -0,0 +1,5 @@
+-- Migration: 013_single_active_session
+-- Description: Enforce at most one active session per user, so concurrent
+-- starts from several backend instances cannot both succeed.
+
+CREATE UNIQUE INDEX idx_sessions_one_active_per_user ON time_sessions(user_id) WHERE status = 'active';
@@ -0,0 +1,16 @@
+-- Migration: 015_opt_profiles
+-- Description: OPT start date and program type per user, used to count
+-- unemployment days against the 90-day (standard) or 150-day (STEM) limit.
+
+CREATE TABLE opt_profiles (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    program VARCHAR(10) NOT NULL CHECK (program IN ('standard', 'stem')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_opt_profiles_updated_at
+    BEFORE UPDATE ON opt_profiles
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
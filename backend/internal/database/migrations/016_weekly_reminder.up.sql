@@ -0,0 +1,4 @@
+-- Migration: 016_weekly_reminder
+-- Description: Opt-in reminder when the current week trends below 20 hours.
+
+ALTER TABLE opt_profiles ADD COLUMN weekly_reminder BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,30 @@
+-- Migration: 017_employers
+-- Description: Employers/organizations the user works for, as needed for
+-- SEVP reporting, and an optional employer on sessions and documents.
+
+CREATE TABLE employers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL,
+    address TEXT,
+    ein VARCHAR(10) CHECK (ein ~ '^[0-9]{2}-[0-9]{7}$'),
+    supervisor_name VARCHAR(200),
+    supervisor_email VARCHAR(255),
+    start_date DATE NOT NULL,
+    end_date DATE CHECK (end_date IS NULL OR end_date >= start_date),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_employers_user ON employers(user_id);
+
+CREATE TRIGGER update_employers_updated_at
+    BEFORE UPDATE ON employers
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE time_sessions ADD COLUMN employer_id UUID REFERENCES employers(id) ON DELETE SET NULL;
+ALTER TABLE documents ADD COLUMN employer_id UUID REFERENCES employers(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_sessions_employer ON time_sessions(employer_id) WHERE employer_id IS NOT NULL;
+CREATE INDEX idx_documents_employer ON documents(employer_id) WHERE employer_id IS NOT NULL;
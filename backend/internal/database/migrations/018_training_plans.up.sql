@@ -0,0 +1,38 @@
+-- Migration: 018_training_plans
+-- Description: STEM OPT I-983 training plan goals per employer, links from
+-- daily documents to goals, and submitted evaluations.
+
+CREATE TABLE training_goals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    employer_id UUID NOT NULL REFERENCES employers(id) ON DELETE CASCADE,
+    title VARCHAR(300) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_training_goals_employer ON training_goals(employer_id);
+
+CREATE TRIGGER update_training_goals_updated_at
+    BEFORE UPDATE ON training_goals
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE document_goals (
+    document_id UUID NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    goal_id UUID NOT NULL REFERENCES training_goals(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (document_id, goal_id)
+);
+
+CREATE INDEX idx_document_goals_goal ON document_goals(goal_id);
+
+-- One row per evaluation the user has filed with their DSO
+CREATE TABLE training_evaluations (
+    employer_id UUID NOT NULL REFERENCES employers(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('twelve_month', 'final')),
+    submitted_on DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (employer_id, type)
+);
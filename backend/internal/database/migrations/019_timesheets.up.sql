@@ -0,0 +1,44 @@
+-- Migration: 019_timesheets
+-- Description: Weekly or monthly timesheets of an employer's sessions that the
+-- employer's supervisor approves or rejects, with a log of every state change.
+
+CREATE TABLE timesheets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    employer_id UUID NOT NULL REFERENCES employers(id) ON DELETE CASCADE,
+    period_type VARCHAR(10) NOT NULL CHECK (period_type IN ('week', 'month')),
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL CHECK (period_end >= period_start),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'submitted', 'approved', 'rejected')),
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_timesheets_period ON timesheets(employer_id, period_type, period_start);
+CREATE INDEX idx_timesheets_user ON timesheets(user_id, period_start DESC);
+-- Lock checks look for approved periods
+CREATE INDEX idx_timesheets_approved ON timesheets(user_id, employer_id, period_start, period_end)
+    WHERE status = 'approved';
+
+CREATE TRIGGER update_timesheets_updated_at
+    BEFORE UPDATE ON timesheets
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Append-only; from_status is NULL for the creation event
+CREATE TABLE timesheet_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    timesheet_id UUID NOT NULL REFERENCES timesheets(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    actor_name VARCHAR(255) NOT NULL,
+    comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_timesheet_events_timesheet ON timesheet_events(timesheet_id, created_at);
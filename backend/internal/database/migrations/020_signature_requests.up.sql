@@ -0,0 +1,20 @@
+-- Migration: 020_signature_requests
+-- Description: Emailed single-use links that let a supervisor without an
+-- account sign off a submitted timesheet, and the attestation they leave.
+
+CREATE TABLE signature_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    timesheet_id UUID NOT NULL REFERENCES timesheets(id) ON DELETE CASCADE,
+    requested_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    supervisor_email VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    -- Attestation, set once when the link is used
+    signed_name VARCHAR(200),
+    signed_ip VARCHAR(64),
+    signed_user_agent TEXT,
+    signed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_signature_requests_timesheet ON signature_requests(timesheet_id, created_at DESC);
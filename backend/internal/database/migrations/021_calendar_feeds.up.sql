@@ -0,0 +1,10 @@
+-- Migration: 021_calendar_feeds
+-- Description: Per-user secret iCalendar feed URLs. Only a SHA-256 hash of
+-- the token is stored, so a leaked database does not leak working URLs.
+
+CREATE TABLE calendar_feeds (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1,26 @@
+-- Migration: 022_session_revisions
+-- Description: User-proposed corrections to completed sessions. Each row keeps
+-- the times it replaces; approved rows are applied to time_sessions.
+
+CREATE TABLE session_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES time_sessions(id) ON DELETE CASCADE,
+    requested_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    old_end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    new_start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    new_end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    justification TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    requires_approval BOOLEAN NOT NULL,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    review_comment TEXT,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_session_revisions_session ON session_revisions(session_id, created_at);
+CREATE INDEX idx_session_revisions_pending ON session_revisions(created_at) WHERE status = 'pending';
+
+-- At most one correction per session waits for review at a time
+CREATE UNIQUE INDEX idx_session_revisions_one_pending ON session_revisions(session_id) WHERE status = 'pending';
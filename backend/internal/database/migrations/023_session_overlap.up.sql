@@ -0,0 +1,62 @@
+-- Migration: 023_session_overlap
+-- Description: A user's sessions may not overlap. Active sessions extend to
+-- infinity; cancelled and voided sessions are ignored. Active sessions already
+-- past their policy's max duration are closed first, the way the auto-closer
+-- would have, and recorded in session_overlap_fixes. Any overlap left after
+-- that fails the migration with the conflicting sessions listed, to be
+-- resolved by hand: nothing is cancelled automatically.
+
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE session_overlap_fixes (
+    session_id UUID PRIMARY KEY REFERENCES time_sessions(id) ON DELETE CASCADE,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Effective max duration: user policy, then role policy, then global, then
+-- the built-in default of 24 hours
+WITH overdue AS (
+    SELECT s.id,
+        s.start_time + make_interval(mins => COALESCE(
+            up.max_duration_minutes, rp.max_duration_minutes, gp.max_duration_minutes, 1440)) AS end_time
+    FROM time_sessions s
+    JOIN users u ON u.id = s.user_id
+    LEFT JOIN session_policies up ON up.scope = 'user' AND up.user_id = u.id
+    LEFT JOIN session_policies rp ON rp.scope = 'role' AND rp.role = u.role
+    LEFT JOIN session_policies gp ON gp.scope = 'global'
+    WHERE s.status = 'active'
+),
+closed AS (
+    UPDATE time_sessions s
+    SET status = 'completed', end_time = o.end_time, end_reason = 'auto_closed'
+    FROM overdue o
+    WHERE s.id = o.id AND o.end_time <= NOW()
+    RETURNING s.id, s.end_time
+)
+INSERT INTO session_overlap_fixes (session_id, end_time)
+SELECT id, end_time FROM closed;
+
+DO $$
+DECLARE
+    conflicts TEXT;
+    total INT;
+BEGIN
+    SELECT count(*), string_agg(format('user %s: %s overlaps %s', a.user_id, a.id, b.id), E'\n')
+    INTO total, conflicts
+    FROM time_sessions a
+    JOIN time_sessions b ON b.user_id = a.user_id AND a.id < b.id
+    WHERE a.status <> 'cancelled' AND b.status <> 'cancelled'
+        AND tstzrange(a.start_time, COALESCE(a.end_time, 'infinity'))
+            && tstzrange(b.start_time, COALESCE(b.end_time, 'infinity'));
+
+    IF total > 0 THEN
+        RAISE EXCEPTION '% pairs of overlapping sessions must be corrected or cancelled before this migration can run', total
+            USING DETAIL = conflicts;
+    END IF;
+END $$;
+
+ALTER TABLE time_sessions ADD CONSTRAINT time_sessions_no_overlap EXCLUDE USING gist (
+    user_id WITH =,
+    tstzrange(start_time, COALESCE(end_time, 'infinity')) WITH &&
+) WHERE (status <> 'cancelled');
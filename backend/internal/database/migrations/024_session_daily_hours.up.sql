@@ -0,0 +1,37 @@
+-- Migration: 024_session_daily_hours
+-- Description: Apportions each non-cancelled session's net time (minus
+-- breaks) to the calendar days it spans in its user's time zone, so a session
+-- across midnight counts toward both days. Active sessions run up to now.
+
+CREATE VIEW session_daily_hours AS
+SELECT
+    s.id AS session_id,
+    s.user_id,
+    s.employer_id,
+    s.status,
+    day.local_date AS day,
+    GREATEST(EXTRACT(EPOCH FROM (span.hi - span.lo)) - COALESCE(breaks.seconds, 0), 0)::BIGINT AS net_seconds
+FROM time_sessions s
+JOIN users u ON u.id = s.user_id
+CROSS JOIN LATERAL (SELECT COALESCE(s.end_time, NOW()) AS end_time) e
+CROSS JOIN LATERAL generate_series(
+    (s.start_time AT TIME ZONE u.timezone)::date,
+    (e.end_time AT TIME ZONE u.timezone)::date,
+    INTERVAL '1 day'
+) AS series(d)
+CROSS JOIN LATERAL (SELECT series.d::date AS local_date) day
+-- The part of the session inside the day; midnights are taken in the user's
+-- zone so DST days are 23 or 25 hours long
+CROSS JOIN LATERAL (
+    SELECT
+        GREATEST(s.start_time, day.local_date::timestamp AT TIME ZONE u.timezone) AS lo,
+        LEAST(e.end_time, (day.local_date + 1)::timestamp AT TIME ZONE u.timezone) AS hi
+) span
+LEFT JOIN LATERAL (
+    SELECT SUM(GREATEST(EXTRACT(EPOCH FROM (
+        LEAST(COALESCE(b.end_time, NOW()), span.hi) - GREATEST(b.start_time, span.lo)
+    )), 0)) AS seconds
+    FROM session_breaks b
+    WHERE b.session_id = s.id
+) breaks ON true
+WHERE s.status <> 'cancelled' AND span.hi > span.lo;
@@ -0,0 +1,50 @@
+-- Migration: 025_session_events
+-- Description: Append-only history of session state changes: who (user,
+-- admin or the system) did what, from which device, and when.
+
+CREATE TABLE session_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES time_sessions(id) ON DELETE CASCADE,
+    event VARCHAR(30) NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(20) NOT NULL CHECK (actor IN ('user', 'admin', 'system')),
+    actor_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    device_id VARCHAR(255),
+    details JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_session_events_session ON session_events(session_id, created_at);
+
+-- Events are never changed once written; they only go with their session.
+-- The actor_user_id foreign key is exempt so deleting a user still works.
+CREATE FUNCTION session_events_append_only() RETURNS trigger AS $$
+BEGIN
+    IF NEW.actor_user_id IS NULL AND OLD.actor_user_id IS NOT NULL
+        AND (NEW.id, NEW.session_id, NEW.event, NEW.from_status, NEW.to_status, NEW.actor,
+             NEW.device_id, NEW.details::text, NEW.created_at)
+        IS NOT DISTINCT FROM
+            (OLD.id, OLD.session_id, OLD.event, OLD.from_status, OLD.to_status, OLD.actor,
+             OLD.device_id, OLD.details::text, OLD.created_at) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'session_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER session_events_no_update
+    BEFORE UPDATE ON session_events
+    FOR EACH ROW EXECUTE FUNCTION session_events_append_only();
+
+-- Sessions that predate the log get a single event for how they came to be
+INSERT INTO session_events (session_id, event, from_status, to_status, actor, actor_user_id, device_id, created_at)
+SELECT id,
+    CASE WHEN end_reason = 'manual' THEN 'recorded' ELSE 'started' END,
+    NULL,
+    CASE WHEN end_reason = 'manual' THEN 'completed' ELSE 'active' END,
+    CASE WHEN device_id = 'server:planned-start' THEN 'system' ELSE 'user' END,
+    CASE WHEN device_id = 'server:planned-start' THEN NULL ELSE user_id END,
+    device_id,
+    created_at
+FROM time_sessions;
@@ -0,0 +1,5 @@
+-- Migration: 026_weekly_reminder_sent
+-- Description: Remember the ISO week each weekly reminder email went out for,
+-- so users get at most one a week.
+
+ALTER TABLE opt_profiles ADD COLUMN reminded_week DATE;
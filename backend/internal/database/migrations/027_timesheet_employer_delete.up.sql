@@ -0,0 +1,14 @@
+-- Migration: 027_timesheet_employer_delete
+-- Description: Stop employer deletion from taking timesheets and their history
+-- with it, and lock approved periods for all of a user's work.
+
+-- NO ACTION rather than RESTRICT: it is checked at the end of the statement,
+-- so deleting a user still cascades through both employers and timesheets.
+ALTER TABLE timesheets DROP CONSTRAINT timesheets_employer_id_fkey;
+ALTER TABLE timesheets ADD CONSTRAINT timesheets_employer_id_fkey
+    FOREIGN KEY (employer_id) REFERENCES employers(id) ON DELETE NO ACTION;
+
+-- Lock checks look for approved periods of the user, whatever the employer
+DROP INDEX idx_timesheets_approved;
+CREATE INDEX idx_timesheets_approved ON timesheets(user_id, period_start, period_end)
+    WHERE status = 'approved';
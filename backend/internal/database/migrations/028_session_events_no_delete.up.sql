@@ -0,0 +1,18 @@
+-- Migration: 028_session_events_no_delete
+-- Description: Events may only be deleted along with their session, by the
+-- cascade from time_sessions (or from users through it). By the time the
+-- cascade reaches an event its session is already gone; any other delete
+-- finds the session still there and is refused.
+
+CREATE FUNCTION session_events_no_delete() RETURNS trigger AS $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM time_sessions WHERE id = OLD.session_id) THEN
+        RAISE EXCEPTION 'session_events is append-only';
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER session_events_no_delete
+    BEFORE DELETE ON session_events
+    FOR EACH ROW EXECUTE FUNCTION session_events_no_delete();
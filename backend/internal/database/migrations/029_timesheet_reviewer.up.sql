@@ -0,0 +1,13 @@
+-- Migration: 029_timesheet_reviewer
+-- Description: The supervisor email a timesheet was submitted to. Only that
+-- address (or an admin) may review it, so changing the employer's supervisor
+-- email afterwards does not hand the review to someone else.
+
+ALTER TABLE timesheets ADD COLUMN reviewer_email VARCHAR(255);
+
+UPDATE timesheets t
+SET reviewer_email = e.supervisor_email
+FROM employers e
+WHERE e.id = t.employer_id AND t.status IN ('submitted', 'approved');
+
+CREATE INDEX idx_timesheets_reviewer ON timesheets(LOWER(reviewer_email)) WHERE status = 'submitted';
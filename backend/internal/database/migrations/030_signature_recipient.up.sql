@@ -0,0 +1,13 @@
+-- Migration: 030_signature_recipient
+-- Description: Flag signature requests sent somewhere other than the
+-- timesheet's reviewer on record (the employer's supervisor email when it was
+-- submitted), so an attestation from such an address can be told apart.
+
+ALTER TABLE signature_requests ADD COLUMN recipient_overridden BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE signature_requests s
+SET recipient_overridden = true
+FROM timesheets t
+JOIN employers e ON e.id = t.employer_id
+WHERE t.id = s.timesheet_id
+    AND LOWER(s.supervisor_email) <> LOWER(COALESCE(NULLIF(t.reviewer_email, ''), e.supervisor_email, ''));
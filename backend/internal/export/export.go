@@ -0,0 +1,60 @@
+// Package export writes tabular data as CSV or XLSX. Rows are written to the
+// underlying writer as they arrive, so large exports are never held in
+// memory.
+package export
+
+import (
+	"errors"
+	"io"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// ContentType returns the MIME type of files in the format.
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Writer writes a header row followed by data rows. Cells may be string,
+// int, int64 or float64; nil leaves a cell empty.
+type Writer interface {
+	WriteRow(cells ...any) error
+	// Close finishes the file. It does not close the underlying writer.
+	Close() error
+}
+
+// New returns a Writer for format that writes the header row first.
+func New(format Format, w io.Writer, sheet string, header ...string) (Writer, error) {
+	var out Writer
+	switch format {
+	case FormatCSV:
+		out = newCSVWriter(w)
+	case FormatXLSX:
+		x, err := newXLSXWriter(w, sheet)
+		if err != nil {
+			return nil, err
+		}
+		out = x
+	default:
+		return nil, ErrUnknownFormat
+	}
+
+	cells := make([]any, len(header))
+	for i, h := range header {
+		cells[i] = h
+	}
+	if err := out.WriteRow(cells...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
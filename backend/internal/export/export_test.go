@@ -0,0 +1,120 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCSV(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := New(FormatCSV, &buf, "ignored", "Date", "Hours", "Note")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := w.WriteRow("2025-03-10", 7.5, "said \"hi\", left"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow("2025-03-11", int64(8), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Date,Hours,Note\n2025-03-10,7.5,\"said \"\"hi\"\", left\"\n2025-03-11,8,\n"
+	if buf.String() != want {
+		t.Errorf("CSV =\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	if _, err := New("pdf", io.Discard, "Sessions", "Date"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("New(pdf) error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+func TestXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := New(FormatXLSX, &buf, "Sessions: March", "Date", "Hours", "Note")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := w.WriteRow("2025-03-10", 7.5, "<b>&</b>"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow("2025-03-11", 8, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("not a zip: %v", err)
+	}
+	parts := map[string]string{}
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(r)
+		r.Close()
+		parts[f.Name] = string(data)
+
+		// Every part must be well-formed XML
+		dec := xml.NewDecoder(bytes.NewReader(data))
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed: %v", f.Name, err)
+			}
+		}
+	}
+
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Sessions March"`) {
+		t.Errorf("sheet name not cleaned: %s", parts["xl/workbook.xml"])
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">Date</t></is></c>`,
+		`<c r="B2"><v>7.5</v></c>`,
+		`&lt;b&gt;&amp;&lt;/b&gt;`,
+		`<row r="3"><c r="A3" t="inlineStr"><is><t xml:space="preserve">2025-03-11</t></is></c><c r="B3"><v>8</v></c></row>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet is missing %s\n%s", want, sheet)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for i, want := range tests {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestSheetName(t *testing.T) {
+	tests := map[string]string{
+		"Sessions":                      "Sessions",
+		"a/b\\c?d*e[f]g:h":              "abcdefgh",
+		"[]":                            "Sheet1",
+		strings.Repeat("é", 40):         strings.Repeat("é", 31),
+		"Documents for 2025 and beyond": "Documents for 2025 and beyond",
+	}
+	for in, want := range tests {
+		if got := sheetName(in); got != want {
+			t.Errorf("sheetName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
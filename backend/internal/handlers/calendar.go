@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarHandler struct {
+	calendarService *services.CalendarService
+}
+
+func NewCalendarHandler(calendarService *services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{calendarService: calendarService}
+}
+
+// GetFeed returns whether the user has a calendar feed and when it was last
+// fetched
+// GET /api/v1/calendar/feed
+func (h *CalendarHandler) GetFeed(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	feed, err := h.calendarService.GetFeed(c.Request.Context(), clerkID)
+	if err != nil {
+		if err == services.ErrCalendarFeedNotFound {
+			c.JSON(http.StatusOK, models.SuccessResponse(nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			"Failed to fetch calendar feed",
+			nil,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(feed))
+}
+
+// RegenerateFeed creates a new secret feed URL, replacing any earlier one
+// POST /api/v1/calendar/feed
+func (h *CalendarHandler) RegenerateFeed(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	feed, err := h.calendarService.RegenerateFeed(c.Request.Context(), clerkID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			"Failed to create calendar feed",
+			nil,
+		))
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse(feed))
+}
+
+// RevokeFeed disables the user's feed URL
+// DELETE /api/v1/calendar/feed
+func (h *CalendarHandler) RevokeFeed(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	if err := h.calendarService.RevokeFeed(c.Request.Context(), clerkID); err != nil {
+		switch err {
+		case services.ErrCalendarFeedNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(
+				models.ErrCodeNotFound,
+				"No calendar feed to revoke",
+				nil,
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+				models.ErrCodeInternal,
+				"Failed to revoke calendar feed",
+				nil,
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(gin.H{"message": "Calendar feed revoked"}))
+}
+
+// Feed serves the iCalendar feed for a secret token; no auth. Feed URLs end
+// in .ics, which is stripped from the token.
+// GET /calendar/:token
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	token := services.CalendarFeedToken(c.Param("token"))
+
+	body, err := h.calendarService.RenderFeed(c.Request.Context(), token)
+	if err != nil {
+		if err == services.ErrCalendarFeedNotFound {
+			c.String(http.StatusNotFound, "Calendar feed not found")
+			return
+		}
+		c.String(http.StatusInternalServerError, "Failed to render calendar feed")
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Header("Content-Disposition", `inline; filename="logbook.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestCalendarFeedURLRoutes checks that the path handed out for a feed
+// reaches the feed route with the token intact.
+func TestCalendarFeedURLRoutes(t *testing.T) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		t.Fatal(err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	for _, target := range []string{services.CalendarFeedPath(token), "/calendar/" + token} {
+		var got string
+		w := serve(func(c *gin.Context) {
+			got = services.CalendarFeedToken(c.Param("token"))
+			c.Status(http.StatusOK)
+		}, http.MethodGet, "/calendar/:token", target, "")
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d, want 200", target, w.Code)
+		}
+		if got != token {
+			t.Errorf("GET %s token = %q, want %q", target, got, token)
+		}
+	}
+}
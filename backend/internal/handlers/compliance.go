@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ComplianceHandler struct {
+	complianceService *services.ComplianceService
+}
+
+func NewComplianceHandler(complianceService *services.ComplianceService) *ComplianceHandler {
+	return &ComplianceHandler{complianceService: complianceService}
+}
+
+// GetOPTStatus returns unemployment days used and remaining under OPT
+// GET /api/v1/compliance/opt
+func (h *ComplianceHandler) GetOPTStatus(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	status, err := h.complianceService.GetOPTStatus(c.Request.Context(), clerkID)
+	if err != nil {
+		switch err {
+		case services.ErrOPTProfileNotSet:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(
+				models.ErrCodeNotFound,
+				"Set your OPT start date and program first",
+				nil,
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+				models.ErrCodeInternal,
+				"Failed to compute OPT status",
+				nil,
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(status))
+}
+
+// SetOPTProfile sets the user's OPT start date and program type
+// PUT /api/v1/compliance/opt/profile
+func (h *ComplianceHandler) SetOPTProfile(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.OPTProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input: start_date (YYYY-MM-DD) and program (standard or stem) are required",
+			err.Error(),
+		))
+		return
+	}
+
+	profile, err := h.complianceService.SetOPTProfile(c.Request.Context(), clerkID, input)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidDate:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
+				models.ErrCodeValidation,
+				"start_date must be YYYY-MM-DD",
+				nil,
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+				models.ErrCodeInternal,
+				"Failed to save OPT profile",
+				nil,
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(profile))
+}
+
+// GetWeeklyCompliance returns hours per ISO week against the 20-hour threshold
+// GET /api/v1/compliance/weekly
+func (h *ComplianceHandler) GetWeeklyCompliance(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var params models.WeeklyComplianceParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid query parameters",
+			err.Error(),
+		))
+		return
+	}
+
+	report, err := h.complianceService.GetWeeklyCompliance(c.Request.Context(), clerkID, params)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidDate:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
+				models.ErrCodeValidation,
+				"from and to must be YYYY-MM-DD",
+				nil,
+			))
+		case services.ErrInvalidDateRange:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
+				models.ErrCodeValidation,
+				"from must be before to and the range at most 104 weeks",
+				nil,
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+				models.ErrCodeInternal,
+				"Failed to compute weekly compliance",
+				nil,
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(report))
+}
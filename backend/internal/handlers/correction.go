@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CorrectionHandler struct {
+	correctionService *services.CorrectionService
+}
+
+func NewCorrectionHandler(correctionService *services.CorrectionService) *CorrectionHandler {
+	return &CorrectionHandler{correctionService: correctionService}
+}
+
+// RequestCorrection proposes new start and end times for a completed
+// session. Recent sessions are corrected at once; older ones wait for an
+// admin.
+// POST /api/v1/sessions/:id/corrections
+func (h *CorrectionHandler) RequestCorrection(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.SessionCorrectionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input: start_time and end_time (ISO 8601 format) and a justification are required",
+			err.Error(),
+		))
+		return
+	}
+
+	revision, err := h.correctionService.RequestCorrection(c.Request.Context(), clerkID, c.Param("id"), input)
+	if err != nil {
+		h.respondError(c, err, "Failed to correct session")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse(revision))
+}
+
+// CorrectEndTime proposes a new end time for a session the server closed
+// automatically. Like any correction it is applied at once for recent
+// sessions and otherwise waits for an admin.
+// PUT /api/v1/sessions/:id/end-time
+func (h *CorrectionHandler) CorrectEndTime(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.CorrectEndTimeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input: end_time is required (ISO 8601 format)",
+			err.Error(),
+		))
+		return
+	}
+
+	revision, err := h.correctionService.CorrectEndTime(c.Request.Context(), clerkID, c.Param("id"), input)
+	if err != nil {
+		h.respondError(c, err, "Failed to correct session")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse(revision))
+}
+
+// ListRevisions returns the correction history of a session
+// GET /api/v1/sessions/:id/revisions
+func (h *CorrectionHandler) ListRevisions(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	revisions, err := h.correctionService.ListRevisions(c.Request.Context(), clerkID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(revisions))
+}
+
+// ListPendingCorrections returns corrections waiting for approval
+// GET /api/v1/admin/corrections
+func (h *CorrectionHandler) ListPendingCorrections(c *gin.Context) {
+	revisions, err := h.correctionService.ListPendingCorrections(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			"Failed to fetch corrections",
+			nil,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(revisions))
+}
+
+// ApproveCorrection applies a pending correction to its session
+// POST /api/v1/admin/corrections/:id/approve
+func (h *CorrectionHandler) ApproveCorrection(c *gin.Context) {
+	h.review(c, h.correctionService.ApproveCorrection, "Failed to approve correction")
+}
+
+// RejectCorrection turns down a pending correction with a comment
+// POST /api/v1/admin/corrections/:id/reject
+func (h *CorrectionHandler) RejectCorrection(c *gin.Context) {
+	h.review(c, h.correctionService.RejectCorrection, "Failed to reject correction")
+}
+
+// review binds the optional review comment and runs an admin action.
+func (h *CorrectionHandler) review(c *gin.Context, action func(ctx context.Context, clerkID string, revisionID string, input models.RevisionReviewInput) (*models.SessionRevision, error), fallback string) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.RevisionReviewInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
+				models.ErrCodeValidation,
+				"Invalid input",
+				err.Error(),
+			))
+			return
+		}
+	}
+
+	revision, err := action(c.Request.Context(), clerkID, c.Param("id"), input)
+	if err != nil {
+		h.respondError(c, err, fallback)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(revision))
+}
+
+func (h *CorrectionHandler) respondError(c *gin.Context, err error, fallback string) {
+	if respondPolicyViolation(c, err) || respondSessionOverlap(c, err) {
+		return
+	}
+	switch err {
+	case services.ErrSessionNotFound:
+		c.JSON(http.StatusNotFound, models.ErrorResponse(models.ErrCodeNotFound, "Session not found", nil))
+	case services.ErrCorrectionNotFound:
+		c.JSON(http.StatusNotFound, models.ErrorResponse(models.ErrCodeNotFound, "Correction not found", nil))
+	case services.ErrUnauthorized:
+		c.JSON(http.StatusForbidden, models.ErrorResponse(
+			models.ErrCodeForbidden,
+			"You don't have permission to access this session",
+			nil,
+		))
+	case services.ErrSessionNotCorrectable, services.ErrSessionNotAutoClosed, services.ErrCorrectionPending,
+		services.ErrCorrectionReviewed, services.ErrCorrectionStale, services.ErrPeriodLocked:
+		c.JSON(http.StatusConflict, models.ErrorResponse(models.ErrCodeConflict, err.Error(), nil))
+	case services.ErrInvalidTimeRange, services.ErrCorrectionUnchanged, services.ErrCorrectionCommentRequired:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(models.ErrCodeValidation, err.Error(), nil))
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(models.ErrCodeInternal, fallback, nil))
+	}
+}
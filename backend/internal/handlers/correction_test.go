@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCorrectEndTimeValidation(t *testing.T) {
+	h := &CorrectionHandler{}
+	for _, body := range []string{
+		`{}`,
+		`{"end_time": "2025-03-10T17:00:00Z", "justification": "` + strings.Repeat("x", 2001) + `"}`,
+	} {
+		w := serve(h.CorrectEndTime, http.MethodPut, "/sessions/:id/end-time",
+			"/sessions/6f1c2a7e-0d7b-4a47-9c55-2f0d8c6b1e11/end-time", body)
+		expectBadRequest(t, w)
+	}
+}
+
+func TestRequestCorrectionValidation(t *testing.T) {
+	h := &CorrectionHandler{}
+	for _, body := range []string{
+		`{}`,
+		`{"start_time": "2025-03-10T09:00:00Z", "end_time": "2025-03-10T17:00:00Z"}`,
+	} {
+		w := serve(h.RequestCorrection, http.MethodPost, "/sessions/:id/corrections",
+			"/sessions/6f1c2a7e-0d7b-4a47-9c55-2f0d8c6b1e11/corrections", body)
+		expectBadRequest(t, w)
+	}
+}
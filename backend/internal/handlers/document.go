@@ -42,6 +42,18 @@ func (h *DocumentHandler) CreateDocument(c *gin.Context) {
 				"Only one document allowed per session, per day",
 				nil,
 			))
+		case services.ErrEmployerNotFound:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
+				models.ErrCodeValidation,
+				"Employer not found",
+				nil,
+			))
+		case services.ErrPeriodLocked:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeConflict,
+				"This period is covered by an approved timesheet",
+				nil,
+			))
 		default:
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
 				models.ErrCodeInternal,
@@ -120,6 +132,18 @@ func (h *DocumentHandler) UpdateDocument(c *gin.Context) {
 				"You don't have permission to modify this document",
 				nil,
 			))
+		case services.ErrEmployerNotFound:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
+				models.ErrCodeValidation,
+				"Employer not found",
+				nil,
+			))
+		case services.ErrPeriodLocked:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeConflict,
+				"This period is covered by an approved timesheet",
+				nil,
+			))
 		default:
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
 				models.ErrCodeInternal,
@@ -188,6 +212,12 @@ func (h *DocumentHandler) DeleteDocument(c *gin.Context) {
 				"You don't have permission to delete this document",
 				nil,
 			))
+		case services.ErrPeriodLocked:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeConflict,
+				"This period is covered by an approved timesheet",
+				nil,
+			))
 		default:
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
 				models.ErrCodeInternal,
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"net/http"
+
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmployerHandler struct {
+	employerService *services.EmployerService
+}
+
+func NewEmployerHandler(employerService *services.EmployerService) *EmployerHandler {
+	return &EmployerHandler{employerService: employerService}
+}
+
+// ListEmployers returns the user's employers, most recent first
+// GET /api/v1/employers
+func (h *EmployerHandler) ListEmployers(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	employers, err := h.employerService.ListEmployers(c.Request.Context(), clerkID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			"Failed to fetch employers",
+			nil,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(employers))
+}
+
+// GetEmployer returns a single employer
+// GET /api/v1/employers/:id
+func (h *EmployerHandler) GetEmployer(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	employerID := c.Param("id")
+
+	employer, err := h.employerService.GetEmployer(c.Request.Context(), clerkID, employerID)
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch employer")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(employer))
+}
+
+// CreateEmployer adds an employer
+// POST /api/v1/employers
+func (h *EmployerHandler) CreateEmployer(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.EmployerInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	employer, err := h.employerService.CreateEmployer(c.Request.Context(), clerkID, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to create employer")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse(employer))
+}
+
+// UpdateEmployer replaces an employer's details
+// PUT /api/v1/employers/:id
+func (h *EmployerHandler) UpdateEmployer(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	employerID := c.Param("id")
+
+	var input models.EmployerInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	employer, err := h.employerService.UpdateEmployer(c.Request.Context(), clerkID, employerID, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to update employer")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(employer))
+}
+
+// DeleteEmployer removes an employer and its draft and rejected timesheets.
+// Sessions and documents attached to it are kept and detached. Employers
+// with submitted or approved timesheets cannot be deleted.
+// DELETE /api/v1/employers/:id
+func (h *EmployerHandler) DeleteEmployer(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	employerID := c.Param("id")
+
+	if err := h.employerService.DeleteEmployer(c.Request.Context(), clerkID, employerID); err != nil {
+		h.respondError(c, err, "Failed to delete employer")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(gin.H{"message": "Employer deleted"}))
+}
+
+func (h *EmployerHandler) respondError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case services.ErrEmployerNotFound:
+		c.JSON(http.StatusNotFound, models.ErrorResponse(
+			models.ErrCodeNotFound,
+			"Employer not found",
+			nil,
+		))
+	case services.ErrUnauthorized:
+		c.JSON(http.StatusForbidden, models.ErrorResponse(
+			models.ErrCodeForbidden,
+			"You don't have permission to access this employer",
+			nil,
+		))
+	case services.ErrInvalidDate:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Dates must be YYYY-MM-DD",
+			nil,
+		))
+	case services.ErrInvalidDateRange:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"end_date must not be before start_date",
+			nil,
+		))
+	case services.ErrEmployerInUse:
+		c.JSON(http.StatusConflict, models.ErrorResponse(
+			models.ErrCodeConflict,
+			"This employer has submitted or approved timesheets and cannot be deleted",
+			nil,
+		))
+	case services.ErrSupervisorLocked:
+		c.JSON(http.StatusConflict, models.ErrorResponse(
+			models.ErrCodeConflict,
+			"The supervisor email cannot change while a timesheet is submitted or approved",
+			nil,
+		))
+	case services.ErrInvalidEIN:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			err.Error(),
+			nil,
+		))
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			fallback,
+			nil,
+		))
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"log_book/internal/export"
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	exportService *services.ExportService
+}
+
+func NewExportHandler(exportService *services.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// ExportSessions downloads the user's sessions as CSV or XLSX
+// GET /api/v1/sessions/export
+func (h *ExportHandler) ExportSessions(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var params models.SessionListParams
+	var exportParams models.ExportParams
+	if !bindExportQuery(c, &params, &exportParams) {
+		return
+	}
+
+	format := export.Format(exportParams.Format)
+	h.stream(c, "sessions", format, func(w io.Writer) error {
+		return h.exportService.ExportSessions(c.Request.Context(), clerkID, params, format, w)
+	})
+}
+
+// ExportDocuments downloads the user's documents as CSV or XLSX, with their
+// content as plain text
+// GET /api/v1/documents/export
+func (h *ExportHandler) ExportDocuments(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var params models.DocumentListParams
+	var exportParams models.ExportParams
+	if !bindExportQuery(c, &params, &exportParams) {
+		return
+	}
+
+	format := export.Format(exportParams.Format)
+	h.stream(c, "documents", format, func(w io.Writer) error {
+		return h.exportService.ExportDocuments(c.Request.Context(), clerkID, params, format, w)
+	})
+}
+
+func bindExportQuery(c *gin.Context, params any, exportParams *models.ExportParams) bool {
+	if err := c.ShouldBindQuery(params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid query parameters",
+			err.Error(),
+		))
+		return false
+	}
+	if err := c.ShouldBindQuery(exportParams); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"format must be csv or xlsx",
+			err.Error(),
+		))
+		return false
+	}
+	return true
+}
+
+// stream sends the export as a download. Errors before the first byte are
+// reported as JSON; after that the status is already sent, so the download is
+// cut short and the error logged. Large exports take longer than the server's
+// write timeout, so it is lifted; the request context still ends the export if
+// the client goes away.
+func (h *ExportHandler) stream(c *gin.Context, resource string, format export.Format, write func(io.Writer) error) {
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Export of %s could not lift the write timeout: %v", resource, err)
+	}
+
+	fileName := fmt.Sprintf("%s-%s.%s", resource, time.Now().UTC().Format("2006-01-02"), format)
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	err := write(c.Writer)
+	if err == nil {
+		return
+	}
+
+	if c.Writer.Written() {
+		log.Printf("Export of %s failed mid-stream: %v", resource, err)
+		c.Abort()
+		return
+	}
+
+	c.Header("Content-Type", "")
+	c.Header("Content-Disposition", "")
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+		models.ErrCodeInternal,
+		fmt.Sprintf("Failed to export %s", resource),
+		nil,
+	))
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"log_book/internal/export"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestStreamOutlivesWriteTimeout(t *testing.T) {
+	h := &ExportHandler{}
+	router := gin.New()
+	router.GET("/export", func(c *gin.Context) {
+		h.stream(c, "sessions", export.FormatCSV, func(w io.Writer) error {
+			io.WriteString(w, "start_time,end_time\n")
+			time.Sleep(150 * time.Millisecond)
+			_, err := io.WriteString(w, "2025-03-10T09:00:00Z,2025-03-10T17:00:00Z\n")
+			return err
+		})
+	})
+
+	srv := httptest.NewUnstartedServer(router)
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/export")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading export: %v", err)
+	}
+	if want := "start_time,end_time\n2025-03-10T09:00:00Z,2025-03-10T17:00:00Z\n"; string(body) != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
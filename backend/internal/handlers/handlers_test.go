@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"log_book/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serve sends body to handler mounted at method path and returns the
+// recorded response.
+func serve(handler gin.HandlerFunc, method, path, target, body string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Handle(method, path, handler)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// errorCode returns the error code of an ErrorResponse body.
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	return resp.Error.Code
+}
+
+// expectBadRequest checks that w is a 400 validation error.
+func expectBadRequest(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400; body %s", w.Code, w.Body.String())
+	}
+	if code := errorCode(t, w); code != models.ErrCodeValidation {
+		t.Errorf("error code = %q, want %s", code, models.ErrCodeValidation)
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileBytes bounds the size of an uploaded import file.
+const maxImportFileBytes = 5 << 20
+
+type ImportHandler struct {
+	importService *services.ImportService
+}
+
+func NewImportHandler(importService *services.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// ImportSessions checks, and in commit mode creates, sessions from a CSV
+// file uploaded as multipart field "file"
+// POST /api/v1/sessions/import
+func (h *ImportHandler) ImportSessions(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.SessionImportInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input: start_column and end_column are required",
+			err.Error(),
+		))
+		return
+	}
+
+	file, ok := openImportFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	result, err := h.importService.ImportSessionsCSV(c.Request.Context(), clerkID, input, file)
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(result))
+}
+
+// ImportTracker checks, and in commit mode creates, sessions from a Toggl or
+// Clockify export uploaded as multipart field "file"
+// POST /api/v1/sessions/import/tracker
+func (h *ImportHandler) ImportTracker(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.TrackerImportInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input: source must be toggl or clockify",
+			err.Error(),
+		))
+		return
+	}
+
+	file, ok := openImportFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	result, err := h.importService.ImportTracker(c.Request.Context(), clerkID, input, file)
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(result))
+}
+
+// openImportFile opens the uploaded multipart field "file", responding with
+// an error when it is missing or too large.
+func openImportFile(c *gin.Context) (multipart.File, bool) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Upload the file as multipart field \"file\"",
+			nil,
+		))
+		return nil, false
+	}
+	if header.Size > maxImportFileBytes {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"File too large. Maximum size is 5MB",
+			nil,
+		))
+		return nil, false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			"Failed to read upload",
+			nil,
+		))
+		return nil, false
+	}
+
+	return file, true
+}
+
+func respondImportError(c *gin.Context, err error) {
+	if respondSessionOverlap(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrInvalidImportFile),
+		errors.Is(err, services.ErrInvalidTrackerExport),
+		errors.Is(err, services.ErrImportEmpty),
+		errors.Is(err, services.ErrImportTooManyRows),
+		errors.Is(err, services.ErrImportColumnNotFound):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			err.Error(),
+			nil,
+		))
+	case errors.Is(err, services.ErrEmployerNotFound):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Employer not found",
+			nil,
+		))
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			"Failed to import sessions",
+			nil,
+		))
+	}
+}
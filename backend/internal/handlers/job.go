@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobHandler struct {
+	jobService *services.JobService
+}
+
+func NewJobHandler(jobService *services.JobService) *JobHandler {
+	return &JobHandler{jobService: jobService}
+}
+
+// ListJobs returns background jobs by status, dead (failed) jobs by default
+// GET /api/v1/admin/jobs
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	var params models.JobListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(models.ErrCodeValidation, "Invalid query parameters", err.Error()))
+		return
+	}
+
+	jobs, total, err := h.jobService.ListJobs(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(models.ErrCodeInternal, "Failed to fetch jobs", nil))
+		return
+	}
+
+	totalPages := (total + params.PerPage - 1) / params.PerPage
+	c.JSON(http.StatusOK, models.SuccessResponseWithPagination(jobs, &models.Pagination{
+		Page:       params.Page,
+		PerPage:    params.PerPage,
+		Total:      total,
+		TotalPages: totalPages,
+	}))
+}
+
+// GetJob returns a job with its run history
+// GET /api/v1/admin/jobs/:id
+func (h *JobHandler) GetJob(c *gin.Context) {
+	job, err := h.jobService.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		switch err {
+		case services.ErrJobNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(models.ErrCodeNotFound, "Job not found", nil))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(models.ErrCodeInternal, "Failed to fetch job", nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(job))
+}
+
+// RetryJob queues a dead job again with a fresh attempt budget
+// POST /api/v1/admin/jobs/:id/retry
+func (h *JobHandler) RetryJob(c *gin.Context) {
+	job, err := h.jobService.RetryJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		switch err {
+		case services.ErrJobNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(models.ErrCodeNotFound, "Job not found", nil))
+		case services.ErrJobNotDead:
+			c.JSON(http.StatusConflict, models.ErrorResponse(models.ErrCodeConflict, "Only dead jobs can be retried", nil))
+		case services.ErrJobAlreadyQueued:
+			c.JSON(http.StatusConflict, models.ErrorResponse(models.ErrCodeConflict, "A job with the same key is already queued", nil))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(models.ErrCodeInternal, "Failed to retry job", nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(job))
+}
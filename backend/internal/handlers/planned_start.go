@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlannedStartHandler struct {
+	plannedStartService *services.PlannedStartService
+}
+
+func NewPlannedStartHandler(plannedStartService *services.PlannedStartService) *PlannedStartHandler {
+	return &PlannedStartHandler{plannedStartService: plannedStartService}
+}
+
+// ListPlannedStarts returns the user's planned shift starts
+// GET /api/v1/schedule/starts
+func (h *PlannedStartHandler) ListPlannedStarts(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	starts, err := h.plannedStartService.ListPlannedStarts(c.Request.Context(), clerkID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			"Failed to fetch planned starts",
+			nil,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(starts))
+}
+
+// CreatePlannedStart adds a one-off or recurring planned shift start
+// POST /api/v1/schedule/starts
+func (h *PlannedStartHandler) CreatePlannedStart(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.PlannedStartInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	start, err := h.plannedStartService.CreatePlannedStart(c.Request.Context(), clerkID, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to create planned start")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse(start))
+}
+
+// UpdatePlannedStart replaces a planned shift start
+// PUT /api/v1/schedule/starts/:id
+func (h *PlannedStartHandler) UpdatePlannedStart(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	startID := c.Param("id")
+
+	var input models.PlannedStartInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	start, err := h.plannedStartService.UpdatePlannedStart(c.Request.Context(), clerkID, startID, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to update planned start")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(start))
+}
+
+// DeletePlannedStart removes a planned shift start
+// DELETE /api/v1/schedule/starts/:id
+func (h *PlannedStartHandler) DeletePlannedStart(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	startID := c.Param("id")
+
+	if err := h.plannedStartService.DeletePlannedStart(c.Request.Context(), clerkID, startID); err != nil {
+		h.respondError(c, err, "Failed to delete planned start")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(gin.H{"message": "Planned start deleted"}))
+}
+
+func (h *PlannedStartHandler) respondError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case services.ErrPlannedStartNotFound:
+		c.JSON(http.StatusNotFound, models.ErrorResponse(
+			models.ErrCodeNotFound,
+			"Planned start not found",
+			nil,
+		))
+	case services.ErrUnauthorized:
+		c.JSON(http.StatusForbidden, models.ErrorResponse(
+			models.ErrCodeForbidden,
+			"You don't have permission to modify this planned start",
+			nil,
+		))
+	case services.ErrInvalidPlannedStart:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Provide either a future start_at (RFC 3339) or days (0-6, Sunday = 0) and start_time (HH:MM)",
+			nil,
+		))
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			fallback,
+			nil,
+		))
+	}
+}
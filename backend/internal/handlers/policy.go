@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PolicyHandler struct {
+	policyService *services.PolicyService
+}
+
+func NewPolicyHandler(policyService *services.PolicyService) *PolicyHandler {
+	return &PolicyHandler{policyService: policyService}
+}
+
+// GetEffectivePolicy returns the session rules that apply to the current user
+// GET /api/v1/time/policy
+func (h *PolicyHandler) GetEffectivePolicy(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	policy, err := h.policyService.GetEffectivePolicy(c.Request.Context(), clerkID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(models.ErrCodeInternal, "Failed to fetch session policy", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(policy))
+}
+
+// ListPolicies returns all stored session policies
+// GET /api/v1/admin/policies
+func (h *PolicyHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.policyService.ListPolicies(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(models.ErrCodeInternal, "Failed to fetch policies", nil))
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse(policies))
+}
+
+// CreatePolicy adds a global, role or user session policy
+// POST /api/v1/admin/policies
+func (h *PolicyHandler) CreatePolicy(c *gin.Context) {
+	var input models.SessionPolicyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(models.ErrCodeValidation, "Invalid input", err.Error()))
+		return
+	}
+
+	policy, err := h.policyService.CreatePolicy(c.Request.Context(), input)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidPolicyScope:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(models.ErrCodeValidation, "Role policies need a role and user policies need a user_id", nil))
+		case services.ErrPolicyDurations:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(models.ErrCodeValidation, "The minimum duration cannot exceed the maximum", nil))
+		case services.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(models.ErrCodeNotFound, "User not found", nil))
+		case services.ErrPolicyExists:
+			c.JSON(http.StatusConflict, models.ErrorResponse(models.ErrCodeConflict, "A policy already exists for this scope", nil))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(models.ErrCodeInternal, "Failed to create policy", nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse(policy))
+}
+
+// UpdatePolicy replaces the rules of an existing policy
+// PUT /api/v1/admin/policies/:id
+func (h *PolicyHandler) UpdatePolicy(c *gin.Context) {
+	var input models.SessionPolicyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(models.ErrCodeValidation, "Invalid input", err.Error()))
+		return
+	}
+
+	policy, err := h.policyService.UpdatePolicy(c.Request.Context(), c.Param("id"), input)
+	if err != nil {
+		switch err {
+		case services.ErrPolicyNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(models.ErrCodeNotFound, "Policy not found", nil))
+		case services.ErrPolicyDurations:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(models.ErrCodeValidation, "The minimum duration cannot exceed the maximum", nil))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(models.ErrCodeInternal, "Failed to update policy", nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(policy))
+}
+
+// DeletePolicy removes a policy so its scope falls back to the next level
+// DELETE /api/v1/admin/policies/:id
+func (h *PolicyHandler) DeletePolicy(c *gin.Context) {
+	err := h.policyService.DeletePolicy(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == services.ErrPolicyNotFound {
+			c.JSON(http.StatusNotFound, models.ErrorResponse(models.ErrCodeNotFound, "Policy not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(models.ErrCodeInternal, "Failed to delete policy", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(gin.H{"message": "Policy deleted"}))
+}
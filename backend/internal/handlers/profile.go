@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler struct {
+	userService *services.UserService
+}
+
+func NewProfileHandler(userService *services.UserService) *ProfileHandler {
+	return &ProfileHandler{userService: userService}
+}
+
+// UpdateProfile updates the current user's name and time zone
+// PATCH /api/v1/me
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.UpdateUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(c.Request.Context(), clerkID, input)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidTimezone:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
+				models.ErrCodeValidation,
+				"Unknown time zone. Use an IANA name such as America/Los_Angeles.",
+				nil,
+			))
+		case services.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(
+				models.ErrCodeNotFound,
+				"User not found",
+				nil,
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+				models.ErrCodeInternal,
+				"Failed to update profile",
+				nil,
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(user))
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportService *services.ReportService
+}
+
+func NewReportHandler(reportService *services.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// TimesheetPDF downloads the user's sessions as a printable timesheet
+// GET /api/v1/reports/timesheet.pdf
+func (h *ReportHandler) TimesheetPDF(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var params models.TimesheetReportParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid query parameters",
+			err.Error(),
+		))
+		return
+	}
+
+	doc, fileName, err := h.reportService.TimesheetPDF(c.Request.Context(), clerkID, params)
+	if err != nil {
+		h.respondError(c, err, "Failed to generate timesheet")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/pdf", doc)
+}
+
+// HoursTotals returns net hours per day, week or month, splitting sessions
+// across midnight between the days they span
+// GET /api/v1/reports/hours
+func (h *ReportHandler) HoursTotals(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var params models.HoursTotalsParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid query parameters",
+			err.Error(),
+		))
+		return
+	}
+
+	report, err := h.reportService.HoursTotals(c.Request.Context(), clerkID, params)
+	if err != nil {
+		h.respondError(c, err, "Failed to total hours")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(report))
+}
+
+func (h *ReportHandler) respondError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case services.ErrInvalidDate:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"from and to must be YYYY-MM-DD",
+			nil,
+		))
+	case services.ErrInvalidDateRange:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"to must not be before from, and the range must be at most a year",
+			nil,
+		))
+	case services.ErrEmployerNotFound:
+		c.JSON(http.StatusNotFound, models.ErrorResponse(
+			models.ErrCodeNotFound,
+			"Employer not found",
+			nil,
+		))
+	case services.ErrReportUnsupportedText:
+		c.JSON(http.StatusUnprocessableEntity, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"A name or address in this timesheet uses characters the PDF cannot show. Use the CSV or XLSX export, or change the name to Latin characters.",
+			nil,
+		))
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			fallback,
+			nil,
+		))
+	}
+}
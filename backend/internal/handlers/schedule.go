@@ -48,6 +48,12 @@ func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
 				"You don't have permission to modify this session",
 				nil,
 			))
+		case services.ErrNoActiveSession:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
+				models.ErrCodeBadRequest,
+				"Session is no longer active",
+				nil,
+			))
 		case services.ErrInvalidScheduleTime:
 			c.JSON(http.StatusBadRequest, models.ErrorResponse(
 				models.ErrCodeBadRequest,
@@ -122,6 +128,12 @@ func (h *ScheduleHandler) CancelSchedule(c *gin.Context) {
 				"You don't have permission to modify this session",
 				nil,
 			))
+		case services.ErrNoActiveSession:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
+				models.ErrCodeBadRequest,
+				"Session is no longer active",
+				nil,
+			))
 		default:
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
 				models.ErrCodeInternal,
@@ -134,3 +146,113 @@ func (h *ScheduleHandler) CancelSchedule(c *gin.Context) {
 
 	c.JSON(http.StatusOK, models.SuccessResponse(gin.H{"message": "Schedule cancelled"}))
 }
+
+// ListRules returns the user's recurring auto-stop rules
+// GET /api/v1/schedule/rules
+func (h *ScheduleHandler) ListRules(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	rules, err := h.scheduleService.ListRules(c.Request.Context(), clerkID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			"Failed to fetch schedule rules",
+			nil,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(rules))
+}
+
+// CreateRule adds a recurring auto-stop rule
+// POST /api/v1/schedule/rules
+func (h *ScheduleHandler) CreateRule(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.ScheduleRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input: days (0-6, Sunday = 0) and stop_time (HH:MM) are required",
+			err.Error(),
+		))
+		return
+	}
+
+	rule, err := h.scheduleService.CreateRule(c.Request.Context(), clerkID, input)
+	if err != nil {
+		h.respondRuleError(c, err, "Failed to create schedule rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse(rule))
+}
+
+// UpdateRule replaces a recurring auto-stop rule
+// PUT /api/v1/schedule/rules/:id
+func (h *ScheduleHandler) UpdateRule(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	ruleID := c.Param("id")
+
+	var input models.ScheduleRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input: days (0-6, Sunday = 0) and stop_time (HH:MM) are required",
+			err.Error(),
+		))
+		return
+	}
+
+	rule, err := h.scheduleService.UpdateRule(c.Request.Context(), clerkID, ruleID, input)
+	if err != nil {
+		h.respondRuleError(c, err, "Failed to update schedule rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(rule))
+}
+
+// DeleteRule removes a recurring auto-stop rule
+// DELETE /api/v1/schedule/rules/:id
+func (h *ScheduleHandler) DeleteRule(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	ruleID := c.Param("id")
+
+	if err := h.scheduleService.DeleteRule(c.Request.Context(), clerkID, ruleID); err != nil {
+		h.respondRuleError(c, err, "Failed to delete schedule rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(gin.H{"message": "Schedule rule deleted"}))
+}
+
+func (h *ScheduleHandler) respondRuleError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case services.ErrScheduleRuleNotFound:
+		c.JSON(http.StatusNotFound, models.ErrorResponse(
+			models.ErrCodeNotFound,
+			"Schedule rule not found",
+			nil,
+		))
+	case services.ErrUnauthorized:
+		c.JSON(http.StatusForbidden, models.ErrorResponse(
+			models.ErrCodeForbidden,
+			"You don't have permission to modify this schedule rule",
+			nil,
+		))
+	case services.ErrInvalidStopTime:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"stop_time must be HH:MM (24-hour)",
+			nil,
+		))
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			fallback,
+			nil,
+		))
+	}
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SignatureHandler struct {
+	signatureService *services.SignatureService
+}
+
+func NewSignatureHandler(signatureService *services.SignatureService) *SignatureHandler {
+	return &SignatureHandler{signatureService: signatureService}
+}
+
+// RequestSignature emails a single-use signing link for a submitted timesheet
+// POST /api/v1/timesheets/:id/signature-requests
+func (h *SignatureHandler) RequestSignature(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.CreateSignatureRequestInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
+				models.ErrCodeValidation,
+				"Invalid input",
+				err.Error(),
+			))
+			return
+		}
+	}
+
+	req, err := h.signatureService.RequestSignature(c.Request.Context(), clerkID, c.Param("id"), input)
+	if err != nil {
+		switch err {
+		case services.ErrTimesheetNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(models.ErrCodeNotFound, "Timesheet not found", nil))
+		case services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, models.ErrorResponse(
+				models.ErrCodeForbidden,
+				"You don't have permission to access this timesheet",
+				nil,
+			))
+		case services.ErrInvalidTimesheetTransition:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeConflict,
+				"Submit the timesheet before requesting a signature",
+				nil,
+			))
+		case services.ErrNoSupervisorEmail, services.ErrSignerIsOwner:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(models.ErrCodeValidation, err.Error(), nil))
+		case services.ErrSigningNotConfigured:
+			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse(models.ErrCodeInternal, err.Error(), nil))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(models.ErrCodeInternal, "Failed to request signature", nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse(req))
+}
+
+// ListSignatureRequests returns a timesheet's signature requests and attestations
+// GET /api/v1/timesheets/:id/signature-requests
+func (h *SignatureHandler) ListSignatureRequests(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	requests, err := h.signatureService.ListSignatureRequests(c.Request.Context(), clerkID, c.Param("id"))
+	if err != nil {
+		switch err {
+		case services.ErrTimesheetNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(models.ErrCodeNotFound, "Timesheet not found", nil))
+		case services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, models.ErrorResponse(
+				models.ErrCodeForbidden,
+				"You don't have permission to access this timesheet",
+				nil,
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(models.ErrCodeInternal, "Failed to fetch signature requests", nil))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(requests))
+}
+
+// SigningPage shows the timesheet behind a signing link (no auth)
+// GET /sign/:token
+func (h *SignatureHandler) SigningPage(c *gin.Context) {
+	page, err := h.signatureService.GetSigningPage(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+
+	h.render(c, http.StatusOK, signingPageData(page, ""))
+}
+
+// Sign records the supervisor's attestation and approves the timesheet (no auth)
+// POST /sign/:token
+func (h *SignatureHandler) Sign(c *gin.Context) {
+	token := c.Param("token")
+
+	var input models.SignInput
+	_ = c.ShouldBind(&input)
+
+	err := h.signatureService.Sign(c.Request.Context(), token, input, c.ClientIP(), c.Request.UserAgent())
+	if err == services.ErrSignatureConfirmation || err == services.ErrSignerNameTooLong {
+		// Show the form again with the reason
+		page, pageErr := h.signatureService.GetSigningPage(c.Request.Context(), token)
+		if pageErr != nil {
+			h.renderError(c, pageErr)
+			return
+		}
+		h.render(c, http.StatusBadRequest, signingPageData(page, err.Error()))
+		return
+	}
+	if err != nil {
+		h.renderError(c, err)
+		return
+	}
+
+	h.render(c, http.StatusOK, signingView{
+		Title:   "Thank you",
+		Message: "Your sign-off has been recorded. You can close this page.",
+	})
+}
+
+func (h *SignatureHandler) renderError(c *gin.Context, err error) {
+	if err == services.ErrSignatureLinkInvalid {
+		h.render(c, http.StatusNotFound, signingView{
+			Title:   "Link unavailable",
+			Message: "This signing link is invalid, has expired or has already been used. Ask for a new link if you still need to sign.",
+		})
+		return
+	}
+
+	log.Printf("Signing page error: %v", err)
+	h.render(c, http.StatusInternalServerError, signingView{
+		Title:   "Something went wrong",
+		Message: "Please try again later.",
+	})
+}
+
+func (h *SignatureHandler) render(c *gin.Context, status int, view signingView) {
+	var buf bytes.Buffer
+	if err := signingTemplate.Execute(&buf, view); err != nil {
+		log.Printf("Failed to render signing page: %v", err)
+		c.String(http.StatusInternalServerError, "Something went wrong")
+		return
+	}
+
+	// Links are bearer credentials; keep them out of caches and referrers
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+type signingView struct {
+	Title        string
+	Message      string
+	Error        string
+	UserName     string
+	EmployerName string
+	Period       string
+	TimeZone     string
+	Rows         []signingRow
+	TotalHours   string
+	ExpiresAt    string
+	ShowForm     bool
+}
+
+type signingRow struct {
+	Date  string
+	Start string
+	End   string
+	Hours string
+}
+
+func signingPageData(page *models.SigningPage, formError string) signingView {
+	loc := page.Location
+	view := signingView{
+		Title:        "Confirm hours worked",
+		Error:        formError,
+		UserName:     page.UserName,
+		EmployerName: page.EmployerName,
+		Period: fmt.Sprintf("%s to %s",
+			page.Timesheet.PeriodStart.Format("Mon, Jan 2, 2006"),
+			page.Timesheet.PeriodEnd.Format("Mon, Jan 2, 2006")),
+		TimeZone:   loc.String(),
+		TotalHours: formatHours(page.Timesheet.TotalSeconds),
+		ExpiresAt:  page.Request.ExpiresAt.In(loc).Format("Jan 2, 2006 15:04 MST"),
+		ShowForm:   true,
+	}
+
+	for _, s := range page.Timesheet.Sessions {
+		row := signingRow{
+			Date:  s.StartTime.In(loc).Format("Mon, Jan 2"),
+			Start: s.StartTime.In(loc).Format("15:04"),
+			Hours: formatHours(s.NetSeconds),
+		}
+		if s.EndTime != nil {
+			row.End = s.EndTime.In(loc).Format("15:04")
+		}
+		view.Rows = append(view.Rows, row)
+	}
+
+	return view
+}
+
+func formatHours(seconds int64) string {
+	return fmt.Sprintf("%.2f", float64(seconds)/3600)
+}
+
+var signingTemplate = template.Must(template.New("signing").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}} - LogBook</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 42rem; margin: 2rem auto; padding: 0 1rem; color: #1f2937; }
+table { width: 100%; border-collapse: collapse; margin: 1rem 0; }
+th, td { text-align: left; padding: .4rem; border-bottom: 1px solid #e5e7eb; }
+td.num, th.num { text-align: right; }
+.error { color: #b91c1c; }
+.muted { color: #6b7280; font-size: .9rem; }
+input[type=text] { width: 100%; padding: .5rem; margin: .25rem 0 1rem; box-sizing: border-box; }
+button { padding: .6rem 1.2rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Message}}<p>{{.Message}}</p>{{end}}
+{{if .ShowForm}}
+<p><strong>{{.UserName}}</strong> asks you to confirm the hours they worked at <strong>{{.EmployerName}}</strong> from {{.Period}}.</p>
+<table>
+<thead><tr><th>Date</th><th>Start</th><th>End</th><th class="num">Hours</th></tr></thead>
+<tbody>
+{{range .Rows}}<tr><td>{{.Date}}</td><td>{{.Start}}</td><td>{{.End}}</td><td class="num">{{.Hours}}</td></tr>
+{{else}}<tr><td colspan="4">No sessions were recorded in this period.</td></tr>
+{{end}}
+</tbody>
+<tfoot><tr><th colspan="3">Total</th><th class="num">{{.TotalHours}}</th></tr></tfoot>
+</table>
+<p class="muted">Times are shown in {{.TimeZone}}. This link can be used once and expires {{.ExpiresAt}}.</p>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<form method="post">
+<label for="name">Your full name</label>
+<input type="text" id="name" name="name" maxlength="200" required autocomplete="name">
+<label><input type="checkbox" name="confirm" value="true" required> I supervised this work and confirm the hours above are accurate.</label>
+<p class="muted">Your name, IP address and the time of signing are recorded.</p>
+<button type="submit">Sign</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
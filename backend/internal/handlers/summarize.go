@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -55,10 +56,7 @@ func (h *SummarizeHandler) Summarize(c *gin.Context) {
 		errMsg := err.Error()
 		if strings.HasPrefix(errMsg, "RATE_LIMITED:") {
 			// Send rate limit as a structured SSE error the frontend can parse
-			fmt.Fprintf(c.Writer, "data: {\"error\":\"%s\"}\n\n", strings.TrimPrefix(errMsg, "RATE_LIMITED: "))
-			flusher.Flush()
-			fmt.Fprintf(c.Writer, "data: [DONE]\n\n")
-			flusher.Flush()
+			writeSSEError(c, flusher, strings.TrimPrefix(errMsg, "RATE_LIMITED: "))
 			return
 		}
 		c.SSEvent("error", errMsg)
@@ -66,6 +64,59 @@ func (h *SummarizeHandler) Summarize(c *gin.Context) {
 	}
 }
 
+// DraftEvaluation streams an AI-drafted I-983 self-evaluation built from the
+// log entries tagged to the employer's training goals. :type is
+// twelve_month or final.
+// GET /api/v1/employers/:id/evaluations/:type/draft
+func (h *SummarizeHandler) DraftEvaluation(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	employerID := c.Param("id")
+	evalType := c.Param("type")
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			"Streaming not supported",
+			nil,
+		))
+		return
+	}
+
+	err := h.summarizeService.StreamEvaluationDraft(c.Request.Context(), clerkID, employerID, evalType, c.Writer, flusher)
+	if err != nil {
+		switch err {
+		case services.ErrEmployerNotFound, services.ErrUnauthorized:
+			writeSSEError(c, flusher, "Employer not found")
+			return
+		case services.ErrInvalidEvaluationType, services.ErrEvaluationNotApplicable:
+			writeSSEError(c, flusher, err.Error())
+			return
+		}
+
+		errMsg := err.Error()
+		if strings.HasPrefix(errMsg, "RATE_LIMITED:") {
+			writeSSEError(c, flusher, strings.TrimPrefix(errMsg, "RATE_LIMITED: "))
+			return
+		}
+		c.SSEvent("error", errMsg)
+		flusher.Flush()
+	}
+}
+
+// writeSSEError sends msg as a structured SSE error followed by [DONE].
+func writeSSEError(c *gin.Context, flusher http.Flusher, msg string) {
+	payload, _ := json.Marshal(gin.H{"error": msg})
+	fmt.Fprintf(c.Writer, "data: %s\n\n", payload)
+	flusher.Flush()
+	fmt.Fprintf(c.Writer, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
 // GetQuota returns the user's remaining AI summarize quota for the current month.
 // GET /api/v1/documents/summarize/quota
 func (h *SummarizeHandler) GetQuota(c *gin.Context) {
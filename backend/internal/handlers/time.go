@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"log_book/internal/middleware"
@@ -28,8 +29,11 @@ func (h *TimeHandler) StartSession(c *gin.Context) {
 		// Input is optional, continue with empty
 	}
 
-	session, err := h.timeService.StartSession(c.Request.Context(), clerkID, input.DeviceID)
+	session, err := h.timeService.StartSession(c.Request.Context(), clerkID, input)
 	if err != nil {
+		if respondPolicyViolation(c, err) || respondSessionOverlap(c, err) {
+			return
+		}
 		switch err {
 		case services.ErrSessionAlreadyActive:
 			c.JSON(http.StatusConflict, models.ErrorResponse(
@@ -37,10 +41,10 @@ func (h *TimeHandler) StartSession(c *gin.Context) {
 				"You already have an active session",
 				nil,
 			))
-		case services.ErrSessionExistsForDate:
-			c.JSON(http.StatusConflict, models.ErrorResponse(
+		case services.ErrEmployerNotFound:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
 				models.ErrCodeValidation,
-				"A session already exists for today. Only one session per day is allowed.",
+				"Employer not found",
 				nil,
 			))
 		default:
@@ -71,8 +75,11 @@ func (h *TimeHandler) StopSession(c *gin.Context) {
 		return
 	}
 
-	session, err := h.timeService.StopSession(c.Request.Context(), clerkID, input.SessionID)
+	session, err := h.timeService.StopSession(c.Request.Context(), clerkID, input)
 	if err != nil {
+		if respondPolicyViolation(c, err) {
+			return
+		}
 		switch err {
 		case services.ErrSessionNotFound:
 			c.JSON(http.StatusNotFound, models.ErrorResponse(
@@ -92,16 +99,274 @@ func (h *TimeHandler) StopSession(c *gin.Context) {
 				"You don't have permission to stop this session",
 				nil,
 			))
-		case services.ErrSessionTooShort:
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+				models.ErrCodeInternal,
+				"Failed to stop session",
+				nil,
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(session))
+}
+
+// PauseSession starts a break on an active session
+// POST /api/v1/time/pause
+func (h *TimeHandler) PauseSession(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.PauseSessionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	session, err := h.timeService.PauseSession(c.Request.Context(), clerkID, input.SessionID)
+	if err != nil {
+		switch err {
+		case services.ErrSessionNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(
+				models.ErrCodeNotFound,
+				"Session not found",
+				nil,
+			))
+		case services.ErrNoActiveSession:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeNoActiveSession,
+				"No active session to pause",
+				nil,
+			))
+		case services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, models.ErrorResponse(
+				models.ErrCodeForbidden,
+				"You don't have permission to pause this session",
+				nil,
+			))
+		case services.ErrSessionPaused:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeConflict,
+				"Session is already paused",
+				nil,
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+				models.ErrCodeInternal,
+				"Failed to pause session",
+				nil,
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(session))
+}
+
+// ResumeSession ends the current break on a paused session
+// POST /api/v1/time/resume
+func (h *TimeHandler) ResumeSession(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.ResumeSessionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	session, err := h.timeService.ResumeSession(c.Request.Context(), clerkID, input.SessionID)
+	if err != nil {
+		switch err {
+		case services.ErrSessionNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(
+				models.ErrCodeNotFound,
+				"Session not found",
+				nil,
+			))
+		case services.ErrNoActiveSession:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeNoActiveSession,
+				"No active session to resume",
+				nil,
+			))
+		case services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, models.ErrorResponse(
+				models.ErrCodeForbidden,
+				"You don't have permission to resume this session",
+				nil,
+			))
+		case services.ErrSessionNotPaused:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeConflict,
+				"Session is not paused",
+				nil,
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+				models.ErrCodeInternal,
+				"Failed to resume session",
+				nil,
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(session))
+}
+
+// Heartbeat records that the device running a session is still alive
+// POST /api/v1/time/heartbeat
+func (h *TimeHandler) Heartbeat(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.HeartbeatInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	session, err := h.timeService.RecordHeartbeat(c.Request.Context(), clerkID, input)
+	if err != nil {
+		switch err {
+		case services.ErrSessionNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(
+				models.ErrCodeNotFound,
+				"Session not found",
+				nil,
+			))
+		case services.ErrNoActiveSession:
 			c.JSON(http.StatusBadRequest, models.ErrorResponse(
-				models.ErrCodeValidation,
-				"Session must run for at least 4 hours before stopping.",
+				models.ErrCodeNoActiveSession,
+				"Session is no longer active",
+				nil,
+			))
+		case services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, models.ErrorResponse(
+				models.ErrCodeForbidden,
+				"You don't have permission to update this session",
 				nil,
 			))
 		default:
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
 				models.ErrCodeInternal,
-				"Failed to stop session",
+				"Failed to record heartbeat",
+				nil,
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(session))
+}
+
+// CancelSession discards an accidentally started active session
+// POST /api/v1/time/cancel
+func (h *TimeHandler) CancelSession(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.CancelSessionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	session, err := h.timeService.CancelSession(c.Request.Context(), clerkID, input)
+	if err != nil {
+		switch err {
+		case services.ErrSessionNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(
+				models.ErrCodeNotFound,
+				"Session not found",
+				nil,
+			))
+		case services.ErrNoActiveSession:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
+				models.ErrCodeNoActiveSession,
+				"No active session to cancel",
+				nil,
+			))
+		case services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, models.ErrorResponse(
+				models.ErrCodeForbidden,
+				"You don't have permission to cancel this session",
+				nil,
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+				models.ErrCodeInternal,
+				"Failed to cancel session",
+				nil,
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(session))
+}
+
+// VoidSession cancels a completed session but keeps it for audit
+// POST /api/v1/sessions/:id/void
+func (h *TimeHandler) VoidSession(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	sessionID := c.Param("id")
+
+	var input models.VoidSessionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"A reason is required to void a session",
+			err.Error(),
+		))
+		return
+	}
+
+	session, err := h.timeService.VoidSession(c.Request.Context(), clerkID, sessionID, input)
+	if err != nil {
+		switch err {
+		case services.ErrSessionNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(
+				models.ErrCodeNotFound,
+				"Session not found",
+				nil,
+			))
+		case services.ErrSessionNotCompleted:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeConflict,
+				"Only completed sessions can be voided",
+				nil,
+			))
+		case services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, models.ErrorResponse(
+				models.ErrCodeForbidden,
+				"You don't have permission to void this session",
+				nil,
+			))
+		case services.ErrPeriodLocked:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeConflict,
+				"This period is covered by an approved timesheet",
+				nil,
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+				models.ErrCodeInternal,
+				"Failed to void session",
 				nil,
 			))
 		}
@@ -128,6 +393,9 @@ func (h *TimeHandler) CreateManualSession(c *gin.Context) {
 
 	session, err := h.timeService.CreateManualSession(c.Request.Context(), clerkID, input)
 	if err != nil {
+		if respondPolicyViolation(c, err) || respondSessionOverlap(c, err) {
+			return
+		}
 		switch err {
 		case services.ErrInvalidTimeRange:
 			c.JSON(http.StatusBadRequest, models.ErrorResponse(
@@ -135,28 +403,16 @@ func (h *TimeHandler) CreateManualSession(c *gin.Context) {
 				"End time must be after start time",
 				nil,
 			))
-		case services.ErrSessionTooShort:
-			c.JSON(http.StatusBadRequest, models.ErrorResponse(
-				models.ErrCodeValidation,
-				"Session must be at least 4 hours.",
-				nil,
-			))
-		case services.ErrSessionTooLong:
+		case services.ErrEmployerNotFound:
 			c.JSON(http.StatusBadRequest, models.ErrorResponse(
 				models.ErrCodeValidation,
-				"Session duration cannot exceed 24 hours",
+				"Employer not found",
 				nil,
 			))
-		case services.ErrFutureEndTime:
-			c.JSON(http.StatusBadRequest, models.ErrorResponse(
-				models.ErrCodeValidation,
-				"End time cannot be in the future",
-				nil,
-			))
-		case services.ErrSessionExistsForDate:
+		case services.ErrPeriodLocked:
 			c.JSON(http.StatusConflict, models.ErrorResponse(
-				models.ErrCodeValidation,
-				"A session already exists for this date. Only one session per day is allowed.",
+				models.ErrCodeConflict,
+				"This period is covered by an approved timesheet",
 				nil,
 			))
 		default:
@@ -227,3 +483,74 @@ func (h *TimeHandler) GetActiveSession(c *gin.Context) {
 
 	c.JSON(http.StatusOK, models.SuccessResponse(session))
 }
+
+// ListSessionEvents returns the history of a session: who started, stopped,
+// scheduled or edited it, from which device and when
+// GET /api/v1/sessions/:id/events
+func (h *TimeHandler) ListSessionEvents(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	events, err := h.timeService.ListEvents(c.Request.Context(), clerkID, c.Param("id"))
+	if err != nil {
+		switch err {
+		case services.ErrSessionNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse(
+				models.ErrCodeNotFound,
+				"Session not found",
+				nil,
+			))
+		case services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, models.ErrorResponse(
+				models.ErrCodeForbidden,
+				"You don't have permission to access this session",
+				nil,
+			))
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+				models.ErrCodeInternal,
+				"Failed to fetch session events",
+				nil,
+			))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(events))
+}
+
+// respondPolicyViolation writes a POLICY_VIOLATION response naming the rule
+// that blocked the action. It reports whether err was a policy violation.
+func respondPolicyViolation(c *gin.Context, err error) bool {
+	var violation *services.PolicyViolationError
+	if !errors.As(err, &violation) {
+		return false
+	}
+
+	status := http.StatusBadRequest
+	if violation.Rule == services.PolicyRuleSessionsPerDay {
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, models.ErrorResponse(
+		models.ErrCodePolicyViolation,
+		violation.Message,
+		gin.H{"rule": violation.Rule},
+	))
+	return true
+}
+
+// respondSessionOverlap writes a 409 naming the sessions in the way if err is
+// a SessionOverlapError. Returns false otherwise.
+func respondSessionOverlap(c *gin.Context, err error) bool {
+	var overlap *services.SessionOverlapError
+	if !errors.As(err, &overlap) {
+		return false
+	}
+
+	c.JSON(http.StatusConflict, models.ErrorResponse(
+		models.ErrCodeSessionOverlap,
+		"This session overlaps another of your sessions",
+		gin.H{"session_ids": overlap.SessionIDs},
+	))
+	return true
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// The handlers below reject bad input before reaching the service, so a
+// handler without one is enough.
+
+func TestCancelSessionValidation(t *testing.T) {
+	h := &TimeHandler{}
+	for _, body := range []string{
+		`{}`,
+		`{"session_id": "not-a-uuid"}`,
+		`{"session_id": "6f1c2a7e-0d7b-4a47-9c55-2f0d8c6b1e11", "reason": "` + strings.Repeat("x", 501) + `"}`,
+	} {
+		expectBadRequest(t, serve(h.CancelSession, http.MethodPost, "/time/cancel", "/time/cancel", body))
+	}
+}
+
+func TestVoidSessionRequiresReason(t *testing.T) {
+	h := &TimeHandler{}
+	for _, body := range []string{`{}`, `{"reason": ""}`} {
+		w := serve(h.VoidSession, http.MethodPost, "/sessions/:id/void",
+			"/sessions/6f1c2a7e-0d7b-4a47-9c55-2f0d8c6b1e11/void", body)
+		expectBadRequest(t, w)
+	}
+}
+
+func TestHeartbeatRequiresSessionID(t *testing.T) {
+	h := &TimeHandler{}
+	for _, body := range []string{`{}`, `{"session_id": "abc"}`} {
+		expectBadRequest(t, serve(h.Heartbeat, http.MethodPost, "/time/heartbeat", "/time/heartbeat", body))
+	}
+}
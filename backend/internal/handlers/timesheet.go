@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TimesheetHandler struct {
+	timesheetService *services.TimesheetService
+}
+
+func NewTimesheetHandler(timesheetService *services.TimesheetService) *TimesheetHandler {
+	return &TimesheetHandler{timesheetService: timesheetService}
+}
+
+// CreateTimesheet opens a draft timesheet for a week or month of an
+// employer's sessions
+// POST /api/v1/timesheets
+func (h *TimesheetHandler) CreateTimesheet(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.CreateTimesheetInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	timesheet, err := h.timesheetService.CreateTimesheet(c.Request.Context(), clerkID, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to create timesheet")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse(timesheet))
+}
+
+// ListTimesheets returns the user's timesheets
+// GET /api/v1/timesheets
+func (h *TimesheetHandler) ListTimesheets(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	timesheets, err := h.timesheetService.ListTimesheets(c.Request.Context(), clerkID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			"Failed to fetch timesheets",
+			nil,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(timesheets))
+}
+
+// ListPendingReviews returns submitted timesheets the caller supervises
+// GET /api/v1/timesheets/reviews
+func (h *TimesheetHandler) ListPendingReviews(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	timesheets, err := h.timesheetService.ListPendingReviews(c.Request.Context(), clerkID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			"Failed to fetch timesheets",
+			nil,
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(timesheets))
+}
+
+// GetTimesheet returns a timesheet with its sessions and history
+// GET /api/v1/timesheets/:id
+func (h *TimesheetHandler) GetTimesheet(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	timesheetID := c.Param("id")
+
+	timesheet, err := h.timesheetService.GetTimesheet(c.Request.Context(), clerkID, timesheetID)
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch timesheet")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(timesheet))
+}
+
+// SubmitTimesheet sends a timesheet to the supervisor for review
+// POST /api/v1/timesheets/:id/submit
+func (h *TimesheetHandler) SubmitTimesheet(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	timesheet, err := h.timesheetService.SubmitTimesheet(c.Request.Context(), clerkID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "Failed to submit timesheet")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(timesheet))
+}
+
+// WithdrawTimesheet takes a submitted timesheet back to draft
+// POST /api/v1/timesheets/:id/withdraw
+func (h *TimesheetHandler) WithdrawTimesheet(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+
+	timesheet, err := h.timesheetService.WithdrawTimesheet(c.Request.Context(), clerkID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "Failed to withdraw timesheet")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(timesheet))
+}
+
+// ApproveTimesheet approves a submitted timesheet (supervisor)
+// POST /api/v1/timesheets/:id/approve
+func (h *TimesheetHandler) ApproveTimesheet(c *gin.Context) {
+	h.review(c, h.timesheetService.ApproveTimesheet, "Failed to approve timesheet")
+}
+
+// RejectTimesheet rejects a submitted timesheet with a comment (supervisor)
+// POST /api/v1/timesheets/:id/reject
+func (h *TimesheetHandler) RejectTimesheet(c *gin.Context) {
+	h.review(c, h.timesheetService.RejectTimesheet, "Failed to reject timesheet")
+}
+
+// ReopenTimesheet returns an approved timesheet to draft (supervisor)
+// POST /api/v1/timesheets/:id/reopen
+func (h *TimesheetHandler) ReopenTimesheet(c *gin.Context) {
+	h.review(c, h.timesheetService.ReopenTimesheet, "Failed to reopen timesheet")
+}
+
+// review binds the optional review comment and runs a supervisor action.
+func (h *TimesheetHandler) review(c *gin.Context, action func(ctx context.Context, clerkID string, timesheetID string, input models.TimesheetReviewInput) (*models.Timesheet, error), fallback string) {
+	clerkID := middleware.GetClerkID(c)
+
+	var input models.TimesheetReviewInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse(
+				models.ErrCodeValidation,
+				"Invalid input",
+				err.Error(),
+			))
+			return
+		}
+	}
+
+	timesheet, err := action(c.Request.Context(), clerkID, c.Param("id"), input)
+	if err != nil {
+		h.respondError(c, err, fallback)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(timesheet))
+}
+
+func (h *TimesheetHandler) respondError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case services.ErrTimesheetNotFound:
+		c.JSON(http.StatusNotFound, models.ErrorResponse(models.ErrCodeNotFound, "Timesheet not found", nil))
+	case services.ErrEmployerNotFound:
+		c.JSON(http.StatusNotFound, models.ErrorResponse(models.ErrCodeNotFound, "Employer not found", nil))
+	case services.ErrUnauthorized:
+		c.JSON(http.StatusForbidden, models.ErrorResponse(
+			models.ErrCodeForbidden,
+			"You don't have permission to access this timesheet",
+			nil,
+		))
+	case services.ErrTimesheetExists, services.ErrInvalidTimesheetTransition:
+		c.JSON(http.StatusConflict, models.ErrorResponse(models.ErrCodeConflict, err.Error(), nil))
+	case services.ErrInvalidDate, services.ErrInvalidTimesheetPeriod, services.ErrReviewCommentRequired:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(models.ErrCodeValidation, err.Error(), nil))
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(models.ErrCodeInternal, fallback, nil))
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"net/http"
+
+	"log_book/internal/middleware"
+	"log_book/internal/models"
+	"log_book/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrainingPlanHandler struct {
+	trainingPlanService *services.TrainingPlanService
+}
+
+func NewTrainingPlanHandler(trainingPlanService *services.TrainingPlanService) *TrainingPlanHandler {
+	return &TrainingPlanHandler{trainingPlanService: trainingPlanService}
+}
+
+// GetTrainingPlan returns an employer's I-983 goals and evaluation deadlines
+// GET /api/v1/employers/:id/training-plan
+func (h *TrainingPlanHandler) GetTrainingPlan(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	employerID := c.Param("id")
+
+	plan, err := h.trainingPlanService.GetTrainingPlan(c.Request.Context(), clerkID, employerID)
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch training plan")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(plan))
+}
+
+// CreateGoal adds a goal to an employer's training plan
+// POST /api/v1/employers/:id/goals
+func (h *TrainingPlanHandler) CreateGoal(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	employerID := c.Param("id")
+
+	var input models.TrainingGoalInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	goal, err := h.trainingPlanService.CreateGoal(c.Request.Context(), clerkID, employerID, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to create training goal")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse(goal))
+}
+
+// UpdateGoal replaces a training goal's title and description
+// PUT /api/v1/training-goals/:id
+func (h *TrainingPlanHandler) UpdateGoal(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	goalID := c.Param("id")
+
+	var input models.TrainingGoalInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	goal, err := h.trainingPlanService.UpdateGoal(c.Request.Context(), clerkID, goalID, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to update training goal")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(goal))
+}
+
+// DeleteGoal removes a training goal. Tagged documents are kept.
+// DELETE /api/v1/training-goals/:id
+func (h *TrainingPlanHandler) DeleteGoal(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	goalID := c.Param("id")
+
+	if err := h.trainingPlanService.DeleteGoal(c.Request.Context(), clerkID, goalID); err != nil {
+		h.respondError(c, err, "Failed to delete training goal")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(gin.H{"message": "Training goal deleted"}))
+}
+
+// GetDocumentGoals returns the training goals a document is tagged with
+// GET /api/v1/documents/:id/goals
+func (h *TrainingPlanHandler) GetDocumentGoals(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	docID := c.Param("id")
+
+	goalIDs, err := h.trainingPlanService.GetDocumentGoals(c.Request.Context(), clerkID, docID)
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch document goals")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(gin.H{"goal_ids": goalIDs}))
+}
+
+// SetDocumentGoals replaces the training goals a document is tagged with
+// PUT /api/v1/documents/:id/goals
+func (h *TrainingPlanHandler) SetDocumentGoals(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	docID := c.Param("id")
+
+	var input models.DocumentGoalsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			"Invalid input",
+			err.Error(),
+		))
+		return
+	}
+
+	goalIDs, err := h.trainingPlanService.SetDocumentGoals(c.Request.Context(), clerkID, docID, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to update document goals")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(gin.H{"goal_ids": goalIDs}))
+}
+
+// SubmitEvaluation records that an I-983 evaluation was filed
+// POST /api/v1/employers/:id/evaluations/:type/submit
+func (h *TrainingPlanHandler) SubmitEvaluation(c *gin.Context) {
+	clerkID := middleware.GetClerkID(c)
+	employerID := c.Param("id")
+	evalType := c.Param("type")
+
+	var input models.SubmitEvaluationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		// Input is optional, default to today
+	}
+
+	deadlines, err := h.trainingPlanService.SubmitEvaluation(c.Request.Context(), clerkID, employerID, evalType, input)
+	if err != nil {
+		h.respondError(c, err, "Failed to record evaluation")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse(deadlines))
+}
+
+func (h *TrainingPlanHandler) respondError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case services.ErrEmployerNotFound:
+		c.JSON(http.StatusNotFound, models.ErrorResponse(
+			models.ErrCodeNotFound,
+			"Employer not found",
+			nil,
+		))
+	case services.ErrTrainingGoalNotFound:
+		c.JSON(http.StatusNotFound, models.ErrorResponse(
+			models.ErrCodeNotFound,
+			"Training goal not found",
+			nil,
+		))
+	case services.ErrDocumentNotFound:
+		c.JSON(http.StatusNotFound, models.ErrorResponse(
+			models.ErrCodeNotFound,
+			"Document not found",
+			nil,
+		))
+	case services.ErrUnauthorized:
+		c.JSON(http.StatusForbidden, models.ErrorResponse(
+			models.ErrCodeForbidden,
+			"You don't have permission to access this training plan",
+			nil,
+		))
+	case services.ErrPeriodLocked:
+		c.JSON(http.StatusConflict, models.ErrorResponse(
+			models.ErrCodeConflict,
+			"This period is covered by an approved timesheet",
+			nil,
+		))
+	case services.ErrInvalidEvaluationType, services.ErrEvaluationNotApplicable, services.ErrInvalidDate:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse(
+			models.ErrCodeValidation,
+			err.Error(),
+			nil,
+		))
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse(
+			models.ErrCodeInternal,
+			fallback,
+			nil,
+		))
+	}
+}
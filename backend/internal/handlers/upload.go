@@ -109,6 +109,12 @@ func (h *UploadHandler) ConfirmUpload(c *gin.Context) {
 				"You don't have permission to add media to this document",
 				nil,
 			))
+		case services.ErrPeriodLocked:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeConflict,
+				"This period is covered by an approved timesheet",
+				nil,
+			))
 		default:
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
 				models.ErrCodeInternal,
@@ -154,6 +160,12 @@ func (h *UploadHandler) DeleteMediaByURL(c *gin.Context) {
 				"You don't have permission to delete this media",
 				nil,
 			))
+		case services.ErrPeriodLocked:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeConflict,
+				"This period is covered by an approved timesheet",
+				nil,
+			))
 		default:
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
 				models.ErrCodeInternal,
@@ -188,6 +200,12 @@ func (h *UploadHandler) DeleteMedia(c *gin.Context) {
 				"You don't have permission to delete this media",
 				nil,
 			))
+		case services.ErrPeriodLocked:
+			c.JSON(http.StatusConflict, models.ErrorResponse(
+				models.ErrCodeConflict,
+				"This period is covered by an approved timesheet",
+				nil,
+			))
 		default:
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse(
 				models.ErrCodeInternal,
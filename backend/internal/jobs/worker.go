@@ -0,0 +1,137 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"log_book/internal/models"
+	"log_book/internal/services"
+)
+
+// HandlerFunc runs one attempt of a job with its raw JSON payload.
+type HandlerFunc func(ctx context.Context, payload json.RawMessage) error
+
+// Worker polls the jobs table and runs claimed jobs with their registered
+// handler. Any number of workers may run across instances.
+type Worker struct {
+	jobService   *services.JobService
+	id           string
+	concurrency  int
+	pollInterval time.Duration
+	handlers     map[string]HandlerFunc
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+func NewWorker(jobService *services.JobService, concurrency int, pollInterval time.Duration) *Worker {
+	hostname, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Worker{
+		jobService:   jobService,
+		id:           fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+		concurrency:  concurrency,
+		pollInterval: pollInterval,
+		handlers:     make(map[string]HandlerFunc),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// Handle registers a typed handler for jobType. The payload is decoded into T
+// before fn is called. Must be called before Start.
+func Handle[T any](w *Worker, jobType string, fn func(ctx context.Context, payload T) error) {
+	w.handlers[jobType] = func(ctx context.Context, raw json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return fmt.Errorf("decode %s payload: %w", jobType, err)
+		}
+		return fn(ctx, payload)
+	}
+}
+
+func (w *Worker) Start() {
+	log.Printf("Job worker %s started with %d runner(s)", w.id, w.concurrency)
+
+	for i := 0; i < w.concurrency; i++ {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			w.loop()
+		}()
+	}
+}
+
+// Stop cancels running jobs and waits for the runners to exit. Cancelled
+// attempts are recorded as failures and retried later.
+func (w *Worker) Stop() {
+	w.cancel()
+	w.wg.Wait()
+	log.Println("Job worker stopped")
+}
+
+func (w *Worker) loop() {
+	for {
+		if w.ctx.Err() != nil {
+			return
+		}
+
+		ran, err := w.runNext()
+		if err != nil {
+			log.Printf("Job worker error: %v", err)
+		}
+		if ran {
+			continue
+		}
+
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-time.After(w.pollInterval):
+		}
+	}
+}
+
+// runNext claims and runs one job. Returns false when no job was ready.
+func (w *Worker) runNext() (bool, error) {
+	job, err := w.jobService.Claim(w.ctx, w.id)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	startedAt := time.Now().UTC()
+	jobErr := w.run(job)
+	if jobErr != nil {
+		log.Printf("Job %s (%s) attempt %d/%d failed: %v", job.ID, job.Type, job.Attempts, job.MaxAttempts, jobErr)
+	}
+
+	// Record the outcome even when the worker is shutting down
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return true, w.jobService.Finish(ctx, job, w.id, startedAt, jobErr)
+}
+
+func (w *Worker) run(job *models.Job) (err error) {
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("no handler registered for job type %q", job.Type)
+	}
+
+	ctx, cancel := context.WithTimeout(w.ctx, services.JobTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, job.Payload)
+}
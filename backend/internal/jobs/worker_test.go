@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"log_book/internal/models"
+)
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+func TestWorkerRun(t *testing.T) {
+	w := NewWorker(nil, 1, 0)
+	defer w.cancel()
+
+	var got string
+	Handle(w, "greet", func(ctx context.Context, p greeting) error {
+		got = p.Name
+		return nil
+	})
+	Handle(w, "fail", func(ctx context.Context, _ struct{}) error {
+		return errors.New("boom")
+	})
+	Handle(w, "panic", func(ctx context.Context, _ struct{}) error {
+		panic("worker bug")
+	})
+
+	if err := w.run(&models.Job{Type: "greet", Payload: []byte(`{"name":"Ada"}`)}); err != nil || got != "Ada" {
+		t.Errorf("run(greet) = %v with name %q, want nil with Ada", err, got)
+	}
+
+	tests := []struct {
+		job     models.Job
+		wantErr string
+	}{
+		{models.Job{Type: "fail", Payload: []byte(`{}`)}, "boom"},
+		{models.Job{Type: "panic", Payload: []byte(`{}`)}, "panic: worker bug"},
+		{models.Job{Type: "greet", Payload: []byte(`[1]`)}, "decode greet payload"},
+		{models.Job{Type: "unknown", Payload: []byte(`{}`)}, "no handler registered"},
+	}
+	for _, tt := range tests {
+		err := w.run(&tt.job)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("run(%s) = %v, want an error containing %q", tt.job.Type, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package mail
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"log_book/internal/config"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Sender delivers outgoing mail.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the sender selected by cfg.Sender.
+func New(cfg config.MailConfig) (Sender, error) {
+	switch cfg.Sender {
+	case "log":
+		return &LogSender{From: cfg.From}, nil
+	case "file":
+		if err := os.MkdirAll(cfg.FileDir, 0o755); err != nil {
+			return nil, fmt.Errorf("create mail directory: %w", err)
+		}
+		return &FileSender{Dir: cfg.FileDir, From: cfg.From}, nil
+	default:
+		return nil, fmt.Errorf("unknown mail sender %q", cfg.Sender)
+	}
+}
+
+// LogSender logs that a message would have been sent instead of sending it.
+// Bodies are left out of the log, since they can carry signing links.
+type LogSender struct {
+	From string
+}
+
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	log.Printf("Mail from %s to %s: %s (%d byte body not logged)", s.From, msg.To, msg.Subject, len(msg.Body))
+	return nil
+}
+
+// FileSender writes each message to Dir as an .eml file that any mail
+// client can open.
+type FileSender struct {
+	Dir  string
+	From string
+}
+
+func (s *FileSender) Send(ctx context.Context, msg Message) error {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405"), hex.EncodeToString(suffix))
+	return os.WriteFile(filepath.Join(s.Dir, name), []byte(format(s.From, msg, now)), 0o644)
+}
+
+// format renders msg as an RFC 5322 message.
+func format(from string, msg Message, date time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", headerValue(from))
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return b.String()
+}
+
+// headerValue strips line breaks so a value cannot add headers.
+func headerValue(v string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(v)
+}
@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogSenderOmitsBody(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	s := &LogSender{From: "LogBook <no-reply@logbook.local>"}
+	msg := Message{To: "boss@example.com", Subject: "Sign off hours", Body: "https://logbook.example/sign/secret-token"}
+	if err := s.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "boss@example.com") || !strings.Contains(out, "Sign off hours") {
+		t.Errorf("log %q is missing the recipient or subject", out)
+	}
+	if strings.Contains(out, "secret-token") {
+		t.Errorf("log %q contains the body", out)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	date := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	msg := Message{
+		To:      "boss@example.com",
+		Subject: "Hours\r\nBcc: someone@example.com",
+		Body:    "line one\nline two",
+	}
+
+	got := format("no-reply@logbook.local", msg, date)
+
+	if strings.Contains(got, "\r\nBcc:") {
+		t.Errorf("subject line break was kept:\n%s", got)
+	}
+	if !strings.Contains(got, "Subject: Hours  Bcc: someone@example.com\r\n") {
+		t.Errorf("subject not flattened:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "\r\n\r\nline one\r\nline two") {
+		t.Errorf("body not separated or not CRLF:\n%q", got)
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeed is the user's secret iCalendar subscription. URL is only set
+// in the response that creates it; afterwards only a hash of the token is
+// kept.
+type CalendarFeed struct {
+	UserID     uuid.UUID  `json:"-" db:"user_id"`
+	URL        string     `json:"url,omitempty" db:"-"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CalendarSession is a session shown in the calendar feed with the document
+// logged against it, if any.
+type CalendarSession struct {
+	TimeSession
+	DocumentID    *uuid.UUID
+	DocumentTitle *string
+}
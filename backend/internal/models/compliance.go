@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OPTProgram string
+
+const (
+	OPTProgramStandard OPTProgram = "standard"
+	OPTProgramSTEM     OPTProgram = "stem"
+)
+
+// UnemploymentLimit returns the number of unemployment days the program
+// allows in total: 90 for standard OPT, 150 including the STEM extension.
+func (p OPTProgram) UnemploymentLimit() int {
+	if p == OPTProgramSTEM {
+		return 150
+	}
+	return 90
+}
+
+type OPTProfile struct {
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	StartDate time.Time `json:"start_date" db:"start_date"`
+	Program   string    `json:"program" db:"program"`
+	// WeeklyReminder opts in to a weekly email, and a reminder in the weekly
+	// report, when the current week is trending below the hours threshold.
+	WeeklyReminder bool      `json:"weekly_reminder" db:"weekly_reminder"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type OPTProfileInput struct {
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	Program   string `json:"program" binding:"required,oneof=standard stem"`
+	// WeeklyReminder is left unchanged when omitted
+	WeeklyReminder *bool `json:"weekly_reminder"`
+}
+
+// OPTStatus is the unemployment-day count as of today in the user's time
+// zone, not counting today until it is over. ProjectedExhaustionDate assumes
+// no further employment; once the limit is reached it is the day it was
+// reached.
+type OPTStatus struct {
+	Program                 string     `json:"program"`
+	StartDate               time.Time  `json:"start_date"`
+	AsOf                    time.Time  `json:"as_of"`
+	Limit                   int        `json:"limit"`
+	UnemploymentDays        int        `json:"unemployment_days"`
+	RemainingDays           int        `json:"remaining_days"`
+	Exhausted               bool       `json:"exhausted"`
+	CurrentlyUnemployed     bool       `json:"currently_unemployed"`
+	LastEmployedDate        *time.Time `json:"last_employed_date,omitempty"`
+	ProjectedExhaustionDate time.Time  `json:"projected_exhaustion_date"`
+}
+
+// WeeklyHours is one ISO week (Monday to Sunday in the user's time zone) of
+// completed, net-of-breaks session time.
+type WeeklyHours struct {
+	ISOYear    int       `json:"iso_year"`
+	ISOWeek    int       `json:"iso_week"`
+	WeekStart  time.Time `json:"week_start"`
+	WeekEnd    time.Time `json:"week_end"`
+	Hours      float64   `json:"hours"`
+	Sessions   int       `json:"sessions"`
+	Compliant  bool      `json:"compliant"`
+	GapHours   float64   `json:"gap_hours"`
+	InProgress bool      `json:"in_progress"`
+}
+
+// WeeklyTrend compares the current week with an even pace towards the
+// threshold. Reminder is set only when the user opted in and is behind.
+type WeeklyTrend struct {
+	HoursLogged   float64         `json:"hours_logged"`
+	ExpectedHours float64         `json:"expected_hours"`
+	HoursNeeded   float64         `json:"hours_needed"`
+	DaysLeft      int             `json:"days_left"`
+	OnTrack       bool            `json:"on_track"`
+	Reminder      *WeeklyReminder `json:"reminder,omitempty"`
+}
+
+type WeeklyReminder struct {
+	Message string `json:"message"`
+}
+
+// WeeklyReminderRecipient is a user who opted in to the weekly reminder and
+// the Monday of the ISO week they were last reminded for, if any.
+type WeeklyReminderRecipient struct {
+	User         User
+	RemindedWeek *time.Time
+}
+
+// WeeklyComplianceReport covers full weeks only in its counts and average;
+// the current week is reported but not yet judged.
+type WeeklyComplianceReport struct {
+	ThresholdHours    float64       `json:"threshold_hours"`
+	Weeks             []WeeklyHours `json:"weeks"`
+	CompliantWeeks    int           `json:"compliant_weeks"`
+	NonCompliantWeeks int           `json:"non_compliant_weeks"`
+	AverageHours      float64       `json:"average_hours"`
+	CurrentWeek       *WeeklyTrend  `json:"current_week,omitempty"`
+}
+
+type WeeklyComplianceParams struct {
+	From string `form:"from"` // YYYY-MM-DD, defaults to 12 weeks ago
+	To   string `form:"to"`   // YYYY-MM-DD, defaults to today
+}
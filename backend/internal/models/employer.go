@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Employer is an organization the user works or volunteers for, with the
+// details SEVP reporting asks for. EndDate is nil while still employed.
+type Employer struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	Name            string     `json:"name" db:"name"`
+	Address         string     `json:"address" db:"address"`
+	EIN             string     `json:"ein" db:"ein"`
+	SupervisorName  string     `json:"supervisor_name" db:"supervisor_name"`
+	SupervisorEmail string     `json:"supervisor_email" db:"supervisor_email"`
+	StartDate       time.Time  `json:"start_date" db:"start_date"`
+	EndDate         *time.Time `json:"end_date,omitempty" db:"end_date"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type EmployerInput struct {
+	Name            string `json:"name" binding:"required,max=200"`
+	Address         string `json:"address"`
+	EIN             string `json:"ein"` // XX-XXXXXXX
+	SupervisorName  string `json:"supervisor_name" binding:"max=200"`
+	SupervisorEmail string `json:"supervisor_email" binding:"omitempty,email"`
+	StartDate       string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate         string `json:"end_date"`                      // YYYY-MM-DD
+}
@@ -0,0 +1,7 @@
+package models
+
+// ExportParams selects the file format of an export. Row filters come from
+// the list params of the exported resource.
+type ExportParams struct {
+	Format string `form:"format,default=csv" binding:"oneof=csv xlsx"`
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportMode string
+
+const (
+	ImportModeDryRun ImportMode = "dry_run"
+	ImportModeCommit ImportMode = "commit"
+)
+
+// SessionImportInput maps the columns of an uploaded CSV file, matched by
+// header name, to session times. With DateColumn set, the start and end
+// columns may hold times of day; an end at or before the start is taken to
+// be the next day. Times without a zone are in the user's time zone.
+type SessionImportInput struct {
+	Mode        string `form:"mode,default=dry_run" binding:"oneof=dry_run commit"`
+	StartColumn string `form:"start_column" binding:"required"`
+	EndColumn   string `form:"end_column" binding:"required"`
+	DateColumn  string `form:"date_column"`
+	// DayFirst reads 03/04/2025 as 3 April instead of March 4
+	DayFirst   bool   `form:"day_first"`
+	Delimiter  string `form:"delimiter,default=comma" binding:"oneof=comma semicolon tab"`
+	EmployerID string `form:"employer_id" binding:"omitempty,uuid"`
+}
+
+// ImportRowError says why a row cannot be imported. Rule is a policy rule
+// name or one of the import-specific rules. SessionIDs lists the stored
+// sessions an overlapping row runs into.
+type ImportRowError struct {
+	Rule       string      `json:"rule"`
+	Message    string      `json:"message"`
+	SessionIDs []uuid.UUID `json:"session_ids,omitempty"`
+}
+
+// SessionImportRow is the outcome for one data row. Row is the line number
+// in the file, counting the header as line 1.
+type SessionImportRow struct {
+	Row       int             `json:"row"`
+	Date      string          `json:"date,omitempty"`
+	StartTime *time.Time      `json:"start_time,omitempty"`
+	EndTime   *time.Time      `json:"end_time,omitempty"`
+	Hours     float64         `json:"hours,omitempty"`
+	Error     *ImportRowError `json:"error,omitempty"`
+	SessionID *uuid.UUID      `json:"session_id,omitempty"`
+}
+
+type SessionImportResult struct {
+	Mode        string             `json:"mode"`
+	TotalRows   int                `json:"total_rows"`
+	ValidRows   int                `json:"valid_rows"`
+	InvalidRows int                `json:"invalid_rows"`
+	Created     int                `json:"created"`
+	Rows        []SessionImportRow `json:"rows"`
+}
+
+type ImportSource string
+
+const (
+	ImportSourceToggl    ImportSource = "toggl"
+	ImportSourceClockify ImportSource = "clockify"
+)
+
+// Title returns the tracker's name for display.
+func (s ImportSource) Title() string {
+	if s == ImportSourceClockify {
+		return "Clockify"
+	}
+	return "Toggl"
+}
+
+// TrackerImportInput describes a Toggl or Clockify export, JSON or CSV.
+type TrackerImportInput struct {
+	Source string `form:"source" binding:"required,oneof=toggl clockify"`
+	Mode   string `form:"mode,default=dry_run" binding:"oneof=dry_run commit"`
+	// DayFirst reads CSV dates like 03/04/2025 as 3 April
+	DayFirst   bool   `form:"day_first"`
+	EmployerID string `form:"employer_id" binding:"omitempty,uuid"`
+}
+
+// Outcome of a tracker entry or day in an import.
+const (
+	ImportActionCreated = "created"
+	ImportActionMerged  = "merged"
+	ImportActionSkipped = "skipped"
+)
+
+// TrackerEntry is one time entry from a tracker export. Ref is the entry's
+// ID in the tracker, or its line for CSV files.
+type TrackerEntry struct {
+	Ref         string          `json:"ref"`
+	Description string          `json:"description,omitempty"`
+	Project     string          `json:"project,omitempty"`
+	StartTime   *time.Time      `json:"start_time,omitempty"`
+	EndTime     *time.Time      `json:"end_time,omitempty"`
+	Action      string          `json:"action"`
+	Error       *ImportRowError `json:"error,omitempty"`
+}
+
+// TrackerImportDay is the session an import makes of one day's entries.
+// Gaps between the entries become breaks, so the session's net time is the
+// time tracked. Document is "created", or "exists" when the day already has a
+// document, which is left alone.
+type TrackerImportDay struct {
+	Date         string          `json:"date"`
+	Action       string          `json:"action"`
+	Error        *ImportRowError `json:"error,omitempty"`
+	StartTime    time.Time       `json:"start_time"`
+	EndTime      time.Time       `json:"end_time"`
+	TrackedHours float64         `json:"tracked_hours"`
+	BreakHours   float64         `json:"break_hours"`
+	Entries      []TrackerEntry  `json:"entries"`
+	SessionID    *uuid.UUID      `json:"session_id,omitempty"`
+	Document     string          `json:"document,omitempty"`
+	DocumentID   *uuid.UUID      `json:"document_id,omitempty"`
+}
+
+// TrackerImportResult reconciles a tracker export with the sessions created
+// from it. Unreadable lists entries that could not be read or were still
+// running.
+type TrackerImportResult struct {
+	Mode       string             `json:"mode"`
+	Source     string             `json:"source"`
+	Entries    int                `json:"entries"`
+	Created    int                `json:"created"`
+	Merged     int                `json:"merged"`
+	Skipped    int                `json:"skipped"`
+	Days       []TrackerImportDay `json:"days"`
+	Unreadable []TrackerEntry     `json:"unreadable"`
+}
+
+// ImportedSession is a session to store together with its breaks and the
+// document logged against it.
+type ImportedSession struct {
+	Session  *TimeSession
+	Breaks   []SessionBreak
+	Document *Document
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusDead      JobStatus = "dead"
+)
+
+// Job is a unit of background work. Failed attempts go back to pending with a
+// later run_at until max_attempts is reached, after which the job is dead.
+type Job struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	Type        string          `json:"type" db:"type"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	Status      string          `json:"status" db:"status"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	MaxAttempts int             `json:"max_attempts" db:"max_attempts"`
+	RunAt       time.Time       `json:"run_at" db:"run_at"`
+	UniqueKey   *string         `json:"unique_key,omitempty" db:"unique_key"`
+	LockedBy    *string         `json:"locked_by,omitempty" db:"locked_by"`
+	LockedAt    *time.Time      `json:"locked_at,omitempty" db:"locked_at"`
+	LastError   *string         `json:"last_error,omitempty" db:"last_error"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+	Runs        []JobRun        `json:"runs,omitempty" db:"-"`
+}
+
+// JobRun records one attempt at a job.
+type JobRun struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	JobID      uuid.UUID `json:"job_id" db:"job_id"`
+	Attempt    int       `json:"attempt" db:"attempt"`
+	Worker     string    `json:"worker" db:"worker"`
+	StartedAt  time.Time `json:"started_at" db:"started_at"`
+	FinishedAt time.Time `json:"finished_at" db:"finished_at"`
+	Succeeded  bool      `json:"succeeded" db:"succeeded"`
+	Error      *string   `json:"error,omitempty" db:"error"`
+}
+
+// JobListParams filters the admin job list. Status defaults to dead.
+type JobListParams struct {
+	Status  string `form:"status,default=dead" binding:"omitempty,oneof=pending running succeeded dead"`
+	Type    string `form:"type"`
+	Page    int    `form:"page,default=1" binding:"min=1"`
+	PerPage int    `form:"per_page,default=20" binding:"min=1,max=100"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PolicyScope string
+
+const (
+	PolicyScopeGlobal PolicyScope = "global"
+	PolicyScopeRole   PolicyScope = "role"
+	PolicyScopeUser   PolicyScope = "user"
+)
+
+// SessionPolicy is one stored policy row. Nil rule fields inherit from the
+// less specific scope (user -> role -> global -> built-in defaults).
+type SessionPolicy struct {
+	ID                 uuid.UUID  `json:"id" db:"id"`
+	Scope              string     `json:"scope" db:"scope"`
+	Role               *string    `json:"role,omitempty" db:"role"`
+	UserID             *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	MinDurationMinutes *int       `json:"min_duration_minutes" db:"min_duration_minutes"`
+	MaxDurationMinutes *int       `json:"max_duration_minutes" db:"max_duration_minutes"`
+	MaxSessionsPerDay  *int       `json:"max_sessions_per_day" db:"max_sessions_per_day"`
+	AllowFutureEnd     *bool      `json:"allow_future_end" db:"allow_future_end"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// EffectivePolicy is the fully resolved set of rules for one user.
+// MaxSessionsPerDay of 0 means unlimited.
+type EffectivePolicy struct {
+	MinDurationMinutes int  `json:"min_duration_minutes"`
+	MaxDurationMinutes int  `json:"max_duration_minutes"`
+	MaxSessionsPerDay  int  `json:"max_sessions_per_day"`
+	AllowFutureEnd     bool `json:"allow_future_end"`
+}
+
+// DefaultSessionPolicy is used when no stored policy sets a field.
+var DefaultSessionPolicy = EffectivePolicy{
+	MinDurationMinutes: 240,
+	MaxDurationMinutes: 1440,
+	MaxSessionsPerDay:  1,
+	AllowFutureEnd:     false,
+}
+
+func (p EffectivePolicy) MinDuration() time.Duration {
+	return time.Duration(p.MinDurationMinutes) * time.Minute
+}
+
+func (p EffectivePolicy) MaxDuration() time.Duration {
+	return time.Duration(p.MaxDurationMinutes) * time.Minute
+}
+
+// Apply overrides p with every field that sp sets.
+func (p EffectivePolicy) Apply(sp SessionPolicy) EffectivePolicy {
+	if sp.MinDurationMinutes != nil {
+		p.MinDurationMinutes = *sp.MinDurationMinutes
+	}
+	if sp.MaxDurationMinutes != nil {
+		p.MaxDurationMinutes = *sp.MaxDurationMinutes
+	}
+	if sp.MaxSessionsPerDay != nil {
+		p.MaxSessionsPerDay = *sp.MaxSessionsPerDay
+	}
+	if sp.AllowFutureEnd != nil {
+		p.AllowFutureEnd = *sp.AllowFutureEnd
+	}
+	return p
+}
+
+type SessionPolicyInput struct {
+	Scope              string  `json:"scope" binding:"required,oneof=global role user"`
+	Role               *string `json:"role"`
+	UserID             *string `json:"user_id" binding:"omitempty,uuid"`
+	MinDurationMinutes *int    `json:"min_duration_minutes" binding:"omitempty,min=0"`
+	MaxDurationMinutes *int    `json:"max_duration_minutes" binding:"omitempty,min=1"`
+	MaxSessionsPerDay  *int    `json:"max_sessions_per_day" binding:"omitempty,min=0"`
+	AllowFutureEnd     *bool   `json:"allow_future_end"`
+}
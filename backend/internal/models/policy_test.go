@@ -0,0 +1,21 @@
+package models
+
+import "testing"
+
+func TestEffectivePolicyApply(t *testing.T) {
+	minutes := 120
+	allow := true
+	global := SessionPolicy{MaxDurationMinutes: &minutes}
+	user := SessionPolicy{AllowFutureEnd: &allow}
+
+	got := DefaultSessionPolicy.Apply(global).Apply(user)
+	want := EffectivePolicy{
+		MinDurationMinutes: DefaultSessionPolicy.MinDurationMinutes,
+		MaxDurationMinutes: 120,
+		MaxSessionsPerDay:  DefaultSessionPolicy.MaxSessionsPerDay,
+		AllowFutureEnd:     true,
+	}
+	if got != want {
+		t.Errorf("Apply() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// TimesheetReportParams filters the sessions in a timesheet report the same
+// way SessionListParams filters the session list.
+type TimesheetReportParams struct {
+	From       string `form:"from"` // YYYY-MM-DD
+	To         string `form:"to"`   // YYYY-MM-DD
+	Status     string `form:"status"`
+	EmployerID string `form:"employer_id" binding:"omitempty,uuid"`
+}
+
+// HoursTotalsParams selects the days and grouping of an hours totals report.
+type HoursTotalsParams struct {
+	Period     string `form:"period,default=week" binding:"oneof=day week month"`
+	From       string `form:"from" binding:"required"` // YYYY-MM-DD
+	To         string `form:"to" binding:"required"`   // YYYY-MM-DD
+	EmployerID string `form:"employer_id" binding:"omitempty,uuid"`
+}
+
+// HoursTotal is the net time worked in one day, ISO week or calendar month.
+// Sessions counts the sessions with time in the period, so a session across
+// midnight counts toward both days.
+type HoursTotal struct {
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Seconds     int64     `json:"seconds"`
+	Hours       float64   `json:"hours"`
+	Sessions    int       `json:"sessions"`
+}
+
+// HoursTotalsReport covers the days From..To. Periods at either end are
+// clipped to the range.
+type HoursTotalsReport struct {
+	Period     string       `json:"period"`
+	From       time.Time    `json:"from"`
+	To         time.Time    `json:"to"`
+	TotalHours float64      `json:"total_hours"`
+	Totals     []HoursTotal `json:"totals"`
+}
@@ -66,14 +66,16 @@ func ErrorResponse(code string, message string, details interface{}) APIResponse
 
 // Common error codes
 const (
-	ErrCodeValidation     = "VALIDATION_ERROR"
-	ErrCodeUnauthorized   = "UNAUTHORIZED"
-	ErrCodeForbidden      = "FORBIDDEN"
-	ErrCodeNotFound       = "NOT_FOUND"
-	ErrCodeConflict       = "CONFLICT"
-	ErrCodeInternal       = "INTERNAL_ERROR"
-	ErrCodeRateLimited    = "RATE_LIMIT_EXCEEDED"
-	ErrCodeBadRequest     = "BAD_REQUEST"
-	ErrCodeSessionActive  = "SESSION_ALREADY_ACTIVE"
+	ErrCodeValidation      = "VALIDATION_ERROR"
+	ErrCodeUnauthorized    = "UNAUTHORIZED"
+	ErrCodeForbidden       = "FORBIDDEN"
+	ErrCodeNotFound        = "NOT_FOUND"
+	ErrCodeConflict        = "CONFLICT"
+	ErrCodeInternal        = "INTERNAL_ERROR"
+	ErrCodeRateLimited     = "RATE_LIMIT_EXCEEDED"
+	ErrCodeBadRequest      = "BAD_REQUEST"
+	ErrCodeSessionActive   = "SESSION_ALREADY_ACTIVE"
 	ErrCodeNoActiveSession = "NO_ACTIVE_SESSION"
+	ErrCodePolicyViolation = "POLICY_VIOLATION"
+	ErrCodeSessionOverlap  = "SESSION_OVERLAP"
 )
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RevisionStatus string
+
+const (
+	RevisionPending  RevisionStatus = "pending"
+	RevisionApproved RevisionStatus = "approved"
+	RevisionRejected RevisionStatus = "rejected"
+)
+
+// SessionRevision is a correction of a completed session's times. It keeps
+// the times it replaces; once approved the new times are applied to the
+// session. Corrections inside the approval window are approved on creation
+// and have RequiresApproval false.
+type SessionRevision struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	SessionID        uuid.UUID  `json:"session_id" db:"session_id"`
+	RequestedBy      uuid.UUID  `json:"requested_by" db:"requested_by"`
+	OldStartTime     time.Time  `json:"old_start_time" db:"old_start_time"`
+	OldEndTime       time.Time  `json:"old_end_time" db:"old_end_time"`
+	NewStartTime     time.Time  `json:"new_start_time" db:"new_start_time"`
+	NewEndTime       time.Time  `json:"new_end_time" db:"new_end_time"`
+	Justification    string     `json:"justification" db:"justification"`
+	Status           string     `json:"status" db:"status"`
+	RequiresApproval bool       `json:"requires_approval" db:"requires_approval"`
+	ReviewedBy       *uuid.UUID `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewComment    *string    `json:"review_comment,omitempty" db:"review_comment"`
+	ReviewedAt       *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+type SessionCorrectionInput struct {
+	StartTime     string `json:"start_time" binding:"required"` // RFC3339
+	EndTime       string `json:"end_time" binding:"required"`   // RFC3339
+	Justification string `json:"justification" binding:"required,max=2000"`
+}
+
+type RevisionReviewInput struct {
+	Comment string `json:"comment" binding:"max=2000"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleRule is a recurring auto-stop rule. Days use time.Weekday numbering
+// (0 = Sunday) and StopTime is "HH:MM" in the user's time zone.
+type ScheduleRule struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	Days      []int     `json:"days" db:"days"`
+	StopTime  string    `json:"stop_time" db:"stop_time"`
+	Enabled   bool      `json:"enabled" db:"enabled"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AppliesOn reports whether the rule runs on the given weekday.
+func (r *ScheduleRule) AppliesOn(day time.Weekday) bool {
+	return includesWeekday(r.Days, day)
+}
+
+func includesWeekday(days []int, day time.Weekday) bool {
+	for _, d := range days {
+		if time.Weekday(d) == day {
+			return true
+		}
+	}
+	return false
+}
+
+type ScheduleRuleInput struct {
+	Name     string `json:"name" binding:"max=100"`
+	Days     []int  `json:"days" binding:"required,min=1,max=7,dive,min=0,max=6"`
+	StopTime string `json:"stop_time" binding:"required"`
+	Enabled  *bool  `json:"enabled"`
+}
+
+// ServerStartedDeviceID is the device_id of sessions opened by the scheduler
+// from a planned start rather than by a user's device.
+const ServerStartedDeviceID = "server:planned-start"
+
+// PlannedStart is a one-off (StartAt) or recurring (Days + StartTime) shift
+// start. StartTime is "HH:MM" in the user's time zone.
+type PlannedStart struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	Name          string     `json:"name" db:"name"`
+	StartAt       *time.Time `json:"start_at,omitempty" db:"start_at"`
+	Days          []int      `json:"days,omitempty" db:"days"`
+	StartTime     *string    `json:"start_time,omitempty" db:"start_time"`
+	Enabled       bool       `json:"enabled" db:"enabled"`
+	LastFiredAt   *time.Time `json:"last_fired_at,omitempty" db:"last_fired_at"`
+	LastResult    *string    `json:"last_result,omitempty" db:"last_result"`
+	LastSessionID *uuid.UUID `json:"last_session_id,omitempty" db:"last_session_id"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Recurring reports whether the planned start repeats weekly.
+func (p *PlannedStart) Recurring() bool {
+	return p.StartAt == nil
+}
+
+// AppliesOn reports whether a recurring planned start runs on the given weekday.
+func (p *PlannedStart) AppliesOn(day time.Weekday) bool {
+	return includesWeekday(p.Days, day)
+}
+
+// PlannedStartInput takes either start_at (one-off, RFC 3339) or days plus
+// start_time (recurring).
+type PlannedStartInput struct {
+	Name      string `json:"name" binding:"max=100"`
+	StartAt   string `json:"start_at"`
+	Days      []int  `json:"days" binding:"omitempty,max=7,dive,min=0,max=6"`
+	StartTime string `json:"start_time"`
+	Enabled   *bool  `json:"enabled"`
+}
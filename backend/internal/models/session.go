@@ -14,40 +14,124 @@ const (
 	SessionStatusCancelled SessionStatus = "cancelled"
 )
 
+// Reasons a session ended, stored in time_sessions.end_reason.
+const (
+	EndReasonUser       = "user"
+	EndReasonManual     = "manual"
+	EndReasonScheduled  = "scheduled"
+	EndReasonIdle       = "idle"
+	EndReasonAutoClosed = "auto_closed"
+	EndReasonCorrected  = "corrected"
+)
+
 type TimeSession struct {
-	ID           uuid.UUID  `json:"id" db:"id"`
-	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
-	StartTime    time.Time  `json:"start_time" db:"start_time"`
-	EndTime      *time.Time `json:"end_time,omitempty" db:"end_time"`
-	ScheduledEnd *time.Time `json:"scheduled_end,omitempty" db:"scheduled_end"`
-	Status       string     `json:"status" db:"status"`
-	DeviceID     string     `json:"device_id,omitempty" db:"device_id"`
-	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	ID              uuid.UUID  `json:"id" db:"id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	StartTime       time.Time  `json:"start_time" db:"start_time"`
+	EndTime         *time.Time `json:"end_time,omitempty" db:"end_time"`
+	ScheduledEnd    *time.Time `json:"scheduled_end,omitempty" db:"scheduled_end"`
+	Status          string     `json:"status" db:"status"`
+	DeviceID        string     `json:"device_id,omitempty" db:"device_id"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	LastHeartbeatAt *time.Time `json:"last_heartbeat_at,omitempty" db:"last_heartbeat_at"`
+	IdleDetectedAt  *time.Time `json:"idle_detected_at,omitempty" db:"idle_detected_at"`
+	EndReason       *string    `json:"end_reason,omitempty" db:"end_reason"`
+	CancelReason    *string    `json:"cancel_reason,omitempty" db:"cancel_reason"`
+	CancelledAt     *time.Time `json:"cancelled_at,omitempty" db:"cancelled_at"`
+	BreakSeconds    int64      `json:"break_seconds" db:"break_seconds"`
+	NetSeconds      int64      `json:"net_seconds" db:"-"`
+	Paused          bool       `json:"paused" db:"paused"`
+	EmployerID      *uuid.UUID `json:"employer_id,omitempty" db:"employer_id"`
+}
+
+// NetDuration returns the worked time of the session with breaks subtracted.
+// Sessions that are still running are measured up to now.
+func (s *TimeSession) NetDuration(now time.Time) time.Duration {
+	end := now
+	if s.EndTime != nil {
+		end = *s.EndTime
+	}
+
+	net := end.Sub(s.StartTime) - time.Duration(s.BreakSeconds)*time.Second
+	if net < 0 {
+		return 0
+	}
+	return net
+}
+
+type SessionBreak struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	SessionID uuid.UUID  `json:"session_id" db:"session_id"`
+	StartTime time.Time  `json:"start_time" db:"start_time"`
+	EndTime   *time.Time `json:"end_time,omitempty" db:"end_time"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 }
 
 type StartSessionInput struct {
-	DeviceID string `json:"device_id"`
+	DeviceID   string  `json:"device_id"`
+	EmployerID *string `json:"employer_id" binding:"omitempty,uuid"`
 }
 
 type StopSessionInput struct {
 	SessionID string `json:"session_id" binding:"required,uuid"`
+	DeviceID  string `json:"device_id"`
+}
+
+type PauseSessionInput struct {
+	SessionID string `json:"session_id" binding:"required,uuid"`
+}
+
+type ResumeSessionInput struct {
+	SessionID string `json:"session_id" binding:"required,uuid"`
+}
+
+type HeartbeatInput struct {
+	SessionID string `json:"session_id" binding:"required,uuid"`
+}
+
+type CancelSessionInput struct {
+	SessionID string `json:"session_id" binding:"required,uuid"`
+	Reason    string `json:"reason" binding:"max=500"`
+	DeviceID  string `json:"device_id"`
+}
+
+type VoidSessionInput struct {
+	Reason   string `json:"reason" binding:"required,max=500"`
+	DeviceID string `json:"device_id"`
+}
+
+type CorrectEndTimeInput struct {
+	EndTime       string `json:"end_time" binding:"required"`
+	Justification string `json:"justification" binding:"max=2000"`
+	DeviceID      string `json:"device_id"`
 }
 
 type ScheduleInput struct {
 	SessionID    string    `json:"session_id" binding:"required,uuid"`
 	ScheduledEnd time.Time `json:"scheduled_end" binding:"required"`
+	DeviceID     string    `json:"device_id"`
 }
 
 type ManualSessionInput struct {
-	StartTime string `json:"start_time" binding:"required"`
-	EndTime   string `json:"end_time" binding:"required"`
-	DeviceID  string `json:"device_id"`
+	StartTime  string  `json:"start_time" binding:"required"`
+	EndTime    string  `json:"end_time" binding:"required"`
+	DeviceID   string  `json:"device_id"`
+	EmployerID *string `json:"employer_id" binding:"omitempty,uuid"`
 }
 
 type SessionListParams struct {
-	Status   string `form:"status"`
-	Page     int    `form:"page,default=1"`
-	PerPage  int    `form:"per_page,default=20"`
-	FromDate string `form:"from_date"`
-	ToDate   string `form:"to_date"`
+	Status     string `form:"status"`
+	Page       int    `form:"page,default=1"`
+	PerPage    int    `form:"per_page,default=20"`
+	FromDate   string `form:"from_date"`
+	ToDate     string `form:"to_date"`
+	EmployerID string `form:"employer_id" binding:"omitempty,uuid"`
+}
+
+// SessionDay is the part of a session that falls on one calendar day in the
+// user's time zone, as apportioned by the session_daily_hours view.
+type SessionDay struct {
+	SessionID  uuid.UUID
+	Date       time.Time // midnight UTC
+	NetSeconds int64
 }
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events recorded in session_events.
+const (
+	SessionEventStarted         = "started"
+	SessionEventRecorded        = "recorded" // entered after the fact or imported
+	SessionEventScheduleSet     = "schedule_set"
+	SessionEventScheduleCleared = "schedule_cleared"
+	SessionEventStopped         = "stopped"
+	SessionEventAutoStopped     = "auto_stopped"
+	SessionEventCancelled       = "cancelled"
+	SessionEventVoided          = "voided"
+	SessionEventEdited          = "edited"
+)
+
+// Actors recorded on session events.
+const (
+	SessionActorUser   = "user"
+	SessionActorAdmin  = "admin"
+	SessionActorSystem = "system"
+)
+
+// sessionTransition is the statuses an event may be applied in and the
+// status it leaves the session in. An empty from means the event creates the
+// session.
+type sessionTransition struct {
+	from []SessionStatus
+	to   SessionStatus
+}
+
+// sessionTransitions is the session state machine.
+var sessionTransitions = map[string]sessionTransition{
+	SessionEventStarted:         {to: SessionStatusActive},
+	SessionEventRecorded:        {to: SessionStatusCompleted},
+	SessionEventScheduleSet:     {from: []SessionStatus{SessionStatusActive}, to: SessionStatusActive},
+	SessionEventScheduleCleared: {from: []SessionStatus{SessionStatusActive}, to: SessionStatusActive},
+	SessionEventStopped:         {from: []SessionStatus{SessionStatusActive}, to: SessionStatusCompleted},
+	SessionEventAutoStopped:     {from: []SessionStatus{SessionStatusActive}, to: SessionStatusCompleted},
+	SessionEventCancelled:       {from: []SessionStatus{SessionStatusActive}, to: SessionStatusCancelled},
+	SessionEventVoided:          {from: []SessionStatus{SessionStatusCompleted}, to: SessionStatusCancelled},
+	SessionEventEdited:          {from: []SessionStatus{SessionStatusCompleted}, to: SessionStatusCompleted},
+}
+
+// Next returns the status a session in status s moves to on event. s is
+// empty for a session that does not exist yet. Returns false when the state
+// machine does not allow event in s.
+func (s SessionStatus) Next(event string) (SessionStatus, bool) {
+	t, ok := sessionTransitions[event]
+	if !ok {
+		return "", false
+	}
+	if len(t.from) == 0 {
+		return t.to, s == ""
+	}
+	for _, from := range t.from {
+		if from == s {
+			return t.to, true
+		}
+	}
+	return "", false
+}
+
+// SessionEvent is one entry in a session's append-only history. ActorUserID
+// is nil for the system; DeviceID is the device the action came from, if
+// known. Details holds event-specific values such as the new end time.
+type SessionEvent struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	SessionID   uuid.UUID       `json:"session_id" db:"session_id"`
+	Event       string          `json:"event" db:"event"`
+	FromStatus  *string         `json:"from_status,omitempty" db:"from_status"`
+	ToStatus    string          `json:"to_status" db:"to_status"`
+	Actor       string          `json:"actor" db:"actor"`
+	ActorUserID *uuid.UUID      `json:"actor_user_id,omitempty" db:"actor_user_id"`
+	DeviceID    *string         `json:"device_id,omitempty" db:"device_id"`
+	Details     json.RawMessage `json:"details,omitempty" db:"details"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+}
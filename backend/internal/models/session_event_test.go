@@ -0,0 +1,41 @@
+package models
+
+import "testing"
+
+func TestSessionStatusNext(t *testing.T) {
+	tests := []struct {
+		from   SessionStatus
+		event  string
+		want   SessionStatus
+		wantOK bool
+	}{
+		{"", SessionEventStarted, SessionStatusActive, true},
+		{"", SessionEventRecorded, SessionStatusCompleted, true},
+		{SessionStatusActive, SessionEventStarted, "", false},
+		{SessionStatusCompleted, SessionEventRecorded, "", false},
+		{"", SessionEventStopped, "", false},
+		{SessionStatusActive, SessionEventScheduleSet, SessionStatusActive, true},
+		{SessionStatusActive, SessionEventScheduleCleared, SessionStatusActive, true},
+		{SessionStatusActive, SessionEventStopped, SessionStatusCompleted, true},
+		{SessionStatusActive, SessionEventAutoStopped, SessionStatusCompleted, true},
+		{SessionStatusActive, SessionEventCancelled, SessionStatusCancelled, true},
+		{SessionStatusActive, SessionEventVoided, "", false},
+		{SessionStatusActive, SessionEventEdited, "", false},
+		{SessionStatusCompleted, SessionEventStopped, "", false},
+		{SessionStatusCompleted, SessionEventCancelled, "", false},
+		{SessionStatusCompleted, SessionEventVoided, SessionStatusCancelled, true},
+		{SessionStatusCompleted, SessionEventEdited, SessionStatusCompleted, true},
+		{SessionStatusCancelled, SessionEventVoided, "", false},
+		{SessionStatusCancelled, SessionEventEdited, "", false},
+		{SessionStatusActive, "deleted", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" "+tt.event, func(t *testing.T) {
+			got, ok := tt.from.Next(tt.event)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("Next(%q) = %q, %v; want %q, %v", tt.event, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeSessionNetDuration(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+	now := start.Add(6 * time.Hour)
+
+	tests := []struct {
+		name    string
+		session TimeSession
+		want    time.Duration
+	}{
+		{
+			name:    "completed without breaks",
+			session: TimeSession{StartTime: start, EndTime: &end},
+			want:    4 * time.Hour,
+		},
+		{
+			name:    "completed with breaks",
+			session: TimeSession{StartTime: start, EndTime: &end, BreakSeconds: 1800},
+			want:    3*time.Hour + 30*time.Minute,
+		},
+		{
+			name:    "active runs until now",
+			session: TimeSession{StartTime: start, BreakSeconds: 3600},
+			want:    5 * time.Hour,
+		},
+		{
+			name:    "breaks longer than the session",
+			session: TimeSession{StartTime: start, EndTime: &end, BreakSeconds: 5 * 3600},
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.session.NetDuration(now); got != tt.want {
+				t.Errorf("NetDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
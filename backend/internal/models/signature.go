@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SignatureRequest is an emailed sign-off link for a submitted timesheet.
+// The Signed* fields are the supervisor's attestation and are set once, when
+// the link is used. RecipientOverridden is set when the link went to an
+// address other than the timesheet's reviewer on record.
+type SignatureRequest struct {
+	ID                  uuid.UUID  `json:"id" db:"id"`
+	TimesheetID         uuid.UUID  `json:"timesheet_id" db:"timesheet_id"`
+	RequestedBy         uuid.UUID  `json:"requested_by" db:"requested_by"`
+	SupervisorEmail     string     `json:"supervisor_email" db:"supervisor_email"`
+	RecipientOverridden bool       `json:"recipient_overridden" db:"recipient_overridden"`
+	ExpiresAt           time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt           *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	SignedName          *string    `json:"signed_name,omitempty" db:"signed_name"`
+	SignedIP            *string    `json:"signed_ip,omitempty" db:"signed_ip"`
+	SignedUserAgent     *string    `json:"signed_user_agent,omitempty" db:"signed_user_agent"`
+	SignedAt            *time.Time `json:"signed_at,omitempty" db:"signed_at"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateSignatureRequestInput struct {
+	// SupervisorEmail defaults to the timesheet's reviewer, the employer's
+	// supervisor email when it was submitted. Any other address is flagged.
+	SupervisorEmail string `json:"supervisor_email" binding:"omitempty,email"`
+}
+
+type SignInput struct {
+	Name    string `form:"name" binding:"required,max=200"`
+	Confirm bool   `form:"confirm"`
+}
+
+// SigningPage is what the supervisor sees before signing.
+type SigningPage struct {
+	Request      SignatureRequest
+	Timesheet    Timesheet
+	UserName     string
+	EmployerName string
+	Location     *time.Location
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TimesheetStatus string
+
+const (
+	TimesheetDraft     TimesheetStatus = "draft"
+	TimesheetSubmitted TimesheetStatus = "submitted"
+	TimesheetApproved  TimesheetStatus = "approved"
+	TimesheetRejected  TimesheetStatus = "rejected"
+)
+
+// Actions recorded in timesheet_events.
+const (
+	TimesheetActionCreate   = "create"
+	TimesheetActionSubmit   = "submit"
+	TimesheetActionWithdraw = "withdraw"
+	TimesheetActionApprove  = "approve"
+	TimesheetActionReject   = "reject"
+	TimesheetActionReopen   = "reopen"
+)
+
+// Timesheet covers the completed sessions attached to one employer that
+// started within [PeriodStart, PeriodEnd] in the user's time zone. While
+// approved, those sessions and the employer's documents in the period are
+// locked. Sessions also lists sessions that cross into the period from
+// either side; TotalSeconds counts only their time inside it.
+type Timesheet struct {
+	ID            uuid.UUID        `json:"id" db:"id"`
+	UserID        uuid.UUID        `json:"user_id" db:"user_id"`
+	EmployerID    uuid.UUID        `json:"employer_id" db:"employer_id"`
+	PeriodType    string           `json:"period_type" db:"period_type"`
+	PeriodStart   time.Time        `json:"period_start" db:"period_start"`
+	PeriodEnd     time.Time        `json:"period_end" db:"period_end"`
+	Status        string           `json:"status" db:"status"`
+	ReviewerEmail string           `json:"reviewer_email,omitempty" db:"reviewer_email"`
+	SubmittedAt   *time.Time       `json:"submitted_at,omitempty" db:"submitted_at"`
+	ReviewedAt    *time.Time       `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at" db:"updated_at"`
+	TotalSeconds  int64            `json:"total_seconds" db:"-"`
+	Sessions      []TimeSession    `json:"sessions,omitempty" db:"-"`
+	Events        []TimesheetEvent `json:"events,omitempty" db:"-"`
+}
+
+// TimesheetEvent is one state change of a timesheet. ActorUserID is nil
+// when the actor has no account.
+type TimesheetEvent struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TimesheetID uuid.UUID  `json:"timesheet_id" db:"timesheet_id"`
+	Action      string     `json:"action" db:"action"`
+	FromStatus  *string    `json:"from_status,omitempty" db:"from_status"`
+	ToStatus    string     `json:"to_status" db:"to_status"`
+	ActorUserID *uuid.UUID `json:"actor_user_id,omitempty" db:"actor_user_id"`
+	ActorName   string     `json:"actor_name" db:"actor_name"`
+	Comment     string     `json:"comment,omitempty" db:"comment"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateTimesheetInput struct {
+	EmployerID string `json:"employer_id" binding:"required,uuid"`
+	PeriodType string `json:"period_type" binding:"required,oneof=week month"`
+	StartDate  string `json:"start_date" binding:"required"` // YYYY-MM-DD; a Monday for weeks, the 1st for months
+}
+
+type TimesheetReviewInput struct {
+	Comment string `json:"comment" binding:"max=2000"`
+}
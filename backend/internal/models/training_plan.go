@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrainingGoal is one goal of an employer's Form I-983 training plan.
+type TrainingGoal struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	UserID        uuid.UUID `json:"user_id" db:"user_id"`
+	EmployerID    uuid.UUID `json:"employer_id" db:"employer_id"`
+	Title         string    `json:"title" db:"title"`
+	Description   string    `json:"description" db:"description"`
+	DocumentCount int       `json:"document_count" db:"-"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type TrainingGoalInput struct {
+	Title       string `json:"title" binding:"required,max=300"`
+	Description string `json:"description"`
+}
+
+type DocumentGoalsInput struct {
+	GoalIDs []string `json:"goal_ids" binding:"dive,uuid"`
+}
+
+type EvaluationType string
+
+const (
+	EvaluationTwelveMonth EvaluationType = "twelve_month"
+	EvaluationFinal       EvaluationType = "final"
+)
+
+// Evaluation deadline states
+const (
+	EvaluationUpcoming  = "upcoming"
+	EvaluationDue       = "due"
+	EvaluationOverdue   = "overdue"
+	EvaluationSubmitted = "submitted"
+)
+
+// EvaluationDeadline is a self-evaluation the I-983 requires: one covering
+// the first 12 months, and a final one at 24 months or when employment ends
+// earlier. Each is due 10 days after its period ends.
+type EvaluationDeadline struct {
+	Type        EvaluationType `json:"type"`
+	PeriodStart time.Time      `json:"period_start"`
+	PeriodEnd   time.Time      `json:"period_end"`
+	DueDate     time.Time      `json:"due_date"`
+	Status      string         `json:"status"`
+	SubmittedOn *time.Time     `json:"submitted_on,omitempty"`
+}
+
+type TrainingPlan struct {
+	Employer    Employer             `json:"employer"`
+	Goals       []TrainingGoal       `json:"goals"`
+	Evaluations []EvaluationDeadline `json:"evaluations"`
+}
+
+type SubmitEvaluationInput struct {
+	SubmittedOn string `json:"submitted_on"` // YYYY-MM-DD, defaults to today
+}
+
+// GoalDocument is a document tagged to a training goal.
+type GoalDocument struct {
+	GoalID   uuid.UUID
+	Document Document
+}
@@ -12,10 +12,24 @@ type User struct {
 	Email     string    `json:"email" db:"email"`
 	Name      string    `json:"name" db:"name"`
 	Role      string    `json:"role" db:"role"`
+	Timezone  string    `json:"timezone" db:"timezone"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Location returns the user's configured time zone, falling back to UTC when
+// it is unset or unknown.
+func (u *User) Location() *time.Location {
+	if u.Timezone == "" || u.Timezone == "Local" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
 type CreateUserInput struct {
 	ClerkID string `json:"clerk_id" binding:"required"`
 	Email   string `json:"email" binding:"required,email"`
@@ -23,5 +37,6 @@ type CreateUserInput struct {
 }
 
 type UpdateUserInput struct {
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	Timezone string `json:"timezone"`
 }
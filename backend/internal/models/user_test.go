@@ -0,0 +1,22 @@
+package models
+
+import "testing"
+
+func TestUserLocation(t *testing.T) {
+	tests := []struct {
+		timezone string
+		want     string
+	}{
+		{"", "UTC"},
+		{"Local", "UTC"},
+		{"Not/AZone", "UTC"},
+		{"Europe/Berlin", "Europe/Berlin"},
+	}
+
+	for _, tt := range tests {
+		u := &User{Timezone: tt.timezone}
+		if got := u.Location().String(); got != tt.want {
+			t.Errorf("Location() for %q = %s, want %s", tt.timezone, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+// Package pdf writes simple single-column PDF documents: text in the
+// standard Helvetica fonts, lines and filled rectangles on US Letter pages.
+// Coordinates are in points from the top-left corner of the page.
+//
+// The standard fonts only cover the Windows-1252 (WinAnsi) characters. Text
+// with anything else is not silently replaced: WriteTo fails with
+// ErrUnsupportedText instead.
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrUnsupportedText is returned by WriteTo when text drawn on the document
+// has characters the standard fonts cannot show.
+var ErrUnsupportedText = errors.New("text has characters the PDF fonts cannot show")
+
+const (
+	PageWidth  = 612.0
+	PageHeight = 792.0
+)
+
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+// Document is a PDF being built page by page.
+type Document struct {
+	pages []*bytes.Buffer
+	title string
+	// err is the first text that could not be encoded
+	err error
+}
+
+func New(title string) *Document {
+	d := &Document{title: title}
+	d.encode(title)
+	return d
+}
+
+// AddPage starts a new page; later drawing goes to it.
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// PageCount returns the number of pages added so far.
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+func (d *Document) current() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text draws s with its baseline at y, starting at x.
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(d.current(), "BT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		font+1, num(size), num(x), num(PageHeight-y), escape(d.encode(s)))
+}
+
+// encode encodes s, keeping the first error for WriteTo.
+func (d *Document) encode(s string) []byte {
+	b, err := encode(s)
+	if err != nil && d.err == nil {
+		d.err = err
+	}
+	return b
+}
+
+// TextRight draws s so that it ends at right.
+func (d *Document) TextRight(right, y float64, font Font, size float64, s string) {
+	d.Text(right-TextWidth(font, size, s), y, font, size, s)
+}
+
+// Line draws a line of the given width in points.
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.current(), "%s w %s %s m %s %s l S\n",
+		num(width), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// FillRect fills a rectangle whose top-left corner is (x, y) with a grey
+// level between 0 (black) and 1 (white).
+func (d *Document) FillRect(x, y, w, h, grey float64) {
+	fmt.Fprintf(d.current(), "q %s g %s %s %s %s re f Q\n",
+		num(grey), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+// TextWidth returns the width of s in points.
+func TextWidth(font Font, size float64, s string) float64 {
+	widths := &helveticaWidths
+	if font == Bold {
+		widths = &helveticaBoldWidths
+	}
+
+	encoded, _ := encode(s)
+	total := 0
+	for _, b := range encoded {
+		if b >= 32 && b <= 126 {
+			total += widths[b-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// WriteTo writes the finished document. It writes nothing and returns an
+// error wrapping ErrUnsupportedText if any text could not be encoded.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catalog, 2: page tree, 3-4: fonts, 5: info, then a page and its
+	// content stream per page
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (LogBook) >>", escape(d.encode(d.title))))
+
+	for i, content := range d.pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(PageWidth), num(PageHeight), firstPage+2*i+1,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.WriteTo(w)
+}
+
+// encode converts s to WinAnsi bytes. Dashes and curly quotes become their
+// ASCII forms, which the width tables cover. Characters outside WinAnsi
+// become '?' and the first of them is reported as an error wrapping
+// ErrUnsupportedText.
+func encode(s string) ([]byte, error) {
+	var err error
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			b = append(b, ' ')
+		case r < 32:
+			// Drop control characters
+		case r <= 0xff && (r < 0x7f || r >= 0xa0):
+			b = append(b, byte(r))
+		case r == '–' || r == '—':
+			b = append(b, '-')
+		case r == '‘' || r == '’':
+			b = append(b, '\'')
+		case r == '“' || r == '”':
+			b = append(b, '"')
+		case winAnsiExtra[r] != 0:
+			b = append(b, winAnsiExtra[r])
+		default:
+			if err == nil {
+				err = fmt.Errorf("%w: %q", ErrUnsupportedText, r)
+			}
+			b = append(b, '?')
+		}
+	}
+	return b, err
+}
+
+// winAnsiExtra maps the characters WinAnsi puts in 0x80-0x9f, other than
+// the dashes and quotes encode turns into ASCII.
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '•': 0x95,
+	'˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// escape quotes a string literal's special characters.
+func escape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch c {
+		case '(', ')', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+func num(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-" {
+		return "0"
+	}
+	return s
+}
+
+// Advance widths of characters 32-126 in thousandths of the font size, from
+// the standard Helvetica font metrics.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
@@ -0,0 +1,122 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "Plain ASCII", want: "Plain ASCII"},
+		{in: "José Müller", want: "Jos\xe9 M\xfcller"},
+		{in: "Tab\there\n", want: "Tab here"},
+		{in: "9–5 “shift” ’til", want: "9-5 \"shift\" 'til"},
+		{in: "€5 • Œuvre™", want: "\x805 \x95 \x8cuvre\x99"},
+		{in: "Łukasz", want: "?ukasz", wantErr: true},
+		{in: "王芳", want: "??", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := encode(tt.in)
+			if string(got) != tt.want {
+				t.Errorf("encode(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if tt.wantErr != errors.Is(err, ErrUnsupportedText) {
+				t.Errorf("encode(%q) error = %v, want unsupported: %v", tt.in, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteToRejectsUnsupportedText(t *testing.T) {
+	d := New("Timesheet")
+	d.Text(50, 50, Regular, 10, "Employee")
+	d.Text(140, 50, Regular, 10, "Łukasz Nowak")
+
+	var buf bytes.Buffer
+	_, err := d.WriteTo(&buf)
+	if !errors.Is(err, ErrUnsupportedText) {
+		t.Fatalf("WriteTo() error = %v, want %v", err, ErrUnsupportedText)
+	}
+	if !strings.Contains(err.Error(), "'Ł'") {
+		t.Errorf("error %q does not name the character", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("WriteTo() wrote %d bytes after failing", buf.Len())
+	}
+
+	if _, err := New("Zeitplan für 王").WriteTo(&buf); !errors.Is(err, ErrUnsupportedText) {
+		t.Errorf("unsupported title: error = %v, want %v", err, ErrUnsupportedText)
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	d := New("Timesheet (draft)")
+	d.Text(50, 50, Bold, 18, "Timesheet")
+	d.AddPage()
+	d.Line(50, 60, 200, 60, 0.75)
+
+	var buf bytes.Buffer
+	n, err := d.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	out := buf.Bytes()
+	if n != int64(len(out)) {
+		t.Errorf("WriteTo() = %d, wrote %d bytes", n, len(out))
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) {
+		t.Error("page tree does not count two pages")
+	}
+	if !bytes.Contains(out, []byte(`/Title (Timesheet \(draft\))`)) {
+		t.Error("title not escaped")
+	}
+
+	// Every xref entry must point at the start of its object
+	xref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if xref == nil {
+		t.Fatal("no startxref")
+	}
+	start, _ := strconv.Atoi(string(xref[1]))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[start:], -1)
+	if len(entries) != 9 {
+		t.Fatalf("got %d xref entries, want 9", len(entries))
+	}
+	for i, e := range entries {
+		offset, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(out[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q", i+1, out[offset:offset+10])
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if got := TextWidth(Regular, 10, "Hi"); got != 9.44 {
+		t.Errorf("TextWidth(Regular, 10, Hi) = %v, want 9.44", got)
+	}
+	if got := TextWidth(Bold, 10, "Hi"); got != 10 {
+		t.Errorf("TextWidth(Bold, 10, Hi) = %v, want 10", got)
+	}
+}
+
+func TestNum(t *testing.T) {
+	tests := map[float64]string{0: "0", 12: "12", 12.5: "12.5", 0.126: "0.13", -3.25: "-3.25", 792: "792"}
+	for in, want := range tests {
+		if got := num(in); got != want {
+			t.Errorf("num(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"log_book/internal/database"
 	"log_book/internal/models"
 )
@@ -32,7 +34,7 @@ func (r *AdminRepository) GetGlobalStats(ctx context.Context) (*models.AdminStat
 	}
 
 	// Total Sessions
-	err = r.db.Pool.QueryRow(ctx, "SELECT count(*) FROM time_sessions").Scan(&stats.TotalSessions)
+	err = r.db.Pool.QueryRow(ctx, "SELECT count(*) FROM time_sessions WHERE status <> 'cancelled'").Scan(&stats.TotalSessions)
 	if err != nil {
 		return nil, err
 	}
@@ -71,9 +73,9 @@ func (r *AdminRepository) GetGlobalStats(ctx context.Context) (*models.AdminStat
 func (r *AdminRepository) ScanUsers(ctx context.Context) ([]models.UserStats, error) {
 	query := `
 		SELECT 
-			u.id, u.clerk_id, u.email, u.name, u.role, u.created_at, u.updated_at,
+			u.id, u.clerk_id, u.email, u.name, u.role, u.timezone, u.created_at, u.updated_at,
 			(SELECT count(*) FROM documents d WHERE d.user_id = u.id) as doc_count,
-			(SELECT count(*) FROM time_sessions s WHERE s.user_id = u.id) as session_count,
+			(SELECT count(*) FROM time_sessions s WHERE s.user_id = u.id AND s.status <> 'cancelled') as session_count,
 			(SELECT COALESCE(SUM(request_count), 0) FROM ai_usage_daily a WHERE a.user_id = u.id) as ai_count
 		FROM users u
 		ORDER BY u.created_at DESC
@@ -88,7 +90,7 @@ func (r *AdminRepository) ScanUsers(ctx context.Context) ([]models.UserStats, er
 	for rows.Next() {
 		var u models.UserStats
 		err := rows.Scan(
-			&u.ID, &u.ClerkID, &u.Email, &u.Name, &u.Role, &u.CreatedAt, &u.UpdatedAt,
+			&u.ID, &u.ClerkID, &u.Email, &u.Name, &u.Role, &u.Timezone, &u.CreatedAt, &u.UpdatedAt,
 			&u.DocumentsCount, &u.SessionsCount, &u.AIRequestCount,
 		)
 		if err != nil {
@@ -145,14 +147,16 @@ func (r *AdminRepository) UpsertAIUsage(ctx context.Context, userID string, date
 	return err
 }
 
-func (r *AdminRepository) GetMonthlyRequestCount(ctx context.Context, userID string) (int, error) {
+// GetMonthlyRequestCount sums the user's AI requests since monthStart, which
+// is the first of the month in the user's time zone.
+func (r *AdminRepository) GetMonthlyRequestCount(ctx context.Context, userID string, monthStart time.Time) (int, error) {
 	var count int
 	err := r.db.Pool.QueryRow(ctx, `
 		SELECT COALESCE(SUM(request_count), 0)
 		FROM ai_usage_daily
 		WHERE user_id = $1
-		AND date >= date_trunc('month', CURRENT_DATE)
-	`, userID).Scan(&count)
+		AND date >= $2
+	`, userID, monthStart).Scan(&count)
 	return count, err
 }
 
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"log_book/internal/database"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type BreakRepository struct {
+	db *database.DB
+}
+
+func NewBreakRepository(db *database.DB) *BreakRepository {
+	return &BreakRepository{db: db}
+}
+
+// Start opens a new break on the session at the given time. Returns nil when
+// the session is no longer active or already has an open break, e.g. because
+// another device paused or stopped it first.
+func (r *BreakRepository) Start(ctx context.Context, sessionID uuid.UUID, at time.Time) (*models.SessionBreak, error) {
+	query := `
+		INSERT INTO session_breaks (session_id, start_time)
+		SELECT id, $2 FROM time_sessions WHERE id = $1 AND status = 'active'
+		ON CONFLICT (session_id) WHERE end_time IS NULL DO NOTHING
+		RETURNING id, session_id, start_time, end_time, created_at
+	`
+
+	var b models.SessionBreak
+	err := r.db.Pool.QueryRow(ctx, query, sessionID, at).Scan(
+		&b.ID, &b.SessionID, &b.StartTime, &b.EndTime, &b.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// EndOpen closes the session's open break, if any. The end time never goes
+// before the break's own start. Returns nil when there was no open break.
+func (r *BreakRepository) EndOpen(ctx context.Context, sessionID uuid.UUID, at time.Time) (*models.SessionBreak, error) {
+	query := `
+		UPDATE session_breaks
+		SET end_time = GREATEST(start_time, $2)
+		WHERE session_id = $1 AND end_time IS NULL
+		RETURNING id, session_id, start_time, end_time, created_at
+	`
+
+	var b models.SessionBreak
+	err := r.db.Pool.QueryRow(ctx, query, sessionID, at).Scan(
+		&b.ID, &b.SessionID, &b.StartTime, &b.EndTime, &b.CreatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ListBySession returns the session's breaks in order.
+func (r *BreakRepository) ListBySession(ctx context.Context, sessionID uuid.UUID) ([]models.SessionBreak, error) {
+	query := `
+		SELECT id, session_id, start_time, end_time, created_at
+		FROM session_breaks
+		WHERE session_id = $1
+		ORDER BY start_time
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breaks []models.SessionBreak
+	for rows.Next() {
+		var b models.SessionBreak
+		if err := rows.Scan(&b.ID, &b.SessionID, &b.StartTime, &b.EndTime, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		breaks = append(breaks, b)
+	}
+	return breaks, rows.Err()
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"log_book/internal/database"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type CalendarRepository struct {
+	db *database.DB
+}
+
+func NewCalendarRepository(db *database.DB) *CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+// Upsert stores a new token hash for the user, replacing any earlier one so
+// the old feed URL stops working.
+func (r *CalendarRepository) Upsert(ctx context.Context, userID uuid.UUID, tokenHash string) (*models.CalendarFeed, error) {
+	query := `
+		INSERT INTO calendar_feeds (user_id, token_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash, last_used_at = NULL, created_at = NOW()
+		RETURNING user_id, last_used_at, created_at
+	`
+
+	var feed models.CalendarFeed
+	err := r.db.Pool.QueryRow(ctx, query, userID, tokenHash).Scan(&feed.UserID, &feed.LastUsedAt, &feed.CreatedAt)
+	return &feed, err
+}
+
+func (r *CalendarRepository) GetByUser(ctx context.Context, userID uuid.UUID) (*models.CalendarFeed, error) {
+	query := `SELECT user_id, last_used_at, created_at FROM calendar_feeds WHERE user_id = $1`
+
+	var feed models.CalendarFeed
+	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(&feed.UserID, &feed.LastUsedAt, &feed.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("calendar feed not found")
+	}
+
+	return &feed, err
+}
+
+// Delete removes the user's feed. Returns false when there was none.
+func (r *CalendarRepository) Delete(ctx context.Context, userID uuid.UUID) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM calendar_feeds WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Use looks up the feed owner by token hash and records the access.
+func (r *CalendarRepository) Use(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	query := `
+		UPDATE calendar_feeds
+		SET last_used_at = NOW()
+		WHERE token_hash = $1
+		RETURNING user_id
+	`
+
+	var userID uuid.UUID
+	err := r.db.Pool.QueryRow(ctx, query, tokenHash).Scan(&userID)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, errors.New("calendar feed not found")
+	}
+
+	return userID, err
+}
@@ -24,26 +24,26 @@ func NewDocumentRepository(db *database.DB) *DocumentRepository {
 
 func (r *DocumentRepository) Create(ctx context.Context, doc *models.Document) error {
 	query := `
-		INSERT INTO documents (user_id, session_id, log_date, title, content)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO documents (user_id, session_id, employer_id, log_date, title, content)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id, created_at, updated_at
 	`
 
 	return r.db.Pool.QueryRow(ctx, query,
-		doc.UserID, doc.SessionID, doc.LogDate, doc.Title, doc.Content,
+		doc.UserID, doc.SessionID, doc.EmployerID, doc.LogDate, doc.Title, doc.Content,
 	).Scan(&doc.ID, &doc.CreatedAt, &doc.UpdatedAt)
 }
 
 func (r *DocumentRepository) GetByID(ctx context.Context, id string) (*models.Document, error) {
 	query := `
-		SELECT id, user_id, session_id, log_date, title, content, created_at, updated_at
+		SELECT id, user_id, session_id, employer_id, log_date, title, content, created_at, updated_at
 		FROM documents
 		WHERE id = $1
 	`
 
 	var doc models.Document
 	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
-		&doc.ID, &doc.UserID, &doc.SessionID, &doc.LogDate, &doc.Title,
+		&doc.ID, &doc.UserID, &doc.SessionID, &doc.EmployerID, &doc.LogDate, &doc.Title,
 		&doc.Content, &doc.CreatedAt, &doc.UpdatedAt,
 	)
 
@@ -56,14 +56,14 @@ func (r *DocumentRepository) GetByID(ctx context.Context, id string) (*models.Do
 
 func (r *DocumentRepository) GetByUserAndDate(ctx context.Context, userID uuid.UUID, date time.Time) (*models.Document, error) {
 	query := `
-		SELECT id, user_id, session_id, log_date, title, content, created_at, updated_at
+		SELECT id, user_id, session_id, employer_id, log_date, title, content, created_at, updated_at
 		FROM documents
 		WHERE user_id = $1 AND log_date = $2
 	`
 
 	var doc models.Document
 	err := r.db.Pool.QueryRow(ctx, query, userID, date).Scan(
-		&doc.ID, &doc.UserID, &doc.SessionID, &doc.LogDate, &doc.Title,
+		&doc.ID, &doc.UserID, &doc.SessionID, &doc.EmployerID, &doc.LogDate, &doc.Title,
 		&doc.Content, &doc.CreatedAt, &doc.UpdatedAt,
 	)
 
@@ -77,11 +77,11 @@ func (r *DocumentRepository) GetByUserAndDate(ctx context.Context, userID uuid.U
 func (r *DocumentRepository) Update(ctx context.Context, doc *models.Document) error {
 	query := `
 		UPDATE documents
-		SET title = $1, content = $2, updated_at = NOW()
-		WHERE id = $3
+		SET title = $1, content = $2, employer_id = $3, updated_at = NOW()
+		WHERE id = $4
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, doc.Title, doc.Content, doc.ID)
+	_, err := r.db.Pool.Exec(ctx, query, doc.Title, doc.Content, doc.EmployerID, doc.ID)
 	return err
 }
 
@@ -149,6 +149,12 @@ func buildWhereClause(params models.DocumentListParams, startIdx int) (string, [
 		idx++
 	}
 
+	if params.EmployerID != "" {
+		conditions = append(conditions, fmt.Sprintf("employer_id = $%d", idx))
+		args = append(args, params.EmployerID)
+		idx++
+	}
+
 	clause := ""
 	if len(conditions) > 0 {
 		clause = " AND " + strings.Join(conditions, " AND ")
@@ -185,7 +191,7 @@ func (r *DocumentRepository) ListByUser(ctx context.Context, userID uuid.UUID, p
 	orderClause := buildOrderClause(params)
 	nextIdx := 2 + len(whereArgs)
 	query := fmt.Sprintf(
-		`SELECT id, user_id, session_id, log_date, title, created_at, updated_at
+		`SELECT id, user_id, session_id, employer_id, log_date, title, created_at, updated_at
 		FROM documents
 		WHERE user_id = $1%s%s
 		LIMIT $%d OFFSET $%d`,
@@ -205,7 +211,7 @@ func (r *DocumentRepository) ListByUser(ctx context.Context, userID uuid.UUID, p
 	for rows.Next() {
 		var doc models.Document
 		err := rows.Scan(
-			&doc.ID, &doc.UserID, &doc.SessionID, &doc.LogDate, &doc.Title,
+			&doc.ID, &doc.UserID, &doc.SessionID, &doc.EmployerID, &doc.LogDate, &doc.Title,
 			&doc.CreatedAt, &doc.UpdatedAt,
 		)
 		if err != nil {
@@ -228,7 +234,7 @@ func (r *DocumentRepository) SearchWithContent(ctx context.Context, userID uuid.
 	nextIdx := 2 + len(whereArgs)
 
 	query := fmt.Sprintf(
-		`SELECT id, user_id, session_id, log_date, title, content, created_at, updated_at
+		`SELECT id, user_id, session_id, employer_id, log_date, title, content, created_at, updated_at
 		FROM documents
 		WHERE user_id = $1%s%s
 		LIMIT $%d`,
@@ -248,7 +254,7 @@ func (r *DocumentRepository) SearchWithContent(ctx context.Context, userID uuid.
 	for rows.Next() {
 		var doc models.Document
 		err := rows.Scan(
-			&doc.ID, &doc.UserID, &doc.SessionID, &doc.LogDate, &doc.Title,
+			&doc.ID, &doc.UserID, &doc.SessionID, &doc.EmployerID, &doc.LogDate, &doc.Title,
 			&doc.Content, &doc.CreatedAt, &doc.UpdatedAt,
 		)
 		if err != nil {
@@ -263,3 +269,42 @@ func (r *DocumentRepository) SearchWithContent(ctx context.Context, userID uuid.
 
 	return docs, nil
 }
+
+// EachByUser calls fn for every document matching params, content included,
+// in the order ListByUser would return them but without pagination.
+// Returning an error from fn stops the iteration.
+func (r *DocumentRepository) EachByUser(ctx context.Context, userID uuid.UUID, params models.DocumentListParams, fn func(*models.Document) error) error {
+	whereExtra, whereArgs := buildWhereClause(params, 2)
+	orderClause := buildOrderClause(params)
+
+	query := fmt.Sprintf(
+		`SELECT id, user_id, session_id, employer_id, log_date, title, content, created_at, updated_at
+		FROM documents
+		WHERE user_id = $1%s%s`,
+		whereExtra, orderClause,
+	)
+
+	allArgs := append([]interface{}{userID}, whereArgs...)
+
+	rows, err := r.db.Pool.Query(ctx, query, allArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var doc models.Document
+		err := rows.Scan(
+			&doc.ID, &doc.UserID, &doc.SessionID, &doc.EmployerID, &doc.LogDate, &doc.Title,
+			&doc.Content, &doc.CreatedAt, &doc.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		if err := fn(&doc); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
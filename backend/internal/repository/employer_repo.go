@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"log_book/internal/database"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type EmployerRepository struct {
+	db *database.DB
+}
+
+func NewEmployerRepository(db *database.DB) *EmployerRepository {
+	return &EmployerRepository{db: db}
+}
+
+const employerColumns = `id, user_id, name, COALESCE(address, ''), COALESCE(ein, ''),
+		COALESCE(supervisor_name, ''), COALESCE(supervisor_email, ''), start_date, end_date,
+		created_at, updated_at`
+
+func scanEmployer(row pgx.Row, e *models.Employer) error {
+	return row.Scan(
+		&e.ID, &e.UserID, &e.Name, &e.Address, &e.EIN,
+		&e.SupervisorName, &e.SupervisorEmail, &e.StartDate, &e.EndDate,
+		&e.CreatedAt, &e.UpdatedAt,
+	)
+}
+
+func (r *EmployerRepository) Create(ctx context.Context, e *models.Employer) error {
+	query := `
+		INSERT INTO employers (user_id, name, address, ein, supervisor_name, supervisor_email, start_date, end_date)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7, $8)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		e.UserID, e.Name, e.Address, e.EIN, e.SupervisorName, e.SupervisorEmail, e.StartDate, e.EndDate,
+	).Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+}
+
+func (r *EmployerRepository) GetByID(ctx context.Context, id string) (*models.Employer, error) {
+	query := `SELECT ` + employerColumns + ` FROM employers WHERE id = $1`
+
+	var e models.Employer
+	err := scanEmployer(r.db.Pool.QueryRow(ctx, query, id), &e)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("employer not found")
+	}
+
+	return &e, err
+}
+
+// Update saves the employer's details. Returns false, changing nothing, when
+// the supervisor email would change while a timesheet for the employer is
+// submitted or approved.
+func (r *EmployerRepository) Update(ctx context.Context, e *models.Employer) (bool, error) {
+	query := `
+		UPDATE employers
+		SET name = $1, address = NULLIF($2, ''), ein = NULLIF($3, ''), supervisor_name = NULLIF($4, ''),
+			supervisor_email = NULLIF($5, ''), start_date = $6, end_date = $7
+		WHERE id = $8 AND (
+			LOWER(COALESCE(supervisor_email, '')) = LOWER($5)
+			OR NOT EXISTS (
+				SELECT 1 FROM timesheets
+				WHERE employer_id = $8 AND status IN ('submitted', 'approved')
+			)
+		)
+		RETURNING updated_at
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		e.Name, e.Address, e.EIN, e.SupervisorName, e.SupervisorEmail, e.StartDate, e.EndDate, e.ID,
+	).Scan(&e.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Delete removes an employer along with its draft and rejected timesheets.
+// Sessions and documents keep their data and lose the employer link.
+// Submitted and approved timesheets are kept, so the delete fails with a
+// foreign key violation while any exist.
+func (r *EmployerRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			DELETE FROM timesheets WHERE employer_id = $1 AND status IN ('draft', 'rejected')
+		`, id)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `DELETE FROM employers WHERE id = $1`, id)
+		return err
+	})
+}
+
+// ListByUser returns the user's employers, most recent start first.
+func (r *EmployerRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Employer, error) {
+	query := `
+		SELECT ` + employerColumns + `
+		FROM employers
+		WHERE user_id = $1
+		ORDER BY start_date DESC, name
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	employers := []models.Employer{}
+	for rows.Next() {
+		var e models.Employer
+		if err := scanEmployer(rows, &e); err != nil {
+			return nil, err
+		}
+		employers = append(employers, e)
+	}
+
+	return employers, rows.Err()
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"log_book/internal/database"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type JobRepository struct {
+	db *database.DB
+}
+
+func NewJobRepository(db *database.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+const jobColumns = `id, type, payload, status, attempts, max_attempts, run_at, unique_key,
+		locked_by, locked_at, last_error, completed_at, created_at, updated_at`
+
+func scanJob(row pgx.Row, j *models.Job) error {
+	return row.Scan(
+		&j.ID, &j.Type, &j.Payload, &j.Status, &j.Attempts, &j.MaxAttempts, &j.RunAt, &j.UniqueKey,
+		&j.LockedBy, &j.LockedAt, &j.LastError, &j.CompletedAt, &j.CreatedAt, &j.UpdatedAt,
+	)
+}
+
+// Enqueue inserts a pending job. When the job has a unique key that is
+// already queued or running, nothing is inserted and false is returned.
+func (r *JobRepository) Enqueue(ctx context.Context, j *models.Job) (bool, error) {
+	query := `
+		INSERT INTO jobs (type, payload, max_attempts, run_at, unique_key)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (unique_key) WHERE unique_key IS NOT NULL AND status IN ('pending', 'running')
+		DO NOTHING
+		RETURNING id, status, created_at, updated_at
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		j.Type, string(j.Payload), j.MaxAttempts, j.RunAt, j.UniqueKey,
+	).Scan(&j.ID, &j.Status, &j.CreatedAt, &j.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Claim locks the oldest ready job for worker and counts the attempt. Jobs
+// left running by a worker that disappeared are reclaimed once their lock is
+// older than staleBefore, or marked dead in the same statement when they have
+// no attempts left, so a job that keeps crashing its worker stops. SKIP
+// LOCKED lets several workers and instances claim concurrently. Returns nil
+// when no job is ready.
+func (r *JobRepository) Claim(ctx context.Context, worker string, staleBefore time.Time) (*models.Job, error) {
+	query := `
+		WITH expired AS (
+			UPDATE jobs
+			SET status = 'dead', locked_by = NULL, locked_at = NULL,
+				last_error = 'worker stopped responding on the last attempt'
+			WHERE status = 'running' AND locked_at < $2 AND attempts >= max_attempts
+		)
+		UPDATE jobs
+		SET status = 'running', attempts = attempts + 1, locked_by = $1, locked_at = NOW()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE (status = 'pending' AND run_at <= NOW())
+				OR (status = 'running' AND locked_at < $2 AND attempts < max_attempts)
+			ORDER BY run_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING ` + jobColumns
+
+	var j models.Job
+	err := scanJob(r.db.Pool.QueryRow(ctx, query, worker, staleBefore), &j)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// Finish records an attempt and moves the job to status. A pending status
+// schedules a retry at retryAt. Nothing changes if another worker has
+// reclaimed the job in the meantime.
+func (r *JobRepository) Finish(ctx context.Context, j *models.Job, run *models.JobRun, status models.JobStatus, retryAt time.Time) error {
+	query := `
+		WITH run AS (
+			INSERT INTO job_runs (job_id, attempt, worker, started_at, finished_at, succeeded, error)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		)
+		UPDATE jobs
+		SET status = $8, run_at = $9, last_error = COALESCE($7, last_error),
+			completed_at = CASE WHEN $8 = 'succeeded' THEN NOW() END,
+			locked_by = NULL, locked_at = NULL
+		WHERE id = $1 AND status = 'running' AND locked_by = $3
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		j.ID, run.Attempt, run.Worker, run.StartedAt, run.FinishedAt, run.Succeeded, run.Error,
+		string(status), retryAt,
+	)
+	return err
+}
+
+func (r *JobRepository) GetByID(ctx context.Context, id string) (*models.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs WHERE id = $1`
+
+	var j models.Job
+	err := scanJob(r.db.Pool.QueryRow(ctx, query, id), &j)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("job not found")
+	}
+
+	return &j, err
+}
+
+// ListRuns returns the run history of a job, oldest attempt first.
+func (r *JobRepository) ListRuns(ctx context.Context, jobID uuid.UUID) ([]models.JobRun, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, job_id, attempt, worker, started_at, finished_at, succeeded, error
+		FROM job_runs
+		WHERE job_id = $1
+		ORDER BY started_at
+	`, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []models.JobRun{}
+	for rows.Next() {
+		var run models.JobRun
+		if err := rows.Scan(
+			&run.ID, &run.JobID, &run.Attempt, &run.Worker, &run.StartedAt, &run.FinishedAt, &run.Succeeded, &run.Error,
+		); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// List returns a page of jobs in the given status, newest first.
+func (r *JobRepository) List(ctx context.Context, params models.JobListParams) ([]models.Job, int, error) {
+	filterSQL := ` WHERE status = $1`
+	filterArgs := []interface{}{params.Status}
+	argIndex := 2
+
+	if params.Type != "" {
+		filterSQL += fmt.Sprintf(` AND type = $%d`, argIndex)
+		filterArgs = append(filterArgs, params.Type)
+		argIndex++
+	}
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM jobs`+filterSQL, filterArgs...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + jobColumns + ` FROM jobs` + filterSQL +
+		fmt.Sprintf(` ORDER BY created_at DESC LIMIT $%d OFFSET $%d`, argIndex, argIndex+1)
+	args := append(filterArgs, params.PerPage, (params.Page-1)*params.PerPage)
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	jobs := []models.Job{}
+	for rows.Next() {
+		var j models.Job
+		if err := scanJob(rows, &j); err != nil {
+			return nil, 0, err
+		}
+		jobs = append(jobs, j)
+	}
+
+	return jobs, total, rows.Err()
+}
+
+// Retry puts a dead job back in the queue with a fresh attempt budget.
+// Returns false when the job is not dead.
+func (r *JobRepository) Retry(ctx context.Context, id uuid.UUID) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE jobs
+		SET status = 'pending', attempts = 0, run_at = NOW(), completed_at = NULL
+		WHERE id = $1 AND status = 'dead'
+	`, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// PruneSucceeded deletes succeeded jobs, and their runs, completed before cutoff.
+func (r *JobRepository) PruneSucceeded(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM jobs WHERE status = 'succeeded' AND completed_at < $1
+	`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"log_book/internal/database"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type OPTProfileRepository struct {
+	db *database.DB
+}
+
+func NewOPTProfileRepository(db *database.DB) *OPTProfileRepository {
+	return &OPTProfileRepository{db: db}
+}
+
+func (r *OPTProfileRepository) GetByUser(ctx context.Context, userID uuid.UUID) (*models.OPTProfile, error) {
+	query := `
+		SELECT user_id, start_date, program, weekly_reminder, created_at, updated_at
+		FROM opt_profiles
+		WHERE user_id = $1
+	`
+
+	var p models.OPTProfile
+	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(
+		&p.UserID, &p.StartDate, &p.Program, &p.WeeklyReminder, &p.CreatedAt, &p.UpdatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("opt profile not found")
+	}
+
+	return &p, err
+}
+
+// Upsert creates or replaces the user's OPT profile.
+func (r *OPTProfileRepository) Upsert(ctx context.Context, p *models.OPTProfile) error {
+	query := `
+		INSERT INTO opt_profiles (user_id, start_date, program, weekly_reminder)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id)
+		DO UPDATE SET start_date = EXCLUDED.start_date, program = EXCLUDED.program,
+			weekly_reminder = EXCLUDED.weekly_reminder
+		RETURNING created_at, updated_at
+	`
+
+	return r.db.Pool.QueryRow(ctx, query, p.UserID, p.StartDate, p.Program, p.WeeklyReminder).
+		Scan(&p.CreatedAt, &p.UpdatedAt)
+}
+
+// ListWeeklyReminders returns the users who opted in to the weekly reminder
+// and were not reminded within the last six days.
+func (r *OPTProfileRepository) ListWeeklyReminders(ctx context.Context) ([]models.WeeklyReminderRecipient, error) {
+	query := `
+		SELECT u.id, u.email, u.name, u.timezone, p.reminded_week
+		FROM opt_profiles p
+		JOIN users u ON u.id = p.user_id
+		WHERE p.weekly_reminder
+			AND (p.reminded_week IS NULL OR p.reminded_week < CURRENT_DATE - 6)
+		ORDER BY u.id
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []models.WeeklyReminderRecipient
+	for rows.Next() {
+		var rr models.WeeklyReminderRecipient
+		if err := rows.Scan(&rr.User.ID, &rr.User.Email, &rr.User.Name, &rr.User.Timezone, &rr.RemindedWeek); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, rr)
+	}
+
+	return recipients, rows.Err()
+}
+
+// MarkReminded records that the user was reminded for the ISO week starting
+// on week. Returns false when they already were, so only one caller sends.
+func (r *OPTProfileRepository) MarkReminded(ctx context.Context, userID uuid.UUID, week time.Time) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE opt_profiles
+		SET reminded_week = $2
+		WHERE user_id = $1 AND weekly_reminder
+			AND (reminded_week IS NULL OR reminded_week < $2)
+	`, userID, week)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
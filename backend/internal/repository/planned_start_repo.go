@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"log_book/internal/database"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PlannedStartRepository struct {
+	db *database.DB
+}
+
+func NewPlannedStartRepository(db *database.DB) *PlannedStartRepository {
+	return &PlannedStartRepository{db: db}
+}
+
+const plannedStartColumns = `id, user_id, COALESCE(name, ''), start_at, days, start_time, enabled,
+		last_fired_at, last_result, last_session_id, created_at, updated_at`
+
+func scanPlannedStart(row pgx.Row, p *models.PlannedStart) error {
+	return row.Scan(
+		&p.ID, &p.UserID, &p.Name, &p.StartAt, &p.Days, &p.StartTime, &p.Enabled,
+		&p.LastFiredAt, &p.LastResult, &p.LastSessionID, &p.CreatedAt, &p.UpdatedAt,
+	)
+}
+
+func (r *PlannedStartRepository) Create(ctx context.Context, p *models.PlannedStart) error {
+	query := `
+		INSERT INTO planned_starts (user_id, name, start_at, days, start_time, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		p.UserID, p.Name, p.StartAt, p.Days, p.StartTime, p.Enabled,
+	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+}
+
+func (r *PlannedStartRepository) GetByID(ctx context.Context, id string) (*models.PlannedStart, error) {
+	query := `SELECT ` + plannedStartColumns + ` FROM planned_starts WHERE id = $1`
+
+	var p models.PlannedStart
+	err := scanPlannedStart(r.db.Pool.QueryRow(ctx, query, id), &p)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("planned start not found")
+	}
+
+	return &p, err
+}
+
+// Update replaces the definition of a planned start. Firing history is reset
+// so an edited one-off start can fire again.
+func (r *PlannedStartRepository) Update(ctx context.Context, p *models.PlannedStart) error {
+	query := `
+		UPDATE planned_starts
+		SET name = $1, start_at = $2, days = $3, start_time = $4, enabled = $5,
+			last_fired_at = NULL, last_result = NULL
+		WHERE id = $6
+		RETURNING updated_at
+	`
+
+	p.LastFiredAt = nil
+	p.LastResult = nil
+	return r.db.Pool.QueryRow(ctx, query,
+		p.Name, p.StartAt, p.Days, p.StartTime, p.Enabled, p.ID,
+	).Scan(&p.UpdatedAt)
+}
+
+func (r *PlannedStartRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM planned_starts WHERE id = $1`, id)
+	return err
+}
+
+func (r *PlannedStartRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.PlannedStart, error) {
+	query := `
+		SELECT ` + plannedStartColumns + `
+		FROM planned_starts
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	return r.query(ctx, query, userID)
+}
+
+// ListEnabled returns every enabled planned start across all users.
+func (r *PlannedStartRepository) ListEnabled(ctx context.Context) ([]models.PlannedStart, error) {
+	query := `SELECT ` + plannedStartColumns + ` FROM planned_starts WHERE enabled`
+
+	return r.query(ctx, query)
+}
+
+// MarkFired records the outcome of a firing. One-off starts are disabled.
+func (r *PlannedStartRepository) MarkFired(ctx context.Context, id uuid.UUID, at time.Time, result string, sessionID *uuid.UUID) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE planned_starts
+		SET last_fired_at = $2, last_result = $3,
+			last_session_id = COALESCE($4, last_session_id),
+			enabled = enabled AND start_at IS NULL
+		WHERE id = $1
+	`, id, at, result, sessionID)
+	return err
+}
+
+func (r *PlannedStartRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.PlannedStart, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	starts := []models.PlannedStart{}
+	for rows.Next() {
+		var p models.PlannedStart
+		if err := scanPlannedStart(rows, &p); err != nil {
+			return nil, err
+		}
+		starts = append(starts, p)
+	}
+
+	return starts, rows.Err()
+}
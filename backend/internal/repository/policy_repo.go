@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"log_book/internal/database"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PolicyRepository struct {
+	db *database.DB
+}
+
+func NewPolicyRepository(db *database.DB) *PolicyRepository {
+	return &PolicyRepository{db: db}
+}
+
+const policyColumns = `id, scope, role, user_id, min_duration_minutes, max_duration_minutes,
+		max_sessions_per_day, allow_future_end, created_at, updated_at`
+
+func scanPolicy(row pgx.Row, p *models.SessionPolicy) error {
+	return row.Scan(
+		&p.ID, &p.Scope, &p.Role, &p.UserID, &p.MinDurationMinutes, &p.MaxDurationMinutes,
+		&p.MaxSessionsPerDay, &p.AllowFutureEnd, &p.CreatedAt, &p.UpdatedAt,
+	)
+}
+
+func (r *PolicyRepository) Create(ctx context.Context, p *models.SessionPolicy) error {
+	query := `
+		INSERT INTO session_policies (scope, role, user_id, min_duration_minutes, max_duration_minutes,
+			max_sessions_per_day, allow_future_end)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		p.Scope, p.Role, p.UserID, p.MinDurationMinutes, p.MaxDurationMinutes,
+		p.MaxSessionsPerDay, p.AllowFutureEnd,
+	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+}
+
+func (r *PolicyRepository) GetByID(ctx context.Context, id string) (*models.SessionPolicy, error) {
+	query := `SELECT ` + policyColumns + ` FROM session_policies WHERE id = $1`
+
+	var p models.SessionPolicy
+	err := scanPolicy(r.db.Pool.QueryRow(ctx, query, id), &p)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("policy not found")
+	}
+
+	return &p, err
+}
+
+// Update replaces the rule fields of a policy. Scope and target are fixed.
+func (r *PolicyRepository) Update(ctx context.Context, p *models.SessionPolicy) error {
+	query := `
+		UPDATE session_policies
+		SET min_duration_minutes = $1, max_duration_minutes = $2,
+			max_sessions_per_day = $3, allow_future_end = $4
+		WHERE id = $5
+		RETURNING updated_at
+	`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		p.MinDurationMinutes, p.MaxDurationMinutes, p.MaxSessionsPerDay, p.AllowFutureEnd, p.ID,
+	).Scan(&p.UpdatedAt)
+}
+
+func (r *PolicyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM session_policies WHERE id = $1`, id)
+	return err
+}
+
+func (r *PolicyRepository) List(ctx context.Context) ([]models.SessionPolicy, error) {
+	query := `
+		SELECT ` + policyColumns + `
+		FROM session_policies
+		ORDER BY CASE scope WHEN 'global' THEN 0 WHEN 'role' THEN 1 ELSE 2 END, created_at
+	`
+
+	return r.query(ctx, query)
+}
+
+// ListApplicable returns the policies that apply to a user, least specific
+// first, so they can be applied in order.
+func (r *PolicyRepository) ListApplicable(ctx context.Context, userID uuid.UUID, role string) ([]models.SessionPolicy, error) {
+	query := `
+		SELECT ` + policyColumns + `
+		FROM session_policies
+		WHERE scope = 'global'
+			OR (scope = 'role' AND role = $2)
+			OR (scope = 'user' AND user_id = $1)
+		ORDER BY CASE scope WHEN 'global' THEN 0 WHEN 'role' THEN 1 ELSE 2 END
+	`
+
+	return r.query(ctx, query, userID, role)
+}
+
+// ShortestMaxDuration returns the smallest max_duration_minutes set on any
+// policy, or nil when no policy sets one.
+func (r *PolicyRepository) ShortestMaxDuration(ctx context.Context) (*int, error) {
+	var minutes *int
+	err := r.db.Pool.QueryRow(ctx, `SELECT MIN(max_duration_minutes) FROM session_policies`).Scan(&minutes)
+	return minutes, err
+}
+
+func (r *PolicyRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.SessionPolicy, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := []models.SessionPolicy{}
+	for rows.Next() {
+		var p models.SessionPolicy
+		if err := scanPolicy(rows, &p); err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+
+	return policies, rows.Err()
+}
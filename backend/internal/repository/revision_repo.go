@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"log_book/internal/database"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const revisionColumns = `id, session_id, requested_by, old_start_time, old_end_time, new_start_time, new_end_time,
+		justification, status, requires_approval, reviewed_by, review_comment, reviewed_at, created_at`
+
+func scanRevision(row pgx.Row, rev *models.SessionRevision) error {
+	return row.Scan(
+		&rev.ID, &rev.SessionID, &rev.RequestedBy, &rev.OldStartTime, &rev.OldEndTime,
+		&rev.NewStartTime, &rev.NewEndTime, &rev.Justification, &rev.Status, &rev.RequiresApproval,
+		&rev.ReviewedBy, &rev.ReviewComment, &rev.ReviewedAt, &rev.CreatedAt,
+	)
+}
+
+// errRevisionNotApplied rolls back a review whose session no longer matches
+// the revision's old times.
+var errRevisionNotApplied = errors.New("revision not applied")
+
+type RevisionRepository struct {
+	db *database.DB
+}
+
+func NewRevisionRepository(db *database.DB) *RevisionRepository {
+	return &RevisionRepository{db: db}
+}
+
+// Create stores a pending revision. The session is left unchanged until the
+// revision is approved.
+func (r *RevisionRepository) Create(ctx context.Context, rev *models.SessionRevision) error {
+	query := `
+		INSERT INTO session_revisions (session_id, requested_by, old_start_time, old_end_time,
+			new_start_time, new_end_time, justification, status, requires_approval)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 'pending', true)
+		RETURNING id, status, requires_approval, created_at
+	`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		rev.SessionID, rev.RequestedBy, rev.OldStartTime, rev.OldEndTime,
+		rev.NewStartTime, rev.NewEndTime, rev.Justification,
+	).Scan(&rev.ID, &rev.Status, &rev.RequiresApproval, &rev.CreatedAt)
+}
+
+// CreateApplied applies a revision that needs no approval and stores it as
+// approved, in one transaction, recording event on the session. Returns
+// false, storing nothing, when the session is no longer completed with the
+// revision's old times.
+func (r *RevisionRepository) CreateApplied(ctx context.Context, rev *models.SessionRevision, event *models.SessionEvent) (bool, error) {
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		if err := applyRevision(ctx, tx, rev, event); err != nil {
+			return err
+		}
+		return tx.QueryRow(ctx, `
+			INSERT INTO session_revisions (session_id, requested_by, old_start_time, old_end_time,
+				new_start_time, new_end_time, justification, status, requires_approval, reviewed_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, 'approved', false, NOW())
+			RETURNING id, status, requires_approval, reviewed_at, created_at
+		`,
+			rev.SessionID, rev.RequestedBy, rev.OldStartTime, rev.OldEndTime,
+			rev.NewStartTime, rev.NewEndTime, rev.Justification,
+		).Scan(&rev.ID, &rev.Status, &rev.RequiresApproval, &rev.ReviewedAt, &rev.CreatedAt)
+	})
+
+	if errors.Is(err, errRevisionNotApplied) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Approve marks a pending revision approved and applies it to the session,
+// recording event, in one transaction. Returns false, changing nothing, when
+// the revision is no longer pending or the session no longer has the
+// revision's old times.
+func (r *RevisionRepository) Approve(ctx context.Context, id uuid.UUID, reviewerID uuid.UUID, comment *string, event *models.SessionEvent) (bool, error) {
+	err := pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		var rev models.SessionRevision
+		err := scanRevision(tx.QueryRow(ctx, `
+			UPDATE session_revisions
+			SET status = 'approved', reviewed_by = $2, review_comment = $3, reviewed_at = NOW()
+			WHERE id = $1 AND status = 'pending'
+			RETURNING `+revisionColumns,
+			id, reviewerID, comment,
+		), &rev)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errRevisionNotApplied
+		}
+		if err != nil {
+			return err
+		}
+		return applyRevision(ctx, tx, &rev, event)
+	})
+
+	if errors.Is(err, errRevisionNotApplied) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Reject marks a pending revision rejected. Returns false when it is no
+// longer pending.
+func (r *RevisionRepository) Reject(ctx context.Context, id uuid.UUID, reviewerID uuid.UUID, comment string) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE session_revisions
+		SET status = 'rejected', reviewed_by = $2, review_comment = $3, reviewed_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`, id, reviewerID, comment)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// applyRevision moves the session to the revision's new times, provided it
+// is still completed with the old ones, and records event. Breaks that fall
+// outside the new times are dropped and those crossing them are clamped.
+func applyRevision(ctx context.Context, tx pgx.Tx, rev *models.SessionRevision, event *models.SessionEvent) error {
+	var applied bool
+	err := tx.QueryRow(ctx, `
+		WITH updated AS (
+			UPDATE time_sessions
+			SET start_time = $4, end_time = $5, end_reason = $6
+			WHERE id = $1 AND status = 'completed' AND start_time = $2 AND end_time = $3
+			RETURNING id
+		), event AS (
+			`+sessionEventInsert("updated", 7)+`
+		)
+		SELECT EXISTS (SELECT 1 FROM updated)
+	`, append([]interface{}{
+		rev.SessionID, rev.OldStartTime, rev.OldEndTime, rev.NewStartTime, rev.NewEndTime, models.EndReasonCorrected,
+	}, sessionEventArgs(event)...)...).Scan(&applied)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errRevisionNotApplied
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM session_breaks
+		WHERE session_id = $1 AND (end_time <= $2 OR start_time >= $3)
+	`, rev.SessionID, rev.NewStartTime, rev.NewEndTime)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE session_breaks
+		SET start_time = GREATEST(start_time, $2), end_time = LEAST(end_time, $3)
+		WHERE session_id = $1 AND (start_time < $2 OR end_time > $3)
+	`, rev.SessionID, rev.NewStartTime, rev.NewEndTime)
+	return err
+}
+
+func (r *RevisionRepository) GetByID(ctx context.Context, id string) (*models.SessionRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM session_revisions WHERE id = $1`
+
+	var rev models.SessionRevision
+	err := scanRevision(r.db.Pool.QueryRow(ctx, query, id), &rev)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("revision not found")
+	}
+
+	return &rev, err
+}
+
+// GetPending returns the session's revision awaiting review, or nil.
+func (r *RevisionRepository) GetPending(ctx context.Context, sessionID uuid.UUID) (*models.SessionRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM session_revisions WHERE session_id = $1 AND status = 'pending'`
+
+	var rev models.SessionRevision
+	err := scanRevision(r.db.Pool.QueryRow(ctx, query, sessionID), &rev)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &rev, err
+}
+
+// ListBySession returns the session's revisions, oldest first.
+func (r *RevisionRepository) ListBySession(ctx context.Context, sessionID uuid.UUID) ([]models.SessionRevision, error) {
+	return r.query(ctx, `
+		SELECT `+revisionColumns+`
+		FROM session_revisions
+		WHERE session_id = $1
+		ORDER BY created_at
+	`, sessionID)
+}
+
+// ListPending returns revisions awaiting review, oldest first.
+func (r *RevisionRepository) ListPending(ctx context.Context) ([]models.SessionRevision, error) {
+	return r.query(ctx, `
+		SELECT `+revisionColumns+`
+		FROM session_revisions
+		WHERE status = 'pending'
+		ORDER BY created_at
+	`)
+}
+
+func (r *RevisionRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.SessionRevision, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.SessionRevision{}
+	for rows.Next() {
+		var rev models.SessionRevision
+		if err := scanRevision(rows, &rev); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, rows.Err()
+}
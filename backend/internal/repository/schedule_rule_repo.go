@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"log_book/internal/database"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type ScheduleRuleRepository struct {
+	db *database.DB
+}
+
+func NewScheduleRuleRepository(db *database.DB) *ScheduleRuleRepository {
+	return &ScheduleRuleRepository{db: db}
+}
+
+const scheduleRuleColumns = `id, user_id, COALESCE(name, ''), days, stop_time, enabled, created_at, updated_at`
+
+func scanScheduleRule(row pgx.Row, rule *models.ScheduleRule) error {
+	return row.Scan(
+		&rule.ID, &rule.UserID, &rule.Name, &rule.Days, &rule.StopTime,
+		&rule.Enabled, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+}
+
+func (r *ScheduleRuleRepository) Create(ctx context.Context, rule *models.ScheduleRule) error {
+	query := `
+		INSERT INTO schedule_rules (user_id, name, days, stop_time, enabled)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		rule.UserID, rule.Name, rule.Days, rule.StopTime, rule.Enabled,
+	).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+func (r *ScheduleRuleRepository) GetByID(ctx context.Context, id string) (*models.ScheduleRule, error) {
+	query := `SELECT ` + scheduleRuleColumns + ` FROM schedule_rules WHERE id = $1`
+
+	var rule models.ScheduleRule
+	err := scanScheduleRule(r.db.Pool.QueryRow(ctx, query, id), &rule)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("schedule rule not found")
+	}
+
+	return &rule, err
+}
+
+func (r *ScheduleRuleRepository) Update(ctx context.Context, rule *models.ScheduleRule) error {
+	query := `
+		UPDATE schedule_rules
+		SET name = $1, days = $2, stop_time = $3, enabled = $4
+		WHERE id = $5
+		RETURNING updated_at
+	`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		rule.Name, rule.Days, rule.StopTime, rule.Enabled, rule.ID,
+	).Scan(&rule.UpdatedAt)
+}
+
+func (r *ScheduleRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM schedule_rules WHERE id = $1`, id)
+	return err
+}
+
+// ListByUser returns the user's rules. With enabledOnly set, disabled rules
+// are skipped.
+func (r *ScheduleRuleRepository) ListByUser(ctx context.Context, userID uuid.UUID, enabledOnly bool) ([]models.ScheduleRule, error) {
+	query := `
+		SELECT ` + scheduleRuleColumns + `
+		FROM schedule_rules
+		WHERE user_id = $1 AND (enabled OR NOT $2)
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID, enabledOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.ScheduleRule{}
+	for rows.Next() {
+		var rule models.ScheduleRule
+		if err := scanScheduleRule(rows, &rule); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -13,6 +14,42 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// sessionColumns is the column list shared by every session SELECT. Break time
+// is summed from session_breaks, clamped to the session's end, and an open
+// break is counted up to now.
+const sessionColumns = `id, user_id, start_time, end_time, scheduled_end, status, device_id, created_at,
+		last_heartbeat_at, idle_detected_at, end_reason, cancel_reason, cancelled_at,
+		COALESCE((
+			SELECT SUM(GREATEST(EXTRACT(EPOCH FROM (
+				LEAST(COALESCE(b.end_time, NOW()), COALESCE(time_sessions.end_time, NOW())) - b.start_time
+			)), 0))
+			FROM session_breaks b
+			WHERE b.session_id = time_sessions.id
+		), 0)::BIGINT AS break_seconds,
+		EXISTS(
+			SELECT 1 FROM session_breaks b
+			WHERE b.session_id = time_sessions.id AND b.end_time IS NULL
+		) AS paused,
+		employer_id`
+
+// scanSession scans a row selected with sessionColumns and fills in the
+// derived net duration.
+func scanSession(row pgx.Row, session *models.TimeSession) error {
+	err := row.Scan(
+		&session.ID, &session.UserID, &session.StartTime, &session.EndTime,
+		&session.ScheduledEnd, &session.Status, &session.DeviceID, &session.CreatedAt,
+		&session.LastHeartbeatAt, &session.IdleDetectedAt, &session.EndReason,
+		&session.CancelReason, &session.CancelledAt,
+		&session.BreakSeconds, &session.Paused, &session.EmployerID,
+	)
+	if err != nil {
+		return err
+	}
+
+	session.NetSeconds = int64(session.NetDuration(time.Now().UTC()).Seconds())
+	return nil
+}
+
 type SessionRepository struct {
 	db *database.DB
 }
@@ -21,42 +58,126 @@ func NewSessionRepository(db *database.DB) *SessionRepository {
 	return &SessionRepository{db: db}
 }
 
-func (r *SessionRepository) Create(ctx context.Context, session *models.TimeSession) error {
+// Create stores a new session and records event for it in the same
+// statement.
+func (r *SessionRepository) Create(ctx context.Context, session *models.TimeSession, event *models.SessionEvent) error {
 	query := `
-		INSERT INTO time_sessions (user_id, start_time, status, device_id)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at
+		WITH created AS (
+			INSERT INTO time_sessions (user_id, start_time, status, device_id, employer_id)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at
+		), event AS (
+			` + sessionEventInsert("created", 6) + `
+		)
+		SELECT id, created_at FROM created
 	`
 
-	return r.db.Pool.QueryRow(ctx, query,
-		session.UserID, session.StartTime, session.Status, session.DeviceID,
-	).Scan(&session.ID, &session.CreatedAt)
+	args := append([]interface{}{
+		session.UserID, session.StartTime, session.Status, session.DeviceID, session.EmployerID,
+	}, sessionEventArgs(event)...)
+	return r.db.Pool.QueryRow(ctx, query, args...).Scan(&session.ID, &session.CreatedAt)
 }
 
-func (r *SessionRepository) CreateManual(ctx context.Context, session *models.TimeSession) error {
-	query := `
-		INSERT INTO time_sessions (user_id, start_time, end_time, status, device_id)
-		VALUES ($1, $2, $3, $4, $5)
+var insertManualSession = `
+	WITH created AS (
+		INSERT INTO time_sessions (user_id, start_time, end_time, status, device_id, end_reason, employer_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id, created_at
-	`
+	), event AS (
+		` + sessionEventInsert("created", 8) + `
+	)
+	SELECT id, created_at FROM created
+`
+
+// manualSessionArgs returns the parameters of insertManualSession.
+func manualSessionArgs(session *models.TimeSession, event *models.SessionEvent) []interface{} {
+	return append([]interface{}{
+		session.UserID, session.StartTime, session.EndTime, session.Status, session.DeviceID, session.EndReason,
+		session.EmployerID,
+	}, sessionEventArgs(event)...)
+}
 
-	return r.db.Pool.QueryRow(ctx, query,
-		session.UserID, session.StartTime, session.EndTime, session.Status, session.DeviceID,
-	).Scan(&session.ID, &session.CreatedAt)
+// CreateManual stores a completed session and records event for it.
+func (r *SessionRepository) CreateManual(ctx context.Context, session *models.TimeSession, event *models.SessionEvent) error {
+	return r.db.Pool.QueryRow(ctx, insertManualSession, manualSessionArgs(session, event)...).
+		Scan(&session.ID, &session.CreatedAt)
+}
+
+// CreateManualBatch inserts completed sessions in a single transaction, so
+// either all of them are stored or none are. event is recorded for each.
+func (r *SessionRepository) CreateManualBatch(ctx context.Context, sessions []*models.TimeSession, event *models.SessionEvent) error {
+	return pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		for _, session := range sessions {
+			err := tx.QueryRow(ctx, insertManualSession, manualSessionArgs(session, event)...).
+				Scan(&session.ID, &session.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CreateImported stores completed sessions with their breaks and documents
+// in a single transaction, recording event for each session. A document for
+// a date that already has one is not stored, and its Document is set to nil.
+func (r *SessionRepository) CreateImported(ctx context.Context, imports []models.ImportedSession, event *models.SessionEvent) error {
+	return pgx.BeginFunc(ctx, r.db.Pool, func(tx pgx.Tx) error {
+		for i := range imports {
+			imp := &imports[i]
+			session := imp.Session
+			err := tx.QueryRow(ctx, insertManualSession, manualSessionArgs(session, event)...).
+				Scan(&session.ID, &session.CreatedAt)
+			if err != nil {
+				return err
+			}
+
+			for j := range imp.Breaks {
+				b := &imp.Breaks[j]
+				b.SessionID = session.ID
+				err := tx.QueryRow(ctx, `
+					INSERT INTO session_breaks (session_id, start_time, end_time)
+					VALUES ($1, $2, $3)
+					RETURNING id, created_at
+				`, b.SessionID, b.StartTime, b.EndTime).Scan(&b.ID, &b.CreatedAt)
+				if err != nil {
+					return err
+				}
+			}
+
+			if imp.Document == nil {
+				continue
+			}
+			doc := imp.Document
+			doc.SessionID = &session.ID
+			err = tx.QueryRow(ctx, `
+				INSERT INTO documents (user_id, session_id, employer_id, log_date, title, content)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (user_id, log_date) DO NOTHING
+				RETURNING id, created_at, updated_at
+			`, doc.UserID, doc.SessionID, doc.EmployerID, doc.LogDate, doc.Title, doc.Content,
+			).Scan(&doc.ID, &doc.CreatedAt, &doc.UpdatedAt)
+			if errors.Is(err, pgx.ErrNoRows) {
+				imp.Document = nil
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *SessionRepository) GetByID(ctx context.Context, id string) (*models.TimeSession, error) {
 	query := `
-		SELECT id, user_id, start_time, end_time, scheduled_end, status, device_id, created_at
+		SELECT ` + sessionColumns + `
 		FROM time_sessions
 		WHERE id = $1
 	`
 
 	var session models.TimeSession
-	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
-		&session.ID, &session.UserID, &session.StartTime, &session.EndTime,
-		&session.ScheduledEnd, &session.Status, &session.DeviceID, &session.CreatedAt,
-	)
+	err := scanSession(r.db.Pool.QueryRow(ctx, query, id), &session)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, errors.New("session not found")
@@ -67,7 +188,7 @@ func (r *SessionRepository) GetByID(ctx context.Context, id string) (*models.Tim
 
 func (r *SessionRepository) GetActiveSession(ctx context.Context, userID uuid.UUID) (*models.TimeSession, error) {
 	query := `
-		SELECT id, user_id, start_time, end_time, scheduled_end, status, device_id, created_at
+		SELECT ` + sessionColumns + `
 		FROM time_sessions
 		WHERE user_id = $1 AND status = 'active'
 		ORDER BY created_at DESC
@@ -75,10 +196,7 @@ func (r *SessionRepository) GetActiveSession(ctx context.Context, userID uuid.UU
 	`
 
 	var session models.TimeSession
-	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(
-		&session.ID, &session.UserID, &session.StartTime, &session.EndTime,
-		&session.ScheduledEnd, &session.Status, &session.DeviceID, &session.CreatedAt,
-	)
+	err := scanSession(r.db.Pool.QueryRow(ctx, query, userID), &session)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -87,57 +205,350 @@ func (r *SessionRepository) GetActiveSession(ctx context.Context, userID uuid.UU
 	return &session, err
 }
 
-func (r *SessionRepository) HasSessionOnDate(ctx context.Context, userID uuid.UUID, date time.Time) (bool, error) {
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+// HasSessionOnDate reports whether a session starts on the calendar day that
+// contains date, with day boundaries taken in loc.
+func (r *SessionRepository) HasSessionOnDate(ctx context.Context, userID uuid.UUID, date time.Time, loc *time.Location) (bool, error) {
+	count, err := r.CountSessionsOnDate(ctx, userID, date, loc)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountSessionsOnDate counts sessions starting on the calendar day that
+// contains date, with day boundaries taken in loc. Cancelled sessions do not
+// count.
+func (r *SessionRepository) CountSessionsOnDate(ctx context.Context, userID uuid.UUID, date time.Time, loc *time.Location) (int, error) {
+	local := date.In(loc)
+	startOfDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
 	endOfDay := startOfDay.AddDate(0, 0, 1)
 
 	var count int
 	err := r.db.Pool.QueryRow(ctx, `
 		SELECT COUNT(*) FROM time_sessions
 		WHERE user_id = $1 AND start_time >= $2 AND start_time < $3
+			AND status <> 'cancelled'
 	`, userID, startOfDay, endOfDay).Scan(&count)
+	return count, err
+}
+
+// CountSessionsByDate counts non-cancelled sessions per calendar day in loc
+// for the days from..to inclusive. Days are keyed as midnight UTC; days
+// without sessions are absent.
+func (r *SessionRepository) CountSessionsByDate(ctx context.Context, userID uuid.UUID, from, to time.Time, loc *time.Location) (map[time.Time]int, error) {
+	query := `
+		SELECT (start_time AT TIME ZONE $4)::date AS d, COUNT(*)
+		FROM time_sessions
+		WHERE user_id = $1 AND status <> 'cancelled'
+			AND (start_time AT TIME ZONE $4)::date BETWEEN $2 AND $3
+		GROUP BY d
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID, from.Format("2006-01-02"), to.Format("2006-01-02"), loc.String())
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	return count > 0, nil
+	defer rows.Close()
+
+	counts := make(map[time.Time]int)
+	for rows.Next() {
+		var d time.Time
+		var count int
+		if err := rows.Scan(&d, &count); err != nil {
+			return nil, err
+		}
+		counts[d] = count
+	}
+
+	return counts, rows.Err()
 }
 
-func (r *SessionRepository) Update(ctx context.Context, session *models.TimeSession) error {
+// ListDays returns the user's session time per calendar day for the days
+// from..to inclusive (midnight UTC dates), ordered by day. A session across
+// midnight yields one row per day. status, if set, keeps only sessions in
+// that status; employerID, if set, only that employer's sessions.
+func (r *SessionRepository) ListDays(ctx context.Context, userID uuid.UUID, from, to time.Time, status string, employerID *uuid.UUID) ([]models.SessionDay, error) {
 	query := `
-		UPDATE time_sessions
-		SET end_time = $1, scheduled_end = $2, status = $3
-		WHERE id = $4
+		SELECT session_id, day, net_seconds
+		FROM session_daily_hours
+		WHERE user_id = $1 AND day BETWEEN $2 AND $3
+			AND ($4 = '' OR status = $4)
+			AND ($5::uuid IS NULL OR employer_id = $5)
+		ORDER BY day, session_id
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query,
-		session.EndTime, session.ScheduledEnd, session.Status, session.ID,
-	)
-	return err
+	rows, err := r.db.Pool.Query(ctx, query, userID, from.Format("2006-01-02"), to.Format("2006-01-02"), status, employerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := []models.SessionDay{}
+	for rows.Next() {
+		var day models.SessionDay
+		if err := rows.Scan(&day.SessionID, &day.Date, &day.NetSeconds); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
+
+// ListCompletedBetween returns the user's completed sessions with time in
+// [from, to), including those that started before from or end after to,
+// oldest first.
+func (r *SessionRepository) ListCompletedBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.TimeSession, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM time_sessions
+		WHERE user_id = $1 AND status = 'completed'
+			AND start_time < $3 AND end_time > $2
+		ORDER BY start_time
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []models.TimeSession
+	for rows.Next() {
+		var session models.TimeSession
+		if err := scanSession(rows, &session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// ListOverlapping returns the user's sessions that overlap [start, end),
+// oldest first, by the same rule as the time_sessions_no_overlap constraint:
+// a nil end or an active session's missing end time means open-ended, and
+// cancelled sessions are ignored. excludeID, if set, is left out.
+func (r *SessionRepository) ListOverlapping(ctx context.Context, userID uuid.UUID, start time.Time, end *time.Time, excludeID *uuid.UUID) ([]models.TimeSession, error) {
+	return r.query(ctx, `
+		SELECT `+sessionColumns+`
+		FROM time_sessions
+		WHERE user_id = $1 AND status <> 'cancelled'
+			AND ($4::uuid IS NULL OR id <> $4)
+			AND tstzrange(start_time, COALESCE(end_time, 'infinity'))
+				&& tstzrange($2::timestamptz, COALESCE($3::timestamptz, 'infinity'))
+		ORDER BY start_time
+	`, userID, start, end, excludeID)
+}
+
+// SetScheduledEnd sets or clears the scheduled end of an active session and
+// records event. Returns false when the session is no longer active.
+func (r *SessionRepository) SetScheduledEnd(ctx context.Context, id uuid.UUID, scheduledEnd *time.Time, event *models.SessionEvent) (bool, error) {
+	query := `
+		WITH updated AS (
+			UPDATE time_sessions SET scheduled_end = $2
+			WHERE id = $1 AND status = 'active'
+			RETURNING id
+		), event AS (
+			` + sessionEventInsert("updated", 3) + `
+		)
+		SELECT EXISTS (SELECT 1 FROM updated)
+	`
+
+	var ok bool
+	err := r.db.Pool.QueryRow(ctx, query, append([]interface{}{id, scheduledEnd}, sessionEventArgs(event)...)...).Scan(&ok)
+	return ok, err
+}
+
+// Cancel marks a session cancelled if it is still in fromStatus, recording
+// the reason and event. Sessions without an end time are closed at endTime,
+// along with an open break. Returns false when the session was no longer in
+// fromStatus.
+func (r *SessionRepository) Cancel(ctx context.Context, id uuid.UUID, fromStatus string, endTime time.Time, reason string, event *models.SessionEvent) (bool, error) {
+	query := `
+		WITH cancelled AS (
+			UPDATE time_sessions
+			SET status = 'cancelled', end_time = COALESCE(end_time, $3),
+				cancel_reason = $4, cancelled_at = NOW()
+			WHERE id = $1 AND status = $2
+			RETURNING id, end_time
+		), closed AS (
+			UPDATE session_breaks b
+			SET end_time = GREATEST(b.start_time, c.end_time)
+			FROM cancelled c
+			WHERE b.session_id = c.id AND b.end_time IS NULL
+		), event AS (
+			` + sessionEventInsert("cancelled", 5) + `
+		)
+		SELECT EXISTS (SELECT 1 FROM cancelled)
+	`
+
+	var ok bool
+	err := r.db.Pool.QueryRow(ctx, query, append([]interface{}{id, fromStatus, endTime, reason}, sessionEventArgs(event)...)...).Scan(&ok)
+	return ok, err
+}
+
+// ListByUser returns a page of the user's sessions. from_date and to_date are
+// calendar days interpreted in loc.
+func (r *SessionRepository) ListByUser(ctx context.Context, userID uuid.UUID, params models.SessionListParams, loc *time.Location) ([]models.TimeSession, int, error) {
+	filterSQL, filterArgs := sessionListFilters(userID, params, loc)
+	argIndex := len(filterArgs) + 1
+
+	// Count total
+	countQuery := `SELECT COUNT(*) FROM time_sessions WHERE user_id = $1` + filterSQL
+	var total int
+	err := r.db.Pool.QueryRow(ctx, countQuery, filterArgs...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated results
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM time_sessions
+		WHERE user_id = $1
+	` + filterSQL
+
+	args := make([]interface{}, len(filterArgs))
+	copy(args, filterArgs)
+
+	query += fmt.Sprintf(` ORDER BY start_time DESC LIMIT $%d OFFSET $%d`, argIndex, argIndex+1)
+	args = append(args, params.PerPage, (params.Page-1)*params.PerPage)
+
+	sessions, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return sessions, total, nil
+}
+
+// ListAllByUser returns every session matching the same filters as
+// ListByUser, oldest first and without pagination. Page and PerPage are
+// ignored.
+func (r *SessionRepository) ListAllByUser(ctx context.Context, userID uuid.UUID, params models.SessionListParams, loc *time.Location) ([]models.TimeSession, error) {
+	filterSQL, args := sessionListFilters(userID, params, loc)
+
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM time_sessions
+		WHERE user_id = $1
+	` + filterSQL + `
+		ORDER BY start_time ASC
+	`
+
+	return r.query(ctx, query, args...)
+}
+
+// EachByUser calls fn for every session matching the same filters as
+// ListByUser, oldest first, while reading rows from the database. Returning
+// an error from fn stops the iteration.
+func (r *SessionRepository) EachByUser(ctx context.Context, userID uuid.UUID, params models.SessionListParams, loc *time.Location, fn func(*models.TimeSession) error) error {
+	filterSQL, args := sessionListFilters(userID, params, loc)
+
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM time_sessions
+		WHERE user_id = $1
+	` + filterSQL + `
+		ORDER BY start_time ASC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var session models.TimeSession
+		if err := scanSession(rows, &session); err != nil {
+			return err
+		}
+		if err := fn(&session); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
 }
 
-func (r *SessionRepository) ListByUser(ctx context.Context, userID uuid.UUID, params models.SessionListParams) ([]models.TimeSession, int, error) {
-	// Build shared WHERE filters
+// ListForCalendar returns the user's completed and active sessions that
+// started at or after since, oldest first, each with the earliest document
+// logged against it.
+func (r *SessionRepository) ListForCalendar(ctx context.Context, userID uuid.UUID, since time.Time) ([]models.CalendarSession, error) {
+	query := `
+		SELECT ` + sessionColumns + `, doc.document_id, doc.document_title
+		FROM time_sessions
+		LEFT JOIN LATERAL (
+			SELECT d.id AS document_id, d.title AS document_title
+			FROM documents d
+			WHERE d.session_id = time_sessions.id
+			ORDER BY d.created_at
+			LIMIT 1
+		) doc ON TRUE
+		WHERE time_sessions.user_id = $1
+			AND time_sessions.status IN ('completed', 'active')
+			AND time_sessions.start_time >= $2
+		ORDER BY time_sessions.start_time
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []models.CalendarSession
+	for rows.Next() {
+		var s models.CalendarSession
+		err := rows.Scan(
+			&s.ID, &s.UserID, &s.StartTime, &s.EndTime,
+			&s.ScheduledEnd, &s.Status, &s.DeviceID, &s.CreatedAt,
+			&s.LastHeartbeatAt, &s.IdleDetectedAt, &s.EndReason,
+			&s.CancelReason, &s.CancelledAt,
+			&s.BreakSeconds, &s.Paused, &s.EmployerID,
+			&s.DocumentID, &s.DocumentTitle,
+		)
+		if err != nil {
+			return nil, err
+		}
+		s.NetSeconds = int64(s.NetDuration(time.Now().UTC()).Seconds())
+		sessions = append(sessions, s)
+	}
+
+	return sessions, rows.Err()
+}
+
+// sessionListFilters builds the WHERE filters shared by ListByUser,
+// ListAllByUser and EachByUser. userID is always $1. The date range keeps
+// sessions with any time on those days, so one across midnight is listed
+// under both.
+func sessionListFilters(userID uuid.UUID, params models.SessionListParams, loc *time.Location) (string, []interface{}) {
 	filterSQL := ""
 	filterArgs := []interface{}{userID}
 	argIndex := 2
 
+	// Cancelled sessions are only listed when asked for explicitly
 	if params.Status != "" {
 		filterSQL += fmt.Sprintf(` AND status = $%d`, argIndex)
 		filterArgs = append(filterArgs, params.Status)
 		argIndex++
+	} else {
+		filterSQL += ` AND status <> 'cancelled'`
 	}
 
 	if params.FromDate != "" {
-		fromTime, err := time.Parse("2006-01-02", params.FromDate)
+		fromTime, err := time.ParseInLocation("2006-01-02", params.FromDate, loc)
 		if err == nil {
-			filterSQL += fmt.Sprintf(` AND start_time >= $%d`, argIndex)
+			filterSQL += fmt.Sprintf(` AND COALESCE(end_time, NOW()) > $%d`, argIndex)
 			filterArgs = append(filterArgs, fromTime.UTC())
 			argIndex++
 		}
 	}
 
 	if params.ToDate != "" {
-		toTime, err := time.Parse("2006-01-02", params.ToDate)
+		toTime, err := time.ParseInLocation("2006-01-02", params.ToDate, loc)
 		if err == nil {
 			// End of the to_date day
 			nextDay := toTime.AddDate(0, 0, 1)
@@ -147,61 +558,163 @@ func (r *SessionRepository) ListByUser(ctx context.Context, userID uuid.UUID, pa
 		}
 	}
 
-	// Count total
-	countQuery := `SELECT COUNT(*) FROM time_sessions WHERE user_id = $1` + filterSQL
-	var total int
-	err := r.db.Pool.QueryRow(ctx, countQuery, filterArgs...).Scan(&total)
+	if params.EmployerID != "" {
+		filterSQL += fmt.Sprintf(` AND employer_id = $%d`, argIndex)
+		filterArgs = append(filterArgs, params.EmployerID)
+	}
+
+	return filterSQL, filterArgs
+}
+
+func (r *SessionRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.TimeSession, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
+	defer rows.Close()
 
-	// Get paginated results
+	var sessions []models.TimeSession
+	for rows.Next() {
+		var session models.TimeSession
+		if err := scanSession(rows, &session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// StopDueScheduled completes every active session whose scheduled end has
+// passed, ending at the scheduled time, closes their open breaks and records
+// event for each. It is a single statement, so a session stopped concurrently
+// by its user or another instance is never touched twice. Returns the IDs of
+// the stopped sessions.
+func (r *SessionRepository) StopDueScheduled(ctx context.Context, now time.Time, event *models.SessionEvent) ([]uuid.UUID, error) {
 	query := `
-		SELECT id, user_id, start_time, end_time, scheduled_end, status, device_id, created_at
-		FROM time_sessions
-		WHERE user_id = $1
-	` + filterSQL
+		WITH stopped AS (
+			UPDATE time_sessions
+			SET status = 'completed', end_time = scheduled_end, end_reason = 'scheduled'
+			WHERE status = 'active' AND scheduled_end <= $1
+			RETURNING id, scheduled_end
+		), closed AS (
+			UPDATE session_breaks b
+			SET end_time = GREATEST(b.start_time, stopped.scheduled_end)
+			FROM stopped
+			WHERE b.session_id = stopped.id AND b.end_time IS NULL
+		), event AS (
+			` + sessionEventInsert("stopped", 2) + `
+		)
+		SELECT id FROM stopped
+	`
 
-	args := make([]interface{}, len(filterArgs))
-	copy(args, filterArgs)
+	rows, err := r.db.Pool.Query(ctx, query, append([]interface{}{now}, sessionEventArgs(event)...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	query += fmt.Sprintf(` ORDER BY start_time DESC LIMIT $%d OFFSET $%d`, argIndex, argIndex+1)
-	args = append(args, params.PerPage, (params.Page-1)*params.PerPage)
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
 
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	return ids, rows.Err()
+}
+
+// RecordHeartbeat stores the latest heartbeat of an active session and clears
+// any earlier idle flag. Returns false when the session is no longer active.
+func (r *SessionRepository) RecordHeartbeat(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	query := `
+		UPDATE time_sessions
+		SET last_heartbeat_at = $2, idle_detected_at = NULL
+		WHERE id = $1 AND status = 'active'
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, id, at)
 	if err != nil {
-		return nil, 0, err
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetIdleSessions returns running, unpaused sessions whose last heartbeat is
+// older than cutoff and that have not been handled yet. Sessions that never
+// sent a heartbeat are left alone.
+func (r *SessionRepository) GetIdleSessions(ctx context.Context, cutoff time.Time) ([]models.TimeSession, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM time_sessions
+		WHERE status = 'active'
+			AND last_heartbeat_at < $1
+			AND idle_detected_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM session_breaks b
+				WHERE b.session_id = time_sessions.id AND b.end_time IS NULL
+			)
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, cutoff)
+	if err != nil {
+		return nil, err
 	}
 	defer rows.Close()
 
 	var sessions []models.TimeSession
 	for rows.Next() {
 		var session models.TimeSession
-		err := rows.Scan(
-			&session.ID, &session.UserID, &session.StartTime, &session.EndTime,
-			&session.ScheduledEnd, &session.Status, &session.DeviceID, &session.CreatedAt,
-		)
-		if err != nil {
-			return nil, 0, err
+		if err := scanSession(rows, &session); err != nil {
+			return nil, err
 		}
 		sessions = append(sessions, session)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, 0, err
-	}
+	return sessions, rows.Err()
+}
 
-	return sessions, total, nil
+// FlagIdle marks an active session as idle without stopping it.
+func (r *SessionRepository) FlagIdle(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE time_sessions SET idle_detected_at = NOW()
+		WHERE id = $1 AND status = 'active'
+	`, id)
+	return err
+}
+
+// StopIdle completes an idle session at its last heartbeat and records
+// event. Returns false if the session was stopped by someone else first.
+func (r *SessionRepository) StopIdle(ctx context.Context, id uuid.UUID, event *models.SessionEvent) (bool, error) {
+	query := `
+		WITH stopped AS (
+			UPDATE time_sessions
+			SET status = 'completed', end_time = GREATEST(start_time, last_heartbeat_at),
+				idle_detected_at = NOW(), end_reason = 'idle'
+			WHERE id = $1 AND status = 'active' AND last_heartbeat_at IS NOT NULL
+			RETURNING id
+		), event AS (
+			` + sessionEventInsert("stopped", 2) + `
+		)
+		SELECT EXISTS (SELECT 1 FROM stopped)
+	`
+
+	var ok bool
+	err := r.db.Pool.QueryRow(ctx, query, append([]interface{}{id}, sessionEventArgs(event)...)...).Scan(&ok)
+	return ok, err
 }
 
-func (r *SessionRepository) GetDueScheduledSessions(ctx context.Context) ([]models.TimeSession, error) {
+// GetActiveStartedBefore returns active sessions that started before cutoff.
+func (r *SessionRepository) GetActiveStartedBefore(ctx context.Context, cutoff time.Time) ([]models.TimeSession, error) {
 	query := `
-		SELECT id, user_id, start_time, end_time, scheduled_end, status, device_id, created_at
+		SELECT ` + sessionColumns + `
 		FROM time_sessions
-		WHERE scheduled_end <= $1 AND status = 'active'
+		WHERE status = 'active' AND start_time < $1
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query, time.Now().UTC())
+	rows, err := r.db.Pool.Query(ctx, query, cutoff)
 	if err != nil {
 		return nil, err
 	}
@@ -210,19 +723,109 @@ func (r *SessionRepository) GetDueScheduledSessions(ctx context.Context) ([]mode
 	var sessions []models.TimeSession
 	for rows.Next() {
 		var session models.TimeSession
-		err := rows.Scan(
-			&session.ID, &session.UserID, &session.StartTime, &session.EndTime,
-			&session.ScheduledEnd, &session.Status, &session.DeviceID, &session.CreatedAt,
-		)
-		if err != nil {
+		if err := scanSession(rows, &session); err != nil {
 			return nil, err
 		}
 		sessions = append(sessions, session)
 	}
 
-	if err := rows.Err(); err != nil {
+	return sessions, rows.Err()
+}
+
+// Complete stops an active session at endTime with the given end reason,
+// closes its open break, if any, and records event. Returns false if the
+// session was no longer active.
+func (r *SessionRepository) Complete(ctx context.Context, id uuid.UUID, endTime time.Time, reason string, event *models.SessionEvent) (bool, error) {
+	query := `
+		WITH stopped AS (
+			UPDATE time_sessions
+			SET status = 'completed', end_time = $2, end_reason = $3
+			WHERE id = $1 AND status = 'active'
+			RETURNING id
+		), closed AS (
+			UPDATE session_breaks
+			SET end_time = GREATEST(start_time, $2)
+			WHERE session_id IN (SELECT id FROM stopped) AND end_time IS NULL
+		), event AS (
+			` + sessionEventInsert("stopped", 4) + `
+		)
+		SELECT EXISTS (SELECT 1 FROM stopped)
+	`
+
+	var ok bool
+	err := r.db.Pool.QueryRow(ctx, query, append([]interface{}{id, endTime, reason}, sessionEventArgs(event)...)...).Scan(&ok)
+	return ok, err
+}
+
+// UpdateEndTime changes the end time and end reason of a completed session
+// and records event. Returns false if the session is not completed.
+func (r *SessionRepository) UpdateEndTime(ctx context.Context, id uuid.UUID, endTime time.Time, reason string, event *models.SessionEvent) (bool, error) {
+	query := `
+		WITH updated AS (
+			UPDATE time_sessions SET end_time = $2, end_reason = $3
+			WHERE id = $1 AND status = 'completed'
+			RETURNING id
+		), event AS (
+			` + sessionEventInsert("updated", 4) + `
+		)
+		SELECT EXISTS (SELECT 1 FROM updated)
+	`
+
+	var ok bool
+	err := r.db.Pool.QueryRow(ctx, query, append([]interface{}{id, endTime, reason}, sessionEventArgs(event)...)...).Scan(&ok)
+	return ok, err
+}
+
+// sessionEventInsert returns an INSERT recording an event for every row of
+// source, a CTE returning session ids as id. The event's values are the seven
+// parameters from $n on, in the order of sessionEventArgs.
+func sessionEventInsert(source string, n int) string {
+	return fmt.Sprintf(`INSERT INTO session_events (session_id, event, from_status, to_status, actor, actor_user_id,
+				device_id, details)
+			SELECT id, $%d, $%d, $%d, $%d, $%d, NULLIF($%d, ''), NULLIF($%d, '')::jsonb FROM %s`,
+		n, n+1, n+2, n+3, n+4, n+5, n+6, source)
+}
+
+func sessionEventArgs(event *models.SessionEvent) []interface{} {
+	deviceID := ""
+	if event.DeviceID != nil {
+		deviceID = *event.DeviceID
+	}
+	return []interface{}{
+		event.Event, event.FromStatus, event.ToStatus, event.Actor, event.ActorUserID, deviceID, string(event.Details),
+	}
+}
+
+// ListEvents returns a session's state changes, oldest first.
+func (r *SessionRepository) ListEvents(ctx context.Context, sessionID uuid.UUID) ([]models.SessionEvent, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, session_id, event, from_status, to_status, actor, actor_user_id, device_id,
+			details::text, created_at
+		FROM session_events
+		WHERE session_id = $1
+		ORDER BY created_at, id
+	`, sessionID)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	events := []models.SessionEvent{}
+	for rows.Next() {
+		var e models.SessionEvent
+		var details *string
+		err := rows.Scan(
+			&e.ID, &e.SessionID, &e.Event, &e.FromStatus, &e.ToStatus, &e.Actor, &e.ActorUserID, &e.DeviceID,
+			&details, &e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if details != nil {
+			e.Details = json.RawMessage(*details)
+		}
+		events = append(events, e)
+	}
 
-	return sessions, nil
+	return events, rows.Err()
 }
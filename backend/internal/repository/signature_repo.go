@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"log_book/internal/database"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type SignatureRepository struct {
+	db *database.DB
+}
+
+func NewSignatureRepository(db *database.DB) *SignatureRepository {
+	return &SignatureRepository{db: db}
+}
+
+const signatureColumns = `id, timesheet_id, requested_by, supervisor_email, recipient_overridden, expires_at, revoked_at,
+		signed_name, signed_ip, signed_user_agent, signed_at, created_at`
+
+func scanSignature(row pgx.Row, r *models.SignatureRequest) error {
+	return row.Scan(
+		&r.ID, &r.TimesheetID, &r.RequestedBy, &r.SupervisorEmail, &r.RecipientOverridden, &r.ExpiresAt, &r.RevokedAt,
+		&r.SignedName, &r.SignedIP, &r.SignedUserAgent, &r.SignedAt, &r.CreatedAt,
+	)
+}
+
+// Create inserts a signature request and revokes the timesheet's earlier
+// unused ones, so only the latest link works.
+func (r *SignatureRepository) Create(ctx context.Context, req *models.SignatureRequest) error {
+	query := `
+		WITH revoked AS (
+			UPDATE signature_requests
+			SET revoked_at = NOW()
+			WHERE timesheet_id = $1 AND signed_at IS NULL AND revoked_at IS NULL
+		)
+		INSERT INTO signature_requests (timesheet_id, requested_by, supervisor_email, recipient_overridden, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		req.TimesheetID, req.RequestedBy, req.SupervisorEmail, req.RecipientOverridden, req.ExpiresAt,
+	).Scan(&req.ID, &req.CreatedAt)
+}
+
+func (r *SignatureRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SignatureRequest, error) {
+	query := `SELECT ` + signatureColumns + ` FROM signature_requests WHERE id = $1`
+
+	var req models.SignatureRequest
+	err := scanSignature(r.db.Pool.QueryRow(ctx, query, id), &req)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("signature request not found")
+	}
+
+	return &req, err
+}
+
+// ListByTimesheet returns a timesheet's signature requests, newest first.
+func (r *SignatureRepository) ListByTimesheet(ctx context.Context, timesheetID uuid.UUID) ([]models.SignatureRequest, error) {
+	query := `
+		SELECT ` + signatureColumns + `
+		FROM signature_requests
+		WHERE timesheet_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, timesheetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []models.SignatureRequest{}
+	for rows.Next() {
+		var req models.SignatureRequest
+		if err := scanSignature(rows, &req); err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, rows.Err()
+}
+
+// Sign records the attestation and approves the timesheet in one statement.
+// It only succeeds while the request is unused, unrevoked and unexpired and
+// the timesheet is still submitted; otherwise it returns false.
+func (r *SignatureRepository) Sign(ctx context.Context, id uuid.UUID, name, ip, userAgent string) (bool, error) {
+	query := `
+		WITH signed AS (
+			UPDATE signature_requests s
+			SET signed_name = $2, signed_ip = $3, signed_user_agent = $4, signed_at = NOW()
+			FROM timesheets t
+			WHERE s.id = $1 AND t.id = s.timesheet_id
+				AND s.signed_at IS NULL AND s.revoked_at IS NULL AND s.expires_at > NOW()
+				AND t.status = 'submitted'
+			RETURNING s.timesheet_id, s.supervisor_email, s.recipient_overridden
+		), approved AS (
+			UPDATE timesheets t
+			SET status = 'approved', reviewed_at = NOW()
+			FROM signed
+			WHERE t.id = signed.timesheet_id AND t.status = 'submitted'
+			RETURNING t.id, signed.supervisor_email, signed.recipient_overridden
+		)
+		INSERT INTO timesheet_events (timesheet_id, action, from_status, to_status, actor_name, comment)
+		SELECT id, $5, 'submitted', 'approved', $2, 'Signed via emailed link by ' || supervisor_email ||
+			CASE WHEN recipient_overridden THEN ' (an address chosen by the employee, not the supervisor on record)' ELSE '' END ||
+			' from ' || $3
+		FROM approved
+		RETURNING id
+	`
+
+	var eventID uuid.UUID
+	err := r.db.Pool.QueryRow(ctx, query, id, name, ip, userAgent, models.TimesheetActionApprove).Scan(&eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
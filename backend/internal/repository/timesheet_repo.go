@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"log_book/internal/database"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type TimesheetRepository struct {
+	db *database.DB
+}
+
+func NewTimesheetRepository(db *database.DB) *TimesheetRepository {
+	return &TimesheetRepository{db: db}
+}
+
+const timesheetColumns = `id, user_id, employer_id, period_type, period_start, period_end, status,
+		COALESCE(reviewer_email, ''), submitted_at, reviewed_at, created_at, updated_at`
+
+func scanTimesheet(row pgx.Row, t *models.Timesheet) error {
+	return row.Scan(
+		&t.ID, &t.UserID, &t.EmployerID, &t.PeriodType, &t.PeriodStart, &t.PeriodEnd, &t.Status,
+		&t.ReviewerEmail, &t.SubmittedAt, &t.ReviewedAt, &t.CreatedAt, &t.UpdatedAt,
+	)
+}
+
+// Create inserts a draft timesheet and its creation event.
+func (r *TimesheetRepository) Create(ctx context.Context, t *models.Timesheet, actorName string) error {
+	query := `
+		WITH created AS (
+			INSERT INTO timesheets (user_id, employer_id, period_type, period_start, period_end, status)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, status, created_at, updated_at
+		), event AS (
+			INSERT INTO timesheet_events (timesheet_id, action, to_status, actor_user_id, actor_name)
+			SELECT id, $7, status, $8, $9 FROM created
+		)
+		SELECT id, created_at, updated_at FROM created
+	`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		t.UserID, t.EmployerID, t.PeriodType, t.PeriodStart, t.PeriodEnd, t.Status,
+		models.TimesheetActionCreate, t.UserID, actorName,
+	).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+}
+
+func (r *TimesheetRepository) GetByID(ctx context.Context, id string) (*models.Timesheet, error) {
+	query := `SELECT ` + timesheetColumns + ` FROM timesheets WHERE id = $1`
+
+	var t models.Timesheet
+	err := scanTimesheet(r.db.Pool.QueryRow(ctx, query, id), &t)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("timesheet not found")
+	}
+
+	return &t, err
+}
+
+// ListByUser returns the user's timesheets, latest period first.
+func (r *TimesheetRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Timesheet, error) {
+	return r.query(ctx, `
+		SELECT `+timesheetColumns+`
+		FROM timesheets
+		WHERE user_id = $1
+		ORDER BY period_start DESC, period_type
+	`, userID)
+}
+
+// ListSubmittedForSupervisor returns the submitted timesheets of employers
+// whose supervisor email is email, oldest submission first.
+func (r *TimesheetRepository) ListSubmittedForSupervisor(ctx context.Context, email string) ([]models.Timesheet, error) {
+	return r.query(ctx, `
+		SELECT `+timesheetColumns+`
+		FROM timesheets
+		WHERE status = 'submitted' AND LOWER(reviewer_email) = LOWER($1)
+		ORDER BY submitted_at
+	`, email)
+}
+
+func (r *TimesheetRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.Timesheet, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	timesheets := []models.Timesheet{}
+	for rows.Next() {
+		var t models.Timesheet
+		if err := scanTimesheet(rows, &t); err != nil {
+			return nil, err
+		}
+		timesheets = append(timesheets, t)
+	}
+
+	return timesheets, rows.Err()
+}
+
+// Overlaps reports whether the employer already has a timesheet of the user
+// that shares a day with [start, end].
+func (r *TimesheetRepository) Overlaps(ctx context.Context, userID, employerID uuid.UUID, start, end time.Time) (bool, error) {
+	var exists bool
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM timesheets
+			WHERE user_id = $1 AND employer_id = $2
+				AND period_start <= $4 AND period_end >= $3
+		)
+	`, userID, employerID, start, end).Scan(&exists)
+	return exists, err
+}
+
+// Transition moves a timesheet to status to if its current status is one of
+// from, and records the event in the same statement. Returns false when the
+// timesheet was not in an allowed status.
+func (r *TimesheetRepository) Transition(ctx context.Context, id uuid.UUID, from []models.TimesheetStatus, to models.TimesheetStatus, event *models.TimesheetEvent) (bool, error) {
+	fromStatuses := make([]string, len(from))
+	for i, status := range from {
+		fromStatuses[i] = string(status)
+	}
+
+	query := `
+		WITH updated AS (
+			UPDATE timesheets t
+			SET status = $2,
+				submitted_at = CASE WHEN $2 = 'submitted' THEN NOW() ELSE t.submitted_at END,
+				reviewer_email = CASE WHEN $2 = 'submitted'
+					THEN (SELECT supervisor_email FROM employers WHERE id = t.employer_id)
+					ELSE t.reviewer_email END,
+				reviewed_at = CASE WHEN $2 IN ('approved', 'rejected') THEN NOW() ELSE t.reviewed_at END
+			FROM (SELECT id, status FROM timesheets WHERE id = $1 FOR UPDATE) old
+			WHERE t.id = old.id AND old.status = ANY($3::text[])
+			RETURNING t.id, old.status AS from_status
+		)
+		INSERT INTO timesheet_events (timesheet_id, action, from_status, to_status, actor_user_id, actor_name, comment)
+		SELECT id, $4, from_status, $2, $5, $6, NULLIF($7, '') FROM updated
+		RETURNING id, created_at
+	`
+
+	err := r.db.Pool.QueryRow(ctx, query,
+		id, string(to), fromStatuses, event.Action, event.ActorUserID, event.ActorName, event.Comment,
+	).Scan(&event.ID, &event.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	event.TimesheetID = id
+	event.ToStatus = string(to)
+	return true, nil
+}
+
+// ListEvents returns a timesheet's state changes, oldest first.
+func (r *TimesheetRepository) ListEvents(ctx context.Context, timesheetID uuid.UUID) ([]models.TimesheetEvent, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, timesheet_id, action, from_status, to_status, actor_user_id, actor_name,
+			COALESCE(comment, ''), created_at
+		FROM timesheet_events
+		WHERE timesheet_id = $1
+		ORDER BY created_at, id
+	`, timesheetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.TimesheetEvent{}
+	for rows.Next() {
+		var e models.TimesheetEvent
+		err := rows.Scan(
+			&e.ID, &e.TimesheetID, &e.Action, &e.FromStatus, &e.ToStatus, &e.ActorUserID, &e.ActorName,
+			&e.Comment, &e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// IsLocked reports whether any day from from to to falls in an approved
+// timesheet of the user's.
+func (r *TimesheetRepository) IsLocked(ctx context.Context, userID uuid.UUID, from, to time.Time) (bool, error) {
+	var locked bool
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM timesheets
+			WHERE user_id = $1 AND status = 'approved'
+				AND period_start <= $3 AND period_end >= $2
+		)
+	`, userID, from, to).Scan(&locked)
+	return locked, err
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"log_book/internal/database"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type TrainingPlanRepository struct {
+	db *database.DB
+}
+
+func NewTrainingPlanRepository(db *database.DB) *TrainingPlanRepository {
+	return &TrainingPlanRepository{db: db}
+}
+
+const goalColumns = `id, user_id, employer_id, title, COALESCE(description, ''), created_at, updated_at,
+		(SELECT COUNT(*) FROM document_goals dg WHERE dg.goal_id = training_goals.id)`
+
+func scanGoal(row pgx.Row, g *models.TrainingGoal) error {
+	return row.Scan(
+		&g.ID, &g.UserID, &g.EmployerID, &g.Title, &g.Description,
+		&g.CreatedAt, &g.UpdatedAt, &g.DocumentCount,
+	)
+}
+
+func (r *TrainingPlanRepository) CreateGoal(ctx context.Context, g *models.TrainingGoal) error {
+	query := `
+		INSERT INTO training_goals (user_id, employer_id, title, description)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.Pool.QueryRow(ctx, query,
+		g.UserID, g.EmployerID, g.Title, g.Description,
+	).Scan(&g.ID, &g.CreatedAt, &g.UpdatedAt)
+}
+
+func (r *TrainingPlanRepository) GetGoalByID(ctx context.Context, id string) (*models.TrainingGoal, error) {
+	query := `SELECT ` + goalColumns + ` FROM training_goals WHERE id = $1`
+
+	var g models.TrainingGoal
+	err := scanGoal(r.db.Pool.QueryRow(ctx, query, id), &g)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("training goal not found")
+	}
+
+	return &g, err
+}
+
+func (r *TrainingPlanRepository) UpdateGoal(ctx context.Context, g *models.TrainingGoal) error {
+	query := `
+		UPDATE training_goals
+		SET title = $1, description = NULLIF($2, '')
+		WHERE id = $3
+		RETURNING updated_at
+	`
+
+	return r.db.Pool.QueryRow(ctx, query, g.Title, g.Description, g.ID).Scan(&g.UpdatedAt)
+}
+
+func (r *TrainingPlanRepository) DeleteGoal(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM training_goals WHERE id = $1`, id)
+	return err
+}
+
+// ListGoalsByEmployer returns an employer's goals in the order they were added.
+func (r *TrainingPlanRepository) ListGoalsByEmployer(ctx context.Context, employerID uuid.UUID) ([]models.TrainingGoal, error) {
+	query := `
+		SELECT ` + goalColumns + `
+		FROM training_goals
+		WHERE employer_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, employerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goals := []models.TrainingGoal{}
+	for rows.Next() {
+		var g models.TrainingGoal
+		if err := scanGoal(rows, &g); err != nil {
+			return nil, err
+		}
+		goals = append(goals, g)
+	}
+
+	return goals, rows.Err()
+}
+
+// ListDocumentGoalIDs returns the goals a document is tagged with.
+func (r *TrainingPlanRepository) ListDocumentGoalIDs(ctx context.Context, documentID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT goal_id FROM document_goals WHERE document_id = $1 ORDER BY created_at, goal_id
+	`, documentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// SetDocumentGoals replaces the goals a document is tagged with. Both
+// statements run as one so readers never see a partial set.
+func (r *TrainingPlanRepository) SetDocumentGoals(ctx context.Context, documentID uuid.UUID, goalIDs []string) error {
+	query := `
+		WITH removed AS (
+			DELETE FROM document_goals
+			WHERE document_id = $1 AND NOT (goal_id = ANY($2::uuid[]))
+		)
+		INSERT INTO document_goals (document_id, goal_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query, documentID, goalIDs)
+	return err
+}
+
+// ListGoalDocuments returns the documents tagged to any of an employer's
+// goals with a log date in [from, to], oldest first, capped at limit rows.
+// A document tagged to several goals is returned once per goal.
+func (r *TrainingPlanRepository) ListGoalDocuments(ctx context.Context, employerID uuid.UUID, from, to time.Time, limit int) ([]models.GoalDocument, error) {
+	query := `
+		SELECT g.id, d.id, d.user_id, d.session_id, d.employer_id, d.log_date, d.title, d.content,
+			d.created_at, d.updated_at
+		FROM training_goals g
+		JOIN document_goals dg ON dg.goal_id = g.id
+		JOIN documents d ON d.id = dg.document_id
+		WHERE g.employer_id = $1 AND d.log_date >= $2 AND d.log_date <= $3
+		ORDER BY d.log_date, g.created_at
+		LIMIT $4
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, employerID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []models.GoalDocument
+	for rows.Next() {
+		var gd models.GoalDocument
+		doc := &gd.Document
+		err := rows.Scan(
+			&gd.GoalID, &doc.ID, &doc.UserID, &doc.SessionID, &doc.EmployerID, &doc.LogDate, &doc.Title,
+			&doc.Content, &doc.CreatedAt, &doc.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, gd)
+	}
+
+	return docs, rows.Err()
+}
+
+// ListSubmittedEvaluations returns the submission date of each evaluation
+// filed for an employer.
+func (r *TrainingPlanRepository) ListSubmittedEvaluations(ctx context.Context, employerID uuid.UUID) (map[models.EvaluationType]time.Time, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT type, submitted_on FROM training_evaluations WHERE employer_id = $1
+	`, employerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	submitted := make(map[models.EvaluationType]time.Time)
+	for rows.Next() {
+		var evalType string
+		var submittedOn time.Time
+		if err := rows.Scan(&evalType, &submittedOn); err != nil {
+			return nil, err
+		}
+		submitted[models.EvaluationType(evalType)] = submittedOn
+	}
+
+	return submitted, rows.Err()
+}
+
+func (r *TrainingPlanRepository) SubmitEvaluation(ctx context.Context, employerID uuid.UUID, evalType models.EvaluationType, submittedOn time.Time) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO training_evaluations (employer_id, type, submitted_on)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (employer_id, type) DO UPDATE SET submitted_on = EXCLUDED.submitted_on
+	`, employerID, string(evalType), submittedOn)
+	return err
+}
@@ -23,16 +23,16 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	query := `
 		INSERT INTO users (clerk_id, email, name)
 		VALUES ($1, $2, $3)
-		RETURNING id, created_at, updated_at
+		RETURNING id, role, timezone, created_at, updated_at
 	`
 
 	return r.db.Pool.QueryRow(ctx, query, user.ClerkID, user.Email, user.Name).
-		Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+		Scan(&user.ID, &user.Role, &user.Timezone, &user.CreatedAt, &user.UpdatedAt)
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	query := `
-		SELECT id, clerk_id, email, name, role, created_at, updated_at
+		SELECT id, clerk_id, email, name, role, timezone, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -40,7 +40,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 	var user models.User
 	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.ClerkID, &user.Email, &user.Name, &user.Role,
-		&user.CreatedAt, &user.UpdatedAt,
+		&user.Timezone, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -52,7 +52,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 
 func (r *UserRepository) GetByClerkID(ctx context.Context, clerkID string) (*models.User, error) {
 	query := `
-		SELECT id, clerk_id, email, name, role, created_at, updated_at
+		SELECT id, clerk_id, email, name, role, timezone, created_at, updated_at
 		FROM users
 		WHERE clerk_id = $1
 	`
@@ -60,7 +60,7 @@ func (r *UserRepository) GetByClerkID(ctx context.Context, clerkID string) (*mod
 	var user models.User
 	err := r.db.Pool.QueryRow(ctx, query, clerkID).Scan(
 		&user.ID, &user.ClerkID, &user.Email, &user.Name, &user.Role,
-		&user.CreatedAt, &user.UpdatedAt,
+		&user.Timezone, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -95,14 +95,24 @@ func (r *UserRepository) GetOrCreateByClerkID(ctx context.Context, clerkID strin
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	query := `
 		UPDATE users
-		SET email = $1, name = $2, updated_at = NOW()
-		WHERE id = $3
+		SET email = $1, name = $2, timezone = $3, updated_at = NOW()
+		WHERE id = $4
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, user.Email, user.Name, user.ID)
+	_, err := r.db.Pool.Exec(ctx, query, user.Email, user.Name, user.Timezone, user.ID)
 	return err
 }
 
+// TimezoneKnown reports whether Postgres accepts name in AT TIME ZONE, so
+// that queries in the user's time zone cannot fail.
+func (r *UserRepository) TimezoneKnown(ctx context.Context, name string) (bool, error) {
+	var known bool
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM pg_timezone_names WHERE name = $1)
+	`, name).Scan(&known)
+	return known, err
+}
+
 func (r *UserRepository) SyncUser(ctx context.Context, clerkID, email, name string) (*models.User, error) {
 	// Try to get existing user
 	user, err := r.GetByClerkID(ctx, clerkID)
@@ -5,20 +5,30 @@ import (
 	"log"
 	"time"
 
+	"log_book/internal/config"
+	"log_book/internal/jobs"
 	"log_book/internal/services"
 )
 
+// periodicJobAttempts is low because the next tick queues the job again.
+const periodicJobAttempts = 3
+
+// Scheduler queues the periodic jobs on every tick. Every backend
+// instance runs one; each job type has a unique key, so at most one of each
+// is queued or running at a time no matter how many instances tick.
 type Scheduler struct {
-	scheduleService *services.ScheduleService
-	interval        time.Duration
-	stopChan        chan struct{}
+	jobService *services.JobService
+	interval   time.Duration
+	idle       config.IdleConfig
+	stopChan   chan struct{}
 }
 
-func New(scheduleService *services.ScheduleService, interval time.Duration) *Scheduler {
+func New(jobService *services.JobService, interval time.Duration, idle config.IdleConfig) *Scheduler {
 	return &Scheduler{
-		scheduleService: scheduleService,
-		interval:        interval,
-		stopChan:        make(chan struct{}),
+		jobService: jobService,
+		interval:   interval,
+		idle:       idle,
+		stopChan:   make(chan struct{}),
 	}
 }
 
@@ -30,7 +40,7 @@ func (s *Scheduler) Start() {
 		for {
 			select {
 			case <-ticker.C:
-				s.processScheduledSessions()
+				s.enqueuePeriodicJobs()
 			case <-s.stopChan:
 				ticker.Stop()
 				log.Println("Scheduler stopped")
@@ -44,17 +54,79 @@ func (s *Scheduler) Stop() {
 	close(s.stopChan)
 }
 
-func (s *Scheduler) processScheduledSessions() {
+func (s *Scheduler) enqueuePeriodicJobs() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	count, err := s.scheduleService.ProcessScheduledSessions(ctx)
-	if err != nil {
-		log.Printf("Error processing scheduled sessions: %v", err)
-		return
+	jobTypes := []string{
+		services.JobTypeStopScheduledSessions,
+		services.JobTypeCloseOverdueSessions,
+		services.JobTypeStartPlannedSessions,
+		services.JobTypePruneJobs,
+		services.JobTypeSendWeeklyReminders,
+	}
+	if s.idle.Timeout > 0 {
+		jobTypes = append(jobTypes, services.JobTypeProcessIdleSessions)
 	}
 
-	if count > 0 {
-		log.Printf("Auto-stopped %d scheduled session(s)", count)
+	for _, jobType := range jobTypes {
+		_, err := s.jobService.Enqueue(ctx, jobType, nil, services.JobOptions{
+			UniqueKey:   jobType,
+			MaxAttempts: periodicJobAttempts,
+		})
+		if err != nil {
+			log.Printf("Error queueing %s: %v", jobType, err)
+		}
 	}
 }
+
+// RegisterJobs registers the handlers for the periodic jobs.
+func RegisterJobs(w *jobs.Worker, scheduleService *services.ScheduleService, plannedStartService *services.PlannedStartService, complianceService *services.ComplianceService, jobService *services.JobService, idle config.IdleConfig) {
+	jobs.Handle(w, services.JobTypeStopScheduledSessions, func(ctx context.Context, _ struct{}) error {
+		count, err := scheduleService.ProcessScheduledSessions(ctx)
+		if count > 0 {
+			log.Printf("Auto-stopped %d scheduled session(s)", count)
+		}
+		return err
+	})
+
+	jobs.Handle(w, services.JobTypeProcessIdleSessions, func(ctx context.Context, _ struct{}) error {
+		stop := idle.Action == "stop"
+		count, err := scheduleService.ProcessIdleSessions(ctx, idle.Timeout, stop)
+		if count > 0 && stop {
+			log.Printf("Auto-stopped %d idle session(s)", count)
+		} else if count > 0 {
+			log.Printf("Flagged %d idle session(s)", count)
+		}
+		return err
+	})
+
+	jobs.Handle(w, services.JobTypeCloseOverdueSessions, func(ctx context.Context, _ struct{}) error {
+		count, err := scheduleService.ProcessOverdueSessions(ctx)
+		if count > 0 {
+			log.Printf("Auto-closed %d session(s) past the maximum duration", count)
+		}
+		return err
+	})
+
+	jobs.Handle(w, services.JobTypeStartPlannedSessions, func(ctx context.Context, _ struct{}) error {
+		count, err := plannedStartService.ProcessPlannedStarts(ctx)
+		if count > 0 {
+			log.Printf("Auto-started %d planned session(s)", count)
+		}
+		return err
+	})
+
+	jobs.Handle(w, services.JobTypeSendWeeklyReminders, func(ctx context.Context, _ struct{}) error {
+		count, err := complianceService.SendWeeklyReminders(ctx)
+		if count > 0 {
+			log.Printf("Queued %d weekly hours reminder(s)", count)
+		}
+		return err
+	})
+
+	jobs.Handle(w, services.JobTypePruneJobs, func(ctx context.Context, _ struct{}) error {
+		_, err := jobService.PruneJobs(ctx)
+		return err
+	})
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"log_book/internal/models"
+	"log_book/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// calendarFeedDays is how far back the calendar feed reaches.
+const calendarFeedDays = 365
+
+// calendarFeedSuffix ends feed URLs so calendar clients recognise the file.
+// The route is /calendar/:token, so the suffix arrives as part of the token.
+const calendarFeedSuffix = ".ics"
+
+// CalendarService manages the per-user secret iCalendar feed. Calendar
+// clients cannot send a bearer token, so the feed URL itself carries a random
+// token; only its SHA-256 hash is stored.
+type CalendarService struct {
+	calendarRepo *repository.CalendarRepository
+	sessionRepo  *repository.SessionRepository
+	employerRepo *repository.EmployerRepository
+	userRepo     *repository.UserRepository
+	publicURL    string
+	appURL       string
+}
+
+func NewCalendarService(calendarRepo *repository.CalendarRepository, sessionRepo *repository.SessionRepository, employerRepo *repository.EmployerRepository, userRepo *repository.UserRepository, publicURL, appURL string) *CalendarService {
+	return &CalendarService{
+		calendarRepo: calendarRepo,
+		sessionRepo:  sessionRepo,
+		employerRepo: employerRepo,
+		userRepo:     userRepo,
+		publicURL:    publicURL,
+		appURL:       appURL,
+	}
+}
+
+// GetFeed returns the user's feed without its URL, which is only shown when
+// the feed is created.
+func (s *CalendarService) GetFeed(ctx context.Context, clerkID string) (*models.CalendarFeed, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := s.calendarRepo.GetByUser(ctx, user.ID)
+	if err != nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	return feed, nil
+}
+
+// RegenerateFeed issues a new feed URL. Any earlier URL stops working.
+func (s *CalendarService) RegenerateFeed(ctx context.Context, clerkID string) (*models.CalendarFeed, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	feed, err := s.calendarRepo.Upsert(ctx, user.ID, hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	feed.URL = s.publicURL + CalendarFeedPath(token)
+	return feed, nil
+}
+
+// CalendarFeedPath returns the path of the feed for token.
+func CalendarFeedPath(token string) string {
+	return "/calendar/" + token + calendarFeedSuffix
+}
+
+// CalendarFeedToken returns the token in the :token parameter of a feed path,
+// which may or may not end in .ics.
+func CalendarFeedToken(param string) string {
+	return strings.TrimSuffix(param, calendarFeedSuffix)
+}
+
+// RevokeFeed disables the user's feed URL.
+func (s *CalendarService) RevokeFeed(ctx context.Context, clerkID string) error {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.calendarRepo.Delete(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCalendarFeedNotFound
+	}
+
+	return nil
+}
+
+// RenderFeed returns the iCalendar document for a feed token, with one event
+// per completed or active session of the last year.
+func (s *CalendarService) RenderFeed(ctx context.Context, token string) ([]byte, error) {
+	userID, err := s.calendarRepo.Use(ctx, hashFeedToken(token))
+	if err != nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	sessions, err := s.sessionRepo.ListForCalendar(ctx, user.ID, now.AddDate(0, 0, -calendarFeedDays))
+	if err != nil {
+		return nil, err
+	}
+
+	employers, err := s.employerRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	employerNames := make(map[uuid.UUID]string, len(employers))
+	for _, e := range employers {
+		employerNames[e.ID] = e.Name
+	}
+
+	cal := &icalWriter{}
+	cal.line("BEGIN:VCALENDAR")
+	cal.line("VERSION:2.0")
+	cal.line("PRODID:-//LogBook//Work sessions//EN")
+	cal.line("CALSCALE:GREGORIAN")
+	cal.line("METHOD:PUBLISH")
+	cal.text("X-WR-CALNAME", "LogBook work sessions")
+	cal.line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	cal.line("X-PUBLISHED-TTL:PT1H")
+
+	for _, session := range sessions {
+		summary := "Work session"
+		if session.EmployerID != nil {
+			if name := employerNames[*session.EmployerID]; name != "" {
+				summary = "Work: " + name
+			}
+		}
+
+		end := now
+		if session.EndTime != nil {
+			end = *session.EndTime
+		} else {
+			summary += " (in progress)"
+			if session.ScheduledEnd != nil && session.ScheduledEnd.After(now) {
+				end = *session.ScheduledEnd
+			}
+		}
+
+		description := []string{fmt.Sprintf("Net hours: %.2f", float64(session.NetSeconds)/3600)}
+		if session.BreakSeconds > 0 {
+			description = append(description, fmt.Sprintf("Breaks: %.2f h", float64(session.BreakSeconds)/3600))
+		}
+		link := s.appURL + "/history"
+		if session.DocumentID != nil {
+			link = s.appURL + "/documents/" + session.DocumentID.String()
+			title := "Untitled log"
+			if session.DocumentTitle != nil && *session.DocumentTitle != "" {
+				title = *session.DocumentTitle
+			}
+			description = append(description, "Log: "+title)
+		}
+		description = append(description, link)
+
+		cal.line("BEGIN:VEVENT")
+		cal.line("UID:" + session.ID.String() + "@logbook")
+		cal.line("DTSTAMP:" + icalTime(now))
+		cal.line("DTSTART:" + icalTime(session.StartTime))
+		cal.line("DTEND:" + icalTime(end))
+		cal.text("SUMMARY", summary)
+		cal.text("DESCRIPTION", strings.Join(description, "\n"))
+		cal.line("URL:" + link)
+		cal.line("TRANSP:OPAQUE")
+		if session.EndTime == nil {
+			cal.line("STATUS:TENTATIVE")
+		} else {
+			cal.line("STATUS:CONFIRMED")
+		}
+		cal.line("END:VEVENT")
+	}
+
+	cal.line("END:VCALENDAR")
+	return []byte(cal.String()), nil
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func icalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icalWriter builds an iCalendar (RFC 5545) document with CRLF line endings
+// and long lines folded at 75 octets.
+type icalWriter struct {
+	strings.Builder
+}
+
+func (w *icalWriter) line(s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		// Do not split a UTF-8 sequence
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space
+		limit = 74
+	}
+	w.WriteString(s + "\r\n")
+}
+
+// text writes a property with a TEXT value, escaped.
+func (w *icalWriter) text(name, value string) {
+	value = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+	w.line(name + ":" + value)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarFeedPath(t *testing.T) {
+	path := CalendarFeedPath("abc_-123")
+	if path != "/calendar/abc_-123.ics" {
+		t.Errorf("CalendarFeedPath() = %q", path)
+	}
+	if got := CalendarFeedToken("abc_-123.ics"); got != "abc_-123" {
+		t.Errorf("CalendarFeedToken(with suffix) = %q", got)
+	}
+	if got := CalendarFeedToken("abc_-123"); got != "abc_-123" {
+		t.Errorf("CalendarFeedToken(without suffix) = %q", got)
+	}
+}
+
+func TestHashFeedToken(t *testing.T) {
+	a, b := hashFeedToken("token-a"), hashFeedToken("token-b")
+	if len(a) != 64 || a == b || a != hashFeedToken("token-a") {
+		t.Errorf("hashFeedToken() = %q, %q", a, b)
+	}
+}
+
+func TestICalTime(t *testing.T) {
+	loc := time.FixedZone("EST", -5*3600)
+	if got := icalTime(time.Date(2025, 3, 10, 9, 30, 0, 0, loc)); got != "20250310T143000Z" {
+		t.Errorf("icalTime() = %q", got)
+	}
+}
+
+func TestICalWriterText(t *testing.T) {
+	var w icalWriter
+	w.text("SUMMARY", "Work; a, b\\c\nnext")
+	if got := w.String(); got != `SUMMARY:Work\; a\, b\\c\nnext`+"\r\n" {
+		t.Errorf("text() = %q", got)
+	}
+}
+
+func TestICalWriterFolds(t *testing.T) {
+	var w icalWriter
+	value := strings.Repeat("é", 100)
+	w.line("DESCRIPTION:" + value)
+
+	lines := strings.Split(strings.TrimSuffix(w.String(), "\r\n"), "\r\n")
+	if len(lines) < 3 {
+		t.Fatalf("got %d lines, want the value folded", len(lines))
+	}
+	var unfolded strings.Builder
+	for i, l := range lines {
+		if len(l) > 75 {
+			t.Errorf("line %d is %d octets", i, len(l))
+		}
+		if i > 0 {
+			if !strings.HasPrefix(l, " ") {
+				t.Errorf("continuation line %d does not start with a space", i)
+			}
+			l = l[1:]
+		}
+		unfolded.WriteString(l)
+	}
+	if unfolded.String() != "DESCRIPTION:"+value {
+		t.Error("unfolding does not give back the line; a UTF-8 sequence was split")
+	}
+}
@@ -0,0 +1,450 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"log_book/internal/mail"
+	"log_book/internal/models"
+	"log_book/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// weeklyHoursThreshold is the average weekly hours OPT employment needs.
+	weeklyHoursThreshold   = 20.0
+	defaultComplianceWeeks = 12
+	maxComplianceWeeks     = 104
+	// weeklyReminderDay is the first day of the ISO week the reminder email
+	// goes out on, once the week has had time to fall behind.
+	weeklyReminderDay = time.Thursday
+)
+
+type ComplianceService struct {
+	optRepo      *repository.OPTProfileRepository
+	sessionRepo  *repository.SessionRepository
+	employerRepo *repository.EmployerRepository
+	userRepo     *repository.UserRepository
+	jobService   *JobService
+}
+
+func NewComplianceService(optRepo *repository.OPTProfileRepository, sessionRepo *repository.SessionRepository, employerRepo *repository.EmployerRepository, userRepo *repository.UserRepository, jobService *JobService) *ComplianceService {
+	return &ComplianceService{
+		optRepo:      optRepo,
+		sessionRepo:  sessionRepo,
+		employerRepo: employerRepo,
+		userRepo:     userRepo,
+		jobService:   jobService,
+	}
+}
+
+// SetOPTProfile stores the user's OPT start date and program type.
+func (s *ComplianceService) SetOPTProfile(ctx context.Context, clerkID string, input models.OPTProfileInput) (*models.OPTProfile, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+
+	profile := &models.OPTProfile{
+		UserID:    user.ID,
+		StartDate: startDate,
+		Program:   input.Program,
+	}
+	if input.WeeklyReminder != nil {
+		profile.WeeklyReminder = *input.WeeklyReminder
+	} else if existing, err := s.optRepo.GetByUser(ctx, user.ID); err == nil {
+		profile.WeeklyReminder = existing.WeeklyReminder
+	}
+	if err := s.optRepo.Upsert(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// GetOPTStatus counts the user's unemployment days from the OPT start date
+// through yesterday. A day is one of employment if it falls in an employer's
+// period or in an ISO week with at least weeklyHoursThreshold hours logged.
+func (s *ComplianceService) GetOPTStatus(ctx context.Context, clerkID string) (*models.OPTStatus, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.optRepo.GetByUser(ctx, user.ID)
+	if err != nil {
+		return nil, ErrOPTProfileNotSet
+	}
+
+	today := localDate(time.Now(), user.Location())
+
+	employers, err := s.employerRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	var periods []employmentPeriod
+	for _, e := range employers {
+		period := employmentPeriod{start: e.StartDate, end: today}
+		if e.EndDate != nil && e.EndDate.Before(today) {
+			period.end = *e.EndDate
+		}
+		periods = append(periods, period)
+	}
+
+	if !profile.StartDate.After(today) {
+		first := isoWeekStart(profile.StartDate)
+		days, err := s.sessionRepo.ListDays(ctx, user.ID, first, today, string(models.SessionStatusCompleted), nil)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, qualifyingWeeks(days)...)
+	}
+
+	return optStatus(profile, periods, today), nil
+}
+
+// employmentPeriod is a run of employed days, start and end inclusive, as
+// midnight UTC dates.
+type employmentPeriod struct {
+	start, end time.Time
+}
+
+// qualifyingWeeks returns the ISO weeks whose days add up to at least
+// weeklyHoursThreshold hours. A week still in progress qualifies as soon as
+// it reaches the threshold.
+func qualifyingWeeks(days []models.SessionDay) []employmentPeriod {
+	seconds := make(map[time.Time]int64)
+	var weeks []time.Time
+	for _, day := range days {
+		week := isoWeekStart(day.Date)
+		if _, ok := seconds[week]; !ok {
+			weeks = append(weeks, week)
+		}
+		seconds[week] += day.NetSeconds
+	}
+
+	var periods []employmentPeriod
+	for _, week := range weeks {
+		if float64(seconds[week])/3600 >= weeklyHoursThreshold {
+			periods = append(periods, employmentPeriod{start: week, end: week.AddDate(0, 0, 6)})
+		}
+	}
+	return periods
+}
+
+// optEmploymentGapDays is the shortest run of days without employment that
+// counts as unemployment once employment has started. Shorter runs (days
+// off, a few days between jobs) are treated as continued employment. Every
+// day between the OPT start date and the first employment counts.
+const optEmploymentGapDays = 7
+
+// optStatus adds up the unemployed runs from the OPT start date through the
+// day before today; today only counts once it is over. Employment after
+// today is ignored. All dates are midnight UTC.
+func optStatus(profile *models.OPTProfile, periods []employmentPeriod, today time.Time) *models.OPTStatus {
+	limit := models.OPTProgram(profile.Program).UnemploymentLimit()
+	status := &models.OPTStatus{
+		Program:   profile.Program,
+		StartDate: profile.StartDate,
+		AsOf:      today,
+		Limit:     limit,
+	}
+
+	// employed[i] is the day i days after the start date, through today
+	employed := make([]bool, max(daysBetween(profile.StartDate, today)+1, 0))
+	for _, p := range periods {
+		from := max(daysBetween(profile.StartDate, p.start), 0)
+		to := min(daysBetween(profile.StartDate, p.end), len(employed)-1)
+		for i := from; i <= to; i++ {
+			employed[i] = true
+		}
+	}
+
+	used := 0
+	var exhaustedOn *time.Time
+	count := func(from time.Time, days int) {
+		if days <= 0 {
+			return
+		}
+		if exhaustedOn == nil && used+days >= limit {
+			day := from.AddDate(0, 0, limit-used-1)
+			exhaustedOn = &day
+		}
+		used += days
+	}
+
+	// run is the first day of the current run without employment
+	run := profile.StartDate
+	seen := false
+	for i, ok := range employed {
+		if !ok {
+			continue
+		}
+		day := profile.StartDate.AddDate(0, 0, i)
+		if gap := daysBetween(run, day); !seen || gap >= optEmploymentGapDays {
+			count(run, gap)
+		}
+		seen = true
+		run = day.AddDate(0, 0, 1)
+		status.LastEmployedDate = &day
+	}
+
+	usedBefore := used
+	trailing := max(daysBetween(run, today), 0)
+	if !seen || trailing >= optEmploymentGapDays {
+		count(run, trailing)
+		status.CurrentlyUnemployed = trailing > 0
+	}
+
+	status.UnemploymentDays = used
+	status.RemainingDays = max(limit-used, 0)
+
+	if exhaustedOn != nil {
+		status.Exhausted = true
+		status.ProjectedExhaustionDate = *exhaustedOn
+		return status
+	}
+
+	// Without further employment the current run keeps growing until the
+	// limit, and counts in full once it is past the tolerance
+	need := limit - usedBefore
+	if seen && need < optEmploymentGapDays {
+		need = optEmploymentGapDays
+	}
+	status.ProjectedExhaustionDate = run.AddDate(0, 0, need-1)
+
+	return status
+}
+
+// GetWeeklyCompliance totals completed session hours per ISO week in the
+// user's time zone and marks each full week against the 20-hour threshold.
+// Time counts toward the day it was worked, so a session across midnight on
+// a Sunday is split between two weeks.
+func (s *ComplianceService) GetWeeklyCompliance(ctx context.Context, clerkID string, params models.WeeklyComplianceParams) (*models.WeeklyComplianceReport, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := user.Location()
+	now := time.Now().UTC()
+	today := localDate(now, loc)
+	currentWeek := isoWeekStart(today)
+
+	last := currentWeek
+	if params.To != "" {
+		to, err := time.Parse("2006-01-02", params.To)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		if to.Before(today) {
+			last = isoWeekStart(to)
+		}
+	}
+
+	first := last.AddDate(0, 0, -7*(defaultComplianceWeeks-1))
+	if params.From != "" {
+		from, err := time.Parse("2006-01-02", params.From)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		first = isoWeekStart(from)
+	}
+
+	count := daysBetween(first, last)/7 + 1
+	if count < 1 || count > maxComplianceWeeks {
+		return nil, ErrInvalidDateRange
+	}
+
+	days, err := s.sessionRepo.ListDays(ctx, user.ID, first, last.AddDate(0, 0, 6), string(models.SessionStatusCompleted), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	weeks := make([]models.WeeklyHours, count)
+	seconds := make([]int64, count)
+	for i := range weeks {
+		start := first.AddDate(0, 0, 7*i)
+		weeks[i].ISOYear, weeks[i].ISOWeek = start.ISOWeek()
+		weeks[i].WeekStart = start
+		weeks[i].WeekEnd = start.AddDate(0, 0, 6)
+		weeks[i].InProgress = start.Equal(currentWeek)
+	}
+	// Days come in order, so a session seen in the same week is already counted
+	lastWeek := make(map[uuid.UUID]int)
+	for _, day := range days {
+		i := daysBetween(first, day.Date) / 7
+		if i < 0 || i >= count {
+			continue
+		}
+		seconds[i] += day.NetSeconds
+		if w, ok := lastWeek[day.SessionID]; !ok || w != i {
+			lastWeek[day.SessionID] = i
+			weeks[i].Sessions++
+		}
+	}
+
+	report := &models.WeeklyComplianceReport{
+		ThresholdHours: weeklyHoursThreshold,
+		Weeks:          weeks,
+	}
+
+	fullWeeks := 0
+	totalHours := 0.0
+	for i := range weeks {
+		week := &weeks[i]
+		week.Hours = roundHours(float64(seconds[i]) / 3600)
+		week.Compliant = week.Hours >= weeklyHoursThreshold
+		week.GapHours = roundHours(math.Max(weeklyHoursThreshold-week.Hours, 0))
+
+		if week.InProgress {
+			weekStart := time.Date(week.WeekStart.Year(), week.WeekStart.Month(), week.WeekStart.Day(), 0, 0, 0, 0, loc)
+			report.CurrentWeek = s.weeklyTrend(ctx, user, week.Hours, weekStart, today, now)
+			continue
+		}
+		fullWeeks++
+		totalHours += week.Hours
+		if week.Compliant {
+			report.CompliantWeeks++
+		} else {
+			report.NonCompliantWeeks++
+		}
+	}
+	if fullWeeks > 0 {
+		report.AverageHours = roundHours(totalHours / float64(fullWeeks))
+	}
+
+	return report, nil
+}
+
+// weeklyTrend compares the current week's hours with an even pace over the
+// week. The reminder is only attached for users who opted in.
+func (s *ComplianceService) weeklyTrend(ctx context.Context, user *models.User, hours float64, weekStart time.Time, today time.Time, now time.Time) *models.WeeklyTrend {
+	trend := newWeeklyTrend(hours, weekStart, today, now)
+	if trend.OnTrack {
+		return trend
+	}
+	if profile, err := s.optRepo.GetByUser(ctx, user.ID); err == nil && profile.WeeklyReminder {
+		trend.Reminder = &models.WeeklyReminder{Message: weeklyReminderMessage(trend)}
+	}
+
+	return trend
+}
+
+// newWeeklyTrend measures hours logged in the week starting at weekStart, in
+// the user's time zone, against an even pace up to now.
+func newWeeklyTrend(hours float64, weekStart time.Time, today time.Time, now time.Time) *models.WeeklyTrend {
+	elapsed := now.Sub(weekStart).Hours() / (7 * 24)
+	elapsed = math.Min(math.Max(elapsed, 0), 1)
+
+	trend := &models.WeeklyTrend{
+		HoursLogged:   hours,
+		ExpectedHours: roundHours(weeklyHoursThreshold * elapsed),
+		HoursNeeded:   roundHours(math.Max(weeklyHoursThreshold-hours, 0)),
+		DaysLeft:      7 - daysBetween(isoWeekStart(today), today),
+	}
+	trend.OnTrack = hours >= trend.ExpectedHours
+	return trend
+}
+
+func weeklyReminderMessage(trend *models.WeeklyTrend) string {
+	return fmt.Sprintf(
+		"You have logged %.1f of %.0f hours this week. %.1f hours to go with %d day(s) left.",
+		trend.HoursLogged, weeklyHoursThreshold, trend.HoursNeeded, trend.DaysLeft,
+	)
+}
+
+// SendWeeklyReminders emails each opted-in user whose current week is behind
+// pace, at most once per ISO week and not before weeklyReminderDay in their
+// time zone. The week is marked before the email is queued, so a failure
+// skips a reminder rather than sending it twice. Returns the number queued.
+func (s *ComplianceService) SendWeeklyReminders(ctx context.Context) (int, error) {
+	recipients, err := s.optRepo.ListWeeklyReminders(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	sent := 0
+	for i := range recipients {
+		user := &recipients[i].User
+		loc := user.Location()
+		today := localDate(now, loc)
+		week := isoWeekStart(today)
+		if !weeklyReminderDue(today, recipients[i].RemindedWeek) {
+			continue
+		}
+
+		days, err := s.sessionRepo.ListDays(ctx, user.ID, week, week.AddDate(0, 0, 6), string(models.SessionStatusCompleted), nil)
+		if err != nil {
+			return sent, err
+		}
+		var seconds int64
+		for _, day := range days {
+			seconds += day.NetSeconds
+		}
+
+		weekStart := time.Date(week.Year(), week.Month(), week.Day(), 0, 0, 0, 0, loc)
+		trend := newWeeklyTrend(roundHours(float64(seconds)/3600), weekStart, today, now)
+		if trend.OnTrack {
+			continue
+		}
+
+		marked, err := s.optRepo.MarkReminded(ctx, user.ID, week)
+		if err != nil {
+			return sent, err
+		}
+		if !marked {
+			continue
+		}
+
+		msg := mail.Message{
+			To:      user.Email,
+			Subject: "Your OPT hours are behind this week",
+			Body: fmt.Sprintf(
+				"Hi %s,\n\n%s\n\nOPT employment needs an average of %.0f hours a week. "+
+					"You get this email because you turned on the weekly reminder; you can turn it off in your OPT profile.\n",
+				actorName(user), weeklyReminderMessage(trend), weeklyHoursThreshold,
+			),
+		}
+		if _, err := s.jobService.Enqueue(ctx, JobTypeSendMail, msg, JobOptions{}); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// weeklyReminderDue reports whether a reminder may go out on today, a local
+// date, given the week the user was last reminded for.
+func weeklyReminderDue(today time.Time, remindedWeek *time.Time) bool {
+	if (int(today.Weekday())+6)%7 < (int(weeklyReminderDay)+6)%7 {
+		return false
+	}
+	return remindedWeek == nil || remindedWeek.Before(isoWeekStart(today))
+}
+
+// isoWeekStart returns the Monday of the ISO week containing date.
+func isoWeekStart(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+// roundHours rounds to two decimal places.
+func roundHours(h float64) float64 {
+	return math.Round(h*100) / 100
+}
+
+// daysBetween returns the number of days from a to b; both must be midnight UTC.
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
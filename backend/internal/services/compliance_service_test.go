@@ -0,0 +1,205 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"log_book/internal/models"
+)
+
+func mustDate(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestOPTStatus(t *testing.T) {
+	profile := &models.OPTProfile{StartDate: mustDate("2025-01-01"), Program: string(models.OPTProgramStandard)}
+
+	tests := []struct {
+		name        string
+		employed    [][2]string
+		today       string
+		used        int
+		unemployed  bool
+		exhausted   bool
+		exhaustedOn string
+	}{
+		{
+			name:        "no employment yet",
+			today:       "2025-01-10",
+			used:        9,
+			unemployed:  true,
+			exhaustedOn: "2025-03-31",
+		},
+		{
+			name:        "today not counted until it is over",
+			today:       "2025-01-01",
+			used:        0,
+			exhaustedOn: "2025-03-31",
+		},
+		{
+			name:        "employed throughout",
+			employed:    [][2]string{{"2025-01-01", "2025-02-01"}},
+			today:       "2025-02-01",
+			used:        0,
+			exhaustedOn: "2025-05-02",
+		},
+		{
+			name:        "days off between jobs are tolerated",
+			employed:    [][2]string{{"2025-01-01", "2025-01-10"}, {"2025-01-14", "2025-01-20"}},
+			today:       "2025-01-20",
+			used:        0,
+			exhaustedOn: "2025-04-20",
+		},
+		{
+			name:        "long gap between jobs counts",
+			employed:    [][2]string{{"2025-01-01", "2025-01-10"}, {"2025-01-21", "2025-02-01"}},
+			today:       "2025-02-01",
+			used:        10,
+			exhaustedOn: "2025-04-22",
+		},
+		{
+			name:        "short run since employment ended is tolerated",
+			employed:    [][2]string{{"2025-01-01", "2025-01-10"}},
+			today:       "2025-01-15",
+			used:        0,
+			exhaustedOn: "2025-04-10",
+		},
+		{
+			name:        "run since employment ended counts once past the tolerance",
+			employed:    [][2]string{{"2025-01-01", "2025-01-10"}},
+			today:       "2025-01-20",
+			used:        9,
+			unemployed:  true,
+			exhaustedOn: "2025-04-10",
+		},
+		{
+			name:        "employment after today is ignored",
+			employed:    [][2]string{{"2025-01-20", "2025-03-01"}},
+			today:       "2025-01-05",
+			used:        4,
+			unemployed:  true,
+			exhaustedOn: "2025-03-31",
+		},
+		{
+			name:        "limit reached",
+			employed:    [][2]string{{"2025-04-15", "2025-04-20"}},
+			today:       "2025-04-20",
+			used:        104,
+			exhausted:   true,
+			exhaustedOn: "2025-03-31",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var periods []employmentPeriod
+			for _, p := range tt.employed {
+				periods = append(periods, employmentPeriod{start: mustDate(p[0]), end: mustDate(p[1])})
+			}
+			got := optStatus(profile, periods, mustDate(tt.today))
+			if got.UnemploymentDays != tt.used {
+				t.Errorf("UnemploymentDays = %d, want %d", got.UnemploymentDays, tt.used)
+			}
+			if got.RemainingDays != max(90-tt.used, 0) {
+				t.Errorf("RemainingDays = %d, want %d", got.RemainingDays, max(90-tt.used, 0))
+			}
+			if got.CurrentlyUnemployed != tt.unemployed {
+				t.Errorf("CurrentlyUnemployed = %v, want %v", got.CurrentlyUnemployed, tt.unemployed)
+			}
+			if got.Exhausted != tt.exhausted {
+				t.Errorf("Exhausted = %v, want %v", got.Exhausted, tt.exhausted)
+			}
+			if !got.ProjectedExhaustionDate.Equal(mustDate(tt.exhaustedOn)) {
+				t.Errorf("ProjectedExhaustionDate = %s, want %s", got.ProjectedExhaustionDate.Format("2006-01-02"), tt.exhaustedOn)
+			}
+		})
+	}
+}
+
+func TestQualifyingWeeks(t *testing.T) {
+	day := func(date string, hours int64) models.SessionDay {
+		return models.SessionDay{Date: mustDate(date), NetSeconds: hours * 3600}
+	}
+	days := []models.SessionDay{
+		// 21 hours, weekend included
+		day("2025-03-10", 8), day("2025-03-12", 8), day("2025-03-16", 5),
+		// 19 hours
+		day("2025-03-17", 10), day("2025-03-19", 9),
+		// 20 hours on a Monday
+		day("2025-03-24", 20),
+	}
+
+	got := qualifyingWeeks(days)
+	want := []employmentPeriod{
+		{start: mustDate("2025-03-10"), end: mustDate("2025-03-16")},
+		{start: mustDate("2025-03-24"), end: mustDate("2025-03-30")},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("qualifyingWeeks() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].start.Equal(want[i].start) || !got[i].end.Equal(want[i].end) {
+			t.Errorf("week %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestISOWeekStart(t *testing.T) {
+	tests := map[string]string{
+		"2025-03-10": "2025-03-10", // Monday
+		"2025-03-12": "2025-03-10",
+		"2025-03-16": "2025-03-10", // Sunday
+		"2025-03-17": "2025-03-17",
+	}
+	for in, want := range tests {
+		if got := isoWeekStart(mustDate(in)); !got.Equal(mustDate(want)) {
+			t.Errorf("isoWeekStart(%s) = %s, want %s", in, got.Format("2006-01-02"), want)
+		}
+	}
+}
+
+func TestNewWeeklyTrend(t *testing.T) {
+	weekStart := mustDate("2025-03-10")
+	// Thursday noon: half the week has passed
+	now := weekStart.Add(84 * time.Hour)
+	today := mustDate("2025-03-13")
+
+	behind := newWeeklyTrend(6, weekStart, today, now)
+	if behind.OnTrack || behind.ExpectedHours != 10 || behind.HoursNeeded != 14 || behind.DaysLeft != 4 {
+		t.Errorf("newWeeklyTrend(6) = %+v", behind)
+	}
+
+	ahead := newWeeklyTrend(25, weekStart, today, now)
+	if !ahead.OnTrack || ahead.HoursNeeded != 0 {
+		t.Errorf("newWeeklyTrend(25) = %+v", ahead)
+	}
+}
+
+func TestWeeklyReminderDue(t *testing.T) {
+	lastWeek := mustDate("2025-03-03")
+	thisWeek := mustDate("2025-03-10")
+
+	tests := []struct {
+		name     string
+		today    string
+		reminded *time.Time
+		want     bool
+	}{
+		{"before reminder day", "2025-03-12", nil, false},
+		{"on reminder day", "2025-03-13", nil, true},
+		{"sunday", "2025-03-16", nil, true},
+		{"reminded last week", "2025-03-14", &lastWeek, true},
+		{"reminded this week", "2025-03-14", &thisWeek, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weeklyReminderDue(mustDate(tt.today), tt.reminded); got != tt.want {
+				t.Errorf("weeklyReminderDue(%s) = %v, want %v", tt.today, got, tt.want)
+			}
+		})
+	}
+}
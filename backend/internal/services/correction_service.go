@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"log_book/internal/models"
+	"log_book/internal/repository"
+)
+
+// CorrectionService handles user-proposed corrections to completed sessions.
+// A correction whose session ended within the approval window is applied
+// straight away; older ones wait for an admin.
+type CorrectionService struct {
+	revisionRepo     *repository.RevisionRepository
+	sessionRepo      *repository.SessionRepository
+	breakRepo        *repository.BreakRepository
+	userRepo         *repository.UserRepository
+	policyService    *PolicyService
+	timesheetService *TimesheetService
+	window           time.Duration
+}
+
+func NewCorrectionService(revisionRepo *repository.RevisionRepository, sessionRepo *repository.SessionRepository, breakRepo *repository.BreakRepository, userRepo *repository.UserRepository, policyService *PolicyService, timesheetService *TimesheetService, window time.Duration) *CorrectionService {
+	return &CorrectionService{
+		revisionRepo:     revisionRepo,
+		sessionRepo:      sessionRepo,
+		breakRepo:        breakRepo,
+		userRepo:         userRepo,
+		policyService:    policyService,
+		timesheetService: timesheetService,
+		window:           window,
+	}
+}
+
+// RequestCorrection proposes new start and end times for one of the user's
+// completed sessions. The original times are kept in the revision.
+func (s *CorrectionService) RequestCorrection(ctx context.Context, clerkID string, sessionID string, input models.SessionCorrectionInput) (*models.SessionRevision, error) {
+	user, session, err := s.getCorrectable(ctx, clerkID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime, err := time.Parse(time.RFC3339, input.StartTime)
+	if err != nil {
+		return nil, ErrInvalidTimeRange
+	}
+	endTime, err := time.Parse(time.RFC3339, input.EndTime)
+	if err != nil || !endTime.After(startTime) {
+		return nil, ErrInvalidTimeRange
+	}
+
+	return s.request(ctx, user, session, startTime.UTC(), endTime.UTC(), input.Justification, "")
+}
+
+// CorrectEndTime proposes a new end time for a session the server closed on
+// the user's behalf (max-duration sweep or idle timeout). It goes through the
+// same approval window as any other correction.
+func (s *CorrectionService) CorrectEndTime(ctx context.Context, clerkID string, sessionID string, input models.CorrectEndTimeInput) (*models.SessionRevision, error) {
+	user, session, err := s.getCorrectable(ctx, clerkID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.EndReason == nil ||
+		(*session.EndReason != models.EndReasonAutoClosed && *session.EndReason != models.EndReasonIdle) {
+		return nil, ErrSessionNotAutoClosed
+	}
+
+	endTime, err := time.Parse(time.RFC3339, input.EndTime)
+	if err != nil || !endTime.After(session.StartTime) {
+		return nil, ErrInvalidTimeRange
+	}
+
+	justification := input.Justification
+	if justification == "" {
+		justification = autoClosedJustification(*session.EndReason)
+	}
+
+	return s.request(ctx, user, session, session.StartTime, endTime.UTC(), justification, input.DeviceID)
+}
+
+// getCorrectable loads the user and one of their completed sessions.
+func (s *CorrectionService) getCorrectable(ctx context.Context, clerkID string, sessionID string) (*models.User, *models.TimeSession, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, nil, ErrSessionNotFound
+	}
+	if session.UserID != user.ID {
+		return nil, nil, ErrUnauthorized
+	}
+	if session.Status != string(models.SessionStatusCompleted) || session.EndTime == nil {
+		return nil, nil, ErrSessionNotCorrectable
+	}
+
+	return user, session, nil
+}
+
+// request validates moving session to startTime and endTime (UTC) and either
+// applies it straight away or leaves it pending for an admin.
+func (s *CorrectionService) request(ctx context.Context, user *models.User, session *models.TimeSession, startTime, endTime time.Time, justification, deviceID string) (*models.SessionRevision, error) {
+	if startTime.Equal(session.StartTime) && endTime.Equal(*session.EndTime) {
+		return nil, ErrCorrectionUnchanged
+	}
+
+	pending, err := s.revisionRepo.GetPending(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, ErrCorrectionPending
+	}
+
+	if err := s.checkUnlocked(ctx, user, session.StartTime, *session.EndTime, startTime, endTime); err != nil {
+		return nil, err
+	}
+
+	// Breaks outside the new times are dropped when it is applied
+	breaks, err := s.breakRepo.ListBySession(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	worked := workedBetween(breaks, startTime, endTime)
+
+	// Moving the session to another day counts it against that day's limit
+	loc := user.Location()
+	if localDate(startTime, loc).Equal(localDate(session.StartTime, loc)) {
+		err = s.policyService.CheckInterval(ctx, user, startTime, endTime, worked)
+	} else {
+		err = s.policyService.CheckManual(ctx, user, startTime, endTime, worked)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkOverlap(ctx, s.sessionRepo, user.ID, &session.ID, startTime, &endTime); err != nil {
+		return nil, err
+	}
+
+	rev := &models.SessionRevision{
+		SessionID:     session.ID,
+		RequestedBy:   user.ID,
+		OldStartTime:  session.StartTime,
+		OldEndTime:    *session.EndTime,
+		NewStartTime:  startTime,
+		NewEndTime:    endTime,
+		Justification: justification,
+	}
+
+	if s.requiresApproval(rev) {
+		if err := s.revisionRepo.Create(ctx, rev); err != nil {
+			if isUniqueViolation(err) {
+				return nil, ErrCorrectionPending
+			}
+			return nil, err
+		}
+		return rev, nil
+	}
+
+	event, ok := newSessionEvent(session.Status, models.SessionEventEdited, userActor(user.ID, deviceID), revisionDetails(rev))
+	if !ok {
+		return nil, ErrSessionNotCorrectable
+	}
+
+	ok, err = s.revisionRepo.CreateApplied(ctx, rev, event)
+	if err != nil {
+		return nil, overlapError(ctx, s.sessionRepo, err, user.ID, &session.ID, startTime, &endTime)
+	}
+	if !ok {
+		return nil, ErrCorrectionStale
+	}
+
+	return rev, nil
+}
+
+// ListRevisions returns the correction history of one of the user's sessions.
+func (s *CorrectionService) ListRevisions(ctx context.Context, clerkID string, sessionID string) ([]models.SessionRevision, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	return s.revisionRepo.ListBySession(ctx, session.ID)
+}
+
+// ListPendingCorrections returns corrections waiting for admin approval.
+func (s *CorrectionService) ListPendingCorrections(ctx context.Context) ([]models.SessionRevision, error) {
+	return s.revisionRepo.ListPending(ctx)
+}
+
+// ApproveCorrection applies a pending correction to its session (admin).
+// Fails with ErrCorrectionStale when the session changed since the
+// correction was requested.
+func (s *CorrectionService) ApproveCorrection(ctx context.Context, clerkID string, revisionID string, input models.RevisionReviewInput) (*models.SessionRevision, error) {
+	reviewer, rev, err := s.getReviewable(ctx, clerkID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, rev.SessionID.String())
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	owner, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUnlocked(ctx, owner, rev.OldStartTime, rev.OldEndTime, rev.NewStartTime, rev.NewEndTime); err != nil {
+		return nil, err
+	}
+
+	var comment *string
+	if input.Comment != "" {
+		comment = &input.Comment
+	}
+
+	event, ok := newSessionEvent(session.Status, models.SessionEventEdited, adminActor(reviewer.ID), revisionDetails(rev))
+	if !ok {
+		return nil, ErrCorrectionStale
+	}
+
+	ok, err = s.revisionRepo.Approve(ctx, rev.ID, reviewer.ID, comment, event)
+	if err != nil {
+		return nil, overlapError(ctx, s.sessionRepo, err, owner.ID, &session.ID, rev.NewStartTime, &rev.NewEndTime)
+	}
+	if !ok {
+		return nil, s.notApplied(ctx, revisionID)
+	}
+
+	return s.revisionRepo.GetByID(ctx, revisionID)
+}
+
+// RejectCorrection turns down a pending correction with a comment (admin).
+// The session keeps its times.
+func (s *CorrectionService) RejectCorrection(ctx context.Context, clerkID string, revisionID string, input models.RevisionReviewInput) (*models.SessionRevision, error) {
+	if input.Comment == "" {
+		return nil, ErrCorrectionCommentRequired
+	}
+
+	reviewer, rev, err := s.getReviewable(ctx, clerkID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.revisionRepo.Reject(ctx, rev.ID, reviewer.ID, input.Comment)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrCorrectionReviewed
+	}
+
+	return s.revisionRepo.GetByID(ctx, revisionID)
+}
+
+// getReviewable loads the reviewer and a pending revision someone else
+// requested.
+func (s *CorrectionService) getReviewable(ctx context.Context, clerkID string, revisionID string) (*models.User, *models.SessionRevision, error) {
+	reviewer, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rev, err := s.revisionRepo.GetByID(ctx, revisionID)
+	if err != nil {
+		return nil, nil, ErrCorrectionNotFound
+	}
+	if rev.RequestedBy == reviewer.ID {
+		return nil, nil, ErrUnauthorized
+	}
+	if rev.Status != string(models.RevisionPending) {
+		return nil, nil, ErrCorrectionReviewed
+	}
+
+	return reviewer, rev, nil
+}
+
+// notApplied explains why an approval changed nothing: either someone else
+// reviewed the revision first or its session no longer has the old times.
+func (s *CorrectionService) notApplied(ctx context.Context, revisionID string) error {
+	rev, err := s.revisionRepo.GetByID(ctx, revisionID)
+	if err != nil {
+		return ErrCorrectionNotFound
+	}
+	if rev.Status != string(models.RevisionPending) {
+		return ErrCorrectionReviewed
+	}
+	return ErrCorrectionStale
+}
+
+// checkUnlocked refuses corrections that move a session out of or into a
+// period covered by an approved timesheet, on any day of the old or new
+// times.
+func (s *CorrectionService) checkUnlocked(ctx context.Context, owner *models.User, oldStart, oldEnd, newStart, newEnd time.Time) error {
+	if err := s.timesheetService.CheckSessionUnlocked(ctx, owner, oldStart, oldEnd); err != nil {
+		return err
+	}
+	return s.timesheetService.CheckSessionUnlocked(ctx, owner, newStart, newEnd)
+}
+
+// workedBetween returns the time from start to end less the parts of breaks
+// that fall inside it.
+func workedBetween(breaks []models.SessionBreak, start, end time.Time) time.Duration {
+	worked := end.Sub(start)
+	for _, b := range breaks {
+		from, to := b.StartTime, end
+		if b.EndTime != nil && b.EndTime.Before(to) {
+			to = *b.EndTime
+		}
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(from) {
+			worked -= to.Sub(from)
+		}
+	}
+	return worked
+}
+
+// autoClosedJustification explains an end-time correction the user gave no
+// reason for.
+func autoClosedJustification(endReason string) string {
+	if endReason == models.EndReasonIdle {
+		return "End time corrected after the session was stopped for inactivity"
+	}
+	return "End time corrected after the session was closed at its maximum duration"
+}
+
+// revisionDetails describes a correction on the session's edited event.
+func revisionDetails(rev *models.SessionRevision) map[string]interface{} {
+	return map[string]interface{}{
+		"old_start_time": rev.OldStartTime,
+		"old_end_time":   rev.OldEndTime,
+		"start_time":     rev.NewStartTime,
+		"end_time":       rev.NewEndTime,
+	}
+}
+
+// requiresApproval reports whether the revision reaches further back than
+// the approval window. Age is measured from the earlier of the two end times.
+func (s *CorrectionService) requiresApproval(rev *models.SessionRevision) bool {
+	end := rev.OldEndTime
+	if rev.NewEndTime.Before(end) {
+		end = rev.NewEndTime
+	}
+	return s.window == 0 || time.Since(end) > s.window
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"log_book/internal/models"
+)
+
+func TestRequiresApproval(t *testing.T) {
+	now := time.Now().UTC()
+	window := 48 * time.Hour
+
+	tests := []struct {
+		name   string
+		window time.Duration
+		oldEnd time.Time
+		newEnd time.Time
+		want   bool
+	}{
+		{"recent session", window, now.Add(-time.Hour), now.Add(-2 * time.Hour), false},
+		{"old session", window, now.Add(-72 * time.Hour), now.Add(-71 * time.Hour), true},
+		{"moved back past the window", window, now.Add(-time.Hour), now.Add(-72 * time.Hour), true},
+		{"moved forward from past the window", window, now.Add(-72 * time.Hour), now.Add(-time.Hour), true},
+		{"no window", 0, now.Add(-time.Minute), now.Add(-time.Minute), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &CorrectionService{window: tt.window}
+			rev := &models.SessionRevision{OldEndTime: tt.oldEnd, NewEndTime: tt.newEnd}
+			if got := s.requiresApproval(rev); got != tt.want {
+				t.Errorf("requiresApproval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevisionEvent(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	rev := &models.SessionRevision{
+		OldStartTime: start,
+		OldEndTime:   start.Add(4 * time.Hour),
+		NewStartTime: start.Add(time.Hour),
+		NewEndTime:   start.Add(5 * time.Hour),
+	}
+
+	details := revisionDetails(rev)
+	for key, want := range map[string]time.Time{
+		"old_start_time": rev.OldStartTime,
+		"old_end_time":   rev.OldEndTime,
+		"start_time":     rev.NewStartTime,
+		"end_time":       rev.NewEndTime,
+	} {
+		if got, _ := details[key].(time.Time); !got.Equal(want) {
+			t.Errorf("details[%q] = %v, want %v", key, details[key], want)
+		}
+	}
+
+	// Only completed sessions can be corrected
+	if _, ok := newSessionEvent(string(models.SessionStatusCompleted), models.SessionEventEdited, systemActor, details); !ok {
+		t.Error("edited should be allowed on a completed session")
+	}
+	for _, status := range []models.SessionStatus{models.SessionStatusActive, models.SessionStatusCancelled} {
+		if _, ok := newSessionEvent(string(status), models.SessionEventEdited, systemActor, details); ok {
+			t.Errorf("edited should not be allowed on a %s session", status)
+		}
+	}
+}
+
+func TestWorkedBetween(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, 3, 10, hour, 0, 0, 0, time.UTC) }
+	breaks := []models.SessionBreak{
+		{StartTime: at(10), EndTime: ptrTime(at(11))},
+		{StartTime: at(13), EndTime: ptrTime(at(16))},
+	}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       time.Duration
+	}{
+		{"both breaks inside", at(9), at(17), 4 * time.Hour},
+		{"second break clipped by the end", at(9), at(14), 3 * time.Hour},
+		{"first break clipped by the start", at(10), at(12), time.Hour},
+		{"breaks outside", at(17), at(20), 3 * time.Hour},
+		{"no breaks", at(9), at(10), time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := workedBetween(breaks, tt.start, tt.end); got != tt.want {
+				t.Errorf("workedBetween() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
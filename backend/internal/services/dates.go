@@ -0,0 +1,17 @@
+package services
+
+import "time"
+
+// localDate returns the calendar date of t as seen in loc, normalised to
+// midnight UTC so it can be stored in a DATE column or compared with dates
+// parsed by time.Parse("2006-01-02", ...).
+func localDate(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// monthStart returns the first day of the current month in loc.
+func monthStart(loc *time.Location) time.Time {
+	today := localDate(time.Now(), loc)
+	return today.AddDate(0, 0, 1-today.Day())
+}
@@ -11,14 +11,18 @@ import (
 )
 
 type DocumentService struct {
-	documentRepo *repository.DocumentRepository
-	userRepo     *repository.UserRepository
+	documentRepo     *repository.DocumentRepository
+	userRepo         *repository.UserRepository
+	employerRepo     *repository.EmployerRepository
+	timesheetService *TimesheetService
 }
 
-func NewDocumentService(documentRepo *repository.DocumentRepository, userRepo *repository.UserRepository) *DocumentService {
+func NewDocumentService(documentRepo *repository.DocumentRepository, userRepo *repository.UserRepository, employerRepo *repository.EmployerRepository, timesheetService *TimesheetService) *DocumentService {
 	return &DocumentService{
-		documentRepo: documentRepo,
-		userRepo:     userRepo,
+		documentRepo:     documentRepo,
+		userRepo:         userRepo,
+		employerRepo:     employerRepo,
+		timesheetService: timesheetService,
 	}
 }
 
@@ -28,9 +32,12 @@ func (s *DocumentService) CreateDocument(ctx context.Context, clerkID string, in
 		return nil, err
 	}
 
-	logDate, err := time.Parse("2006-01-02", input.LogDate)
-	if err != nil {
-		return nil, err
+	logDate := localDate(time.Now(), user.Location())
+	if input.LogDate != "" {
+		logDate, err = time.Parse("2006-01-02", input.LogDate)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	existing, _ := s.documentRepo.GetByUserAndDate(ctx, user.ID, logDate)
@@ -54,12 +61,22 @@ func (s *DocumentService) CreateDocument(ctx context.Context, clerkID string, in
 		}
 	}
 
+	employerID, err := resolveEmployerID(ctx, s.employerRepo, user.ID, input.EmployerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.timesheetService.CheckUnlocked(ctx, user.ID, logDate); err != nil {
+		return nil, err
+	}
+
 	doc := &models.Document{
-		UserID:    user.ID,
-		SessionID: sessionID,
-		LogDate:   logDate,
-		Title:     input.Title,
-		Content:   input.Content,
+		UserID:     user.ID,
+		SessionID:  sessionID,
+		EmployerID: employerID,
+		LogDate:    logDate,
+		Title:      input.Title,
+		Content:    input.Content,
 	}
 
 	err = s.documentRepo.Create(ctx, doc)
@@ -103,11 +120,22 @@ func (s *DocumentService) UpdateDocument(ctx context.Context, clerkID string, do
 		return nil, ErrUnauthorized
 	}
 
+	if err := s.timesheetService.CheckUnlocked(ctx, user.ID, doc.LogDate); err != nil {
+		return nil, err
+	}
+
 	if input.Title != "" {
 		doc.Title = input.Title
 	}
 	doc.Content = input.Content
 
+	if input.EmployerID != nil {
+		doc.EmployerID, err = resolveEmployerID(ctx, s.employerRepo, user.ID, input.EmployerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = s.documentRepo.Update(ctx, doc)
 	if err != nil {
 		return nil, err
@@ -140,5 +168,9 @@ func (s *DocumentService) DeleteDocument(ctx context.Context, clerkID string, do
 		return ErrUnauthorized
 	}
 
+	if err := s.timesheetService.CheckUnlocked(ctx, user.ID, doc.LogDate); err != nil {
+		return err
+	}
+
 	return s.documentRepo.Delete(ctx, doc.ID)
 }
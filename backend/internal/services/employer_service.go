@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"log_book/internal/models"
+	"log_book/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var einPattern = regexp.MustCompile(`^[0-9]{2}-?[0-9]{7}$`)
+
+type EmployerService struct {
+	employerRepo *repository.EmployerRepository
+	userRepo     *repository.UserRepository
+}
+
+func NewEmployerService(employerRepo *repository.EmployerRepository, userRepo *repository.UserRepository) *EmployerService {
+	return &EmployerService{
+		employerRepo: employerRepo,
+		userRepo:     userRepo,
+	}
+}
+
+func (s *EmployerService) ListEmployers(ctx context.Context, clerkID string) ([]models.Employer, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.employerRepo.ListByUser(ctx, user.ID)
+}
+
+func (s *EmployerService) GetEmployer(ctx context.Context, clerkID string, employerID string) (*models.Employer, error) {
+	return s.getOwnedEmployer(ctx, clerkID, employerID)
+}
+
+func (s *EmployerService) CreateEmployer(ctx context.Context, clerkID string, input models.EmployerInput) (*models.Employer, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	employer := &models.Employer{UserID: user.ID}
+	if err := applyEmployerInput(employer, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.employerRepo.Create(ctx, employer); err != nil {
+		return nil, err
+	}
+
+	return employer, nil
+}
+
+func (s *EmployerService) UpdateEmployer(ctx context.Context, clerkID string, employerID string, input models.EmployerInput) (*models.Employer, error) {
+	employer, err := s.getOwnedEmployer(ctx, clerkID, employerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyEmployerInput(employer, input); err != nil {
+		return nil, err
+	}
+
+	// Submitted and approved timesheets keep the supervisor they were sent
+	// to, and the owner may not swap in another reviewer while they exist
+	ok, err := s.employerRepo.Update(ctx, employer)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrSupervisorLocked
+	}
+
+	return employer, nil
+}
+
+func (s *EmployerService) DeleteEmployer(ctx context.Context, clerkID string, employerID string) error {
+	employer, err := s.getOwnedEmployer(ctx, clerkID, employerID)
+	if err != nil {
+		return err
+	}
+
+	// Submitted and approved timesheets are records the supervisor has
+	// signed or is about to, so they block the delete
+	if err := s.employerRepo.Delete(ctx, employer.ID); err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrEmployerInUse
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *EmployerService) getOwnedEmployer(ctx context.Context, clerkID string, employerID string) (*models.Employer, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	employer, err := s.employerRepo.GetByID(ctx, employerID)
+	if err != nil {
+		return nil, ErrEmployerNotFound
+	}
+
+	if employer.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	return employer, nil
+}
+
+func applyEmployerInput(employer *models.Employer, input models.EmployerInput) error {
+	startDate, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return ErrInvalidDate
+	}
+
+	var endDate *time.Time
+	if input.EndDate != "" {
+		d, err := time.Parse("2006-01-02", input.EndDate)
+		if err != nil {
+			return ErrInvalidDate
+		}
+		if d.Before(startDate) {
+			return ErrInvalidDateRange
+		}
+		endDate = &d
+	}
+
+	ein := strings.TrimSpace(input.EIN)
+	if ein != "" {
+		if !einPattern.MatchString(ein) {
+			return ErrInvalidEIN
+		}
+		// Store as XX-XXXXXXX
+		digits := strings.ReplaceAll(ein, "-", "")
+		ein = digits[:2] + "-" + digits[2:]
+	}
+
+	employer.Name = strings.TrimSpace(input.Name)
+	employer.Address = input.Address
+	employer.EIN = ein
+	employer.SupervisorName = input.SupervisorName
+	employer.SupervisorEmail = input.SupervisorEmail
+	employer.StartDate = startDate
+	employer.EndDate = endDate
+	return nil
+}
+
+// resolveEmployerID parses an optional employer_id and checks that the
+// employer belongs to the user. A nil or empty value means no employer.
+func resolveEmployerID(ctx context.Context, employerRepo *repository.EmployerRepository, userID uuid.UUID, raw *string) (*uuid.UUID, error) {
+	if raw == nil || *raw == "" {
+		return nil, nil
+	}
+
+	employer, err := employerRepo.GetByID(ctx, *raw)
+	if err != nil || employer.UserID != userID {
+		return nil, ErrEmployerNotFound
+	}
+	return &employer.ID, nil
+}
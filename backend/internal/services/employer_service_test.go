@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"log_book/internal/models"
+)
+
+func TestApplyEmployerInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   models.EmployerInput
+		wantEIN string
+		wantErr error
+	}{
+		{
+			name:    "ein without dash",
+			input:   models.EmployerInput{Name: " Acme ", StartDate: "2025-01-06", EIN: "123456789"},
+			wantEIN: "12-3456789",
+		},
+		{
+			name:    "ein with dash",
+			input:   models.EmployerInput{Name: "Acme", StartDate: "2025-01-06", EndDate: "2025-12-31", EIN: "12-3456789"},
+			wantEIN: "12-3456789",
+		},
+		{
+			name:    "ein too short",
+			input:   models.EmployerInput{Name: "Acme", StartDate: "2025-01-06", EIN: "12-345"},
+			wantErr: ErrInvalidEIN,
+		},
+		{
+			name:    "bad start date",
+			input:   models.EmployerInput{Name: "Acme", StartDate: "01/06/2025"},
+			wantErr: ErrInvalidDate,
+		},
+		{
+			name:    "end before start",
+			input:   models.EmployerInput{Name: "Acme", StartDate: "2025-01-06", EndDate: "2025-01-05"},
+			wantErr: ErrInvalidDateRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var employer models.Employer
+			err := applyEmployerInput(&employer, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("applyEmployerInput() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if employer.Name != "Acme" {
+				t.Errorf("Name = %q, want trimmed %q", employer.Name, "Acme")
+			}
+			if employer.EIN != tt.wantEIN {
+				t.Errorf("EIN = %q, want %q", employer.EIN, tt.wantEIN)
+			}
+			if (employer.EndDate != nil) != (tt.input.EndDate != "") {
+				t.Errorf("EndDate = %v for input %q", employer.EndDate, tt.input.EndDate)
+			}
+		})
+	}
+}
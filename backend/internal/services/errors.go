@@ -1,19 +1,100 @@
 package services
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
 
 // Common service errors
 var (
 	// Session errors
-	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionNotFound      = errors.New("session not found")
 	ErrSessionAlreadyActive = errors.New("session already active")
-	ErrNoActiveSession     = errors.New("no active session")
-	ErrInvalidScheduleTime = errors.New("schedule time must be in the future")
-	ErrInvalidTimeRange    = errors.New("end time must be after start time")
-	ErrSessionTooLong      = errors.New("session duration cannot exceed 24 hours")
-	ErrFutureEndTime       = errors.New("end time cannot be in the future")
+	ErrNoActiveSession      = errors.New("no active session")
+	ErrInvalidScheduleTime  = errors.New("schedule time must be in the future")
+	ErrInvalidTimeRange     = errors.New("end time must be after start time")
+	ErrSessionTooLong       = errors.New("session duration cannot exceed 24 hours")
+	ErrFutureEndTime        = errors.New("end time cannot be in the future")
 	ErrSessionExistsForDate = errors.New("a session already exists for this date")
 	ErrSessionTooShort      = errors.New("session must be at least 4 hours")
+	ErrSessionPaused        = errors.New("session is already paused")
+	ErrSessionNotPaused     = errors.New("session is not paused")
+	ErrSessionNotCompleted  = errors.New("only completed sessions can be voided")
+	ErrSessionNotAutoClosed = errors.New("only automatically closed sessions can be corrected")
+	ErrSessionOverlap       = errors.New("session overlaps another session")
+
+	// Correction errors
+	ErrCorrectionNotFound        = errors.New("correction not found")
+	ErrSessionNotCorrectable     = errors.New("only completed sessions can be corrected")
+	ErrCorrectionUnchanged       = errors.New("the correction does not change the session's times")
+	ErrCorrectionPending         = errors.New("a correction of this session is already waiting for approval")
+	ErrCorrectionReviewed        = errors.New("this correction has already been reviewed")
+	ErrCorrectionStale           = errors.New("the session changed after this correction was requested")
+	ErrCorrectionCommentRequired = errors.New("a comment is required when rejecting a correction")
+
+	// Schedule errors
+	ErrScheduleRuleNotFound = errors.New("schedule rule not found")
+	ErrInvalidStopTime      = errors.New("stop time must be HH:MM")
+	ErrPlannedStartNotFound = errors.New("planned start not found")
+	ErrInvalidPlannedStart  = errors.New("planned start needs either a future start_at or days and start_time")
+
+	// Policy errors
+	ErrPolicyNotFound     = errors.New("policy not found")
+	ErrPolicyExists       = errors.New("a policy already exists for this scope")
+	ErrInvalidPolicyScope = errors.New("role policies need a role and user policies need a user_id")
+	ErrPolicyDurations    = errors.New("min_duration_minutes cannot exceed max_duration_minutes")
+
+	// Employer errors
+	ErrEmployerNotFound = errors.New("employer not found")
+	ErrInvalidEIN       = errors.New("EIN must be 9 digits (XX-XXXXXXX)")
+	ErrEmployerInUse    = errors.New("employer has submitted or approved timesheets")
+	ErrSupervisorLocked = errors.New("the supervisor email cannot change while a timesheet is submitted or approved")
+
+	// Training plan errors
+	ErrTrainingGoalNotFound    = errors.New("training goal not found")
+	ErrInvalidEvaluationType   = errors.New("evaluation type must be twelve_month or final")
+	ErrEvaluationNotApplicable = errors.New("evaluation does not apply to this employment period")
+
+	// Timesheet errors
+	ErrTimesheetNotFound          = errors.New("timesheet not found")
+	ErrTimesheetExists            = errors.New("a timesheet already covers part of this period")
+	ErrInvalidTimesheetPeriod     = errors.New("weekly timesheets start on a Monday and monthly ones on the 1st")
+	ErrInvalidTimesheetTransition = errors.New("timesheet is not in a state that allows this")
+	ErrReviewCommentRequired      = errors.New("a comment is required when rejecting a timesheet")
+	ErrPeriodLocked               = errors.New("this period is covered by an approved timesheet")
+
+	// Signature errors
+	ErrSigningNotConfigured  = errors.New("emailed sign-off is not configured")
+	ErrNoSupervisorEmail     = errors.New("no supervisor email given and the employer has none")
+	ErrSignerIsOwner         = errors.New("a timesheet cannot be sent to its owner for signing")
+	ErrSignatureLinkInvalid  = errors.New("this signing link is invalid or has expired")
+	ErrSignatureConfirmation = errors.New("confirm the hours and type your name to sign")
+	ErrSignerNameTooLong     = errors.New("your name must be at most 200 characters")
+
+	// Import errors
+	ErrInvalidImportFile    = errors.New("the file could not be read as CSV")
+	ErrImportEmpty          = errors.New("the file has no data rows")
+	ErrImportTooManyRows    = errors.New("the file has more than 5000 data rows")
+	ErrImportColumnNotFound = errors.New("column not found in the header row")
+	ErrInvalidTrackerExport = errors.New("the file is not a Toggl or Clockify export")
+
+	// Report errors
+	ErrReportUnsupportedText = errors.New("a name or address in this timesheet has characters the PDF cannot show")
+
+	// Calendar errors
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+	// Compliance errors
+	ErrOPTProfileNotSet = errors.New("OPT start date and program are not set")
+	ErrInvalidDate      = errors.New("date must be YYYY-MM-DD")
+	ErrInvalidDateRange = errors.New("invalid date range")
+
+	// Job errors
+	ErrJobNotFound      = errors.New("job not found")
+	ErrJobNotDead       = errors.New("only dead jobs can be retried")
+	ErrJobAlreadyQueued = errors.New("a job with the same key is already queued")
 
 	// Document errors
 	ErrDocumentNotFound = errors.New("document not found")
@@ -23,8 +104,40 @@ var (
 	ErrMediaNotFound = errors.New("media not found")
 
 	// User errors
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrInvalidTimezone = errors.New("invalid time zone")
 
 	// Auth errors
 	ErrUnauthorized = errors.New("unauthorized")
 )
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// isForeignKeyViolation reports whether err is a Postgres foreign key
+// violation, such as deleting a row that is still referenced.
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+// isExclusionViolation reports whether err is a Postgres exclusion constraint
+// violation, such as two sessions of a user overlapping.
+func isExclusionViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23P01"
+}
+
+// SessionOverlapError is returned when a session would overlap others of the
+// same user. SessionIDs lists the sessions in the way. It unwraps to
+// ErrSessionOverlap.
+type SessionOverlapError struct {
+	SessionIDs []uuid.UUID
+}
+
+func (e *SessionOverlapError) Error() string { return ErrSessionOverlap.Error() }
+
+func (e *SessionOverlapError) Unwrap() error { return ErrSessionOverlap }
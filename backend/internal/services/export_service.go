@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"io"
+
+	"log_book/internal/export"
+	"log_book/internal/models"
+	"log_book/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type ExportService struct {
+	sessionRepo  *repository.SessionRepository
+	documentRepo *repository.DocumentRepository
+	employerRepo *repository.EmployerRepository
+	userRepo     *repository.UserRepository
+}
+
+func NewExportService(sessionRepo *repository.SessionRepository, documentRepo *repository.DocumentRepository, employerRepo *repository.EmployerRepository, userRepo *repository.UserRepository) *ExportService {
+	return &ExportService{
+		sessionRepo:  sessionRepo,
+		documentRepo: documentRepo,
+		employerRepo: employerRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// ExportSessions writes the user's sessions matching params to w, one row at
+// a time. Times are in the user's time zone.
+func (s *ExportService) ExportSessions(ctx context.Context, clerkID string, params models.SessionListParams, format export.Format, w io.Writer) error {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return err
+	}
+
+	employers, err := s.employerNames(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	out, err := export.New(format, w, "Sessions",
+		"id", "date", "start", "end", "status", "break_hours", "net_hours",
+		"employer", "employer_id", "device_id", "end_reason", "cancel_reason",
+	)
+	if err != nil {
+		return err
+	}
+
+	loc := user.Location()
+	err = s.sessionRepo.EachByUser(ctx, user.ID, params, loc, func(session *models.TimeSession) error {
+		var end any
+		if session.EndTime != nil {
+			end = session.EndTime.In(loc).Format(exportTimeLayout)
+		}
+		return out.WriteRow(
+			session.ID.String(),
+			localDate(session.StartTime, loc).Format("2006-01-02"),
+			session.StartTime.In(loc).Format(exportTimeLayout),
+			end,
+			session.Status,
+			roundHours(float64(session.BreakSeconds)/3600),
+			roundHours(float64(session.NetSeconds)/3600),
+			employerName(employers, session.EmployerID),
+			optionalID(session.EmployerID),
+			session.DeviceID,
+			optionalString(session.EndReason),
+			optionalString(session.CancelReason),
+		)
+	})
+	if err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+// ExportDocuments writes the user's documents matching params to w, one row
+// at a time, with their content flattened to plain text.
+func (s *ExportService) ExportDocuments(ctx context.Context, clerkID string, params models.DocumentListParams, format export.Format, w io.Writer) error {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return err
+	}
+
+	employers, err := s.employerNames(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	out, err := export.New(format, w, "Documents",
+		"id", "log_date", "title", "employer", "employer_id", "session_id",
+		"created_at", "updated_at", "content",
+	)
+	if err != nil {
+		return err
+	}
+
+	loc := user.Location()
+	err = s.documentRepo.EachByUser(ctx, user.ID, params, func(doc *models.Document) error {
+		return out.WriteRow(
+			doc.ID.String(),
+			doc.LogDate.Format("2006-01-02"),
+			doc.Title,
+			employerName(employers, doc.EmployerID),
+			optionalID(doc.EmployerID),
+			optionalID(doc.SessionID),
+			doc.CreatedAt.In(loc).Format(exportTimeLayout),
+			doc.UpdatedAt.In(loc).Format(exportTimeLayout),
+			extractText(doc.Content),
+		)
+	})
+	if err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+// exportTimeLayout is the local date-time format spreadsheets parse as a
+// timestamp.
+const exportTimeLayout = "2006-01-02 15:04:05"
+
+func (s *ExportService) employerNames(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]string, error) {
+	employers, err := s.employerRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uuid.UUID]string, len(employers))
+	for _, e := range employers {
+		names[e.ID] = e.Name
+	}
+	return names, nil
+}
+
+func employerName(names map[uuid.UUID]string, id *uuid.UUID) any {
+	if id == nil {
+		return nil
+	}
+	return names[*id]
+}
+
+func optionalID(id *uuid.UUID) any {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
+
+func optionalString(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
@@ -0,0 +1,992 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"log_book/internal/models"
+	"log_book/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// maxImportRows bounds the data rows in a single import.
+const maxImportRows = 5000
+
+// Row rules reported by imports besides the policy rules.
+const (
+	ImportRuleInvalidTime  = "invalid_time"
+	ImportRuleTimeRange    = "invalid_time_range"
+	ImportRulePeriodLocked = "period_locked"
+	ImportRuleRunning      = "running"
+	ImportRuleOverlap      = "overlap"
+)
+
+// ImportService bulk-creates completed sessions from files, applying the
+// same checks as TimeService.CreateManualSession to every session.
+type ImportService struct {
+	sessionRepo      *repository.SessionRepository
+	documentRepo     *repository.DocumentRepository
+	employerRepo     *repository.EmployerRepository
+	userRepo         *repository.UserRepository
+	policyService    *PolicyService
+	timesheetService *TimesheetService
+}
+
+func NewImportService(sessionRepo *repository.SessionRepository, documentRepo *repository.DocumentRepository, employerRepo *repository.EmployerRepository, userRepo *repository.UserRepository, policyService *PolicyService, timesheetService *TimesheetService) *ImportService {
+	return &ImportService{
+		sessionRepo:      sessionRepo,
+		documentRepo:     documentRepo,
+		employerRepo:     employerRepo,
+		userRepo:         userRepo,
+		policyService:    policyService,
+		timesheetService: timesheetService,
+	}
+}
+
+// importCandidate is a session read from an import file, or the reason the
+// row could not be read.
+type importCandidate struct {
+	row        int
+	start, end time.Time
+	err        *models.ImportRowError
+}
+
+// ImportSessionsCSV validates every row of a CSV file and, in commit mode,
+// creates the valid ones in a single transaction.
+func (s *ImportService) ImportSessionsCSV(ctx context.Context, clerkID string, input models.SessionImportInput, file io.Reader) (*models.SessionImportResult, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	employerID, err := resolveEmployerID(ctx, s.employerRepo, user.ID, &input.EmployerID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := parseSessionCSV(file, input, user.Location())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.importSessions(ctx, user, employerID, models.ImportMode(input.Mode), candidates)
+}
+
+// importSessions checks candidates in file order. Each valid row counts
+// toward the per-day limit of the rows after it, which may not overlap it.
+func (s *ImportService) importSessions(ctx context.Context, user *models.User, employerID *uuid.UUID, mode models.ImportMode, candidates []importCandidate) (*models.SessionImportResult, error) {
+	policy, err := s.policyService.Effective(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := user.Location()
+	stored, err := s.storedCounts(ctx, user, candidates)
+	if err != nil {
+		return nil, err
+	}
+	overlaps, err := s.loadOverlaps(ctx, user, candidates)
+	if err != nil {
+		return nil, err
+	}
+	imported := make(map[time.Time]int)
+
+	result := &models.SessionImportResult{
+		Mode:      string(mode),
+		TotalRows: len(candidates),
+		Rows:      make([]models.SessionImportRow, len(candidates)),
+	}
+
+	var sessions []*models.TimeSession
+	var sessionRows []int
+	reason := models.EndReasonManual
+	for i, c := range candidates {
+		row := &result.Rows[i]
+		row.Row = c.row
+		if c.err != nil {
+			row.Error = c.err
+			result.InvalidRows++
+			continue
+		}
+
+		start, end := c.start.UTC(), c.end.UTC()
+		day := localDate(start, loc)
+		row.Date = day.Format("2006-01-02")
+		row.StartTime = &start
+		row.EndTime = &end
+		row.Hours = roundHours(end.Sub(start).Hours())
+
+		row.Error, err = s.checkRow(ctx, user, policy, start, end, end.Sub(start), stored[day], imported[day])
+		if err != nil {
+			return nil, err
+		}
+		if row.Error == nil {
+			row.Error = overlaps.check(start, end)
+		}
+		if row.Error != nil {
+			result.InvalidRows++
+			continue
+		}
+
+		imported[day]++
+		overlaps.add(start, end, fmt.Sprintf("row %d", c.row))
+		result.ValidRows++
+		sessions = append(sessions, &models.TimeSession{
+			UserID:     user.ID,
+			StartTime:  start,
+			EndTime:    &end,
+			Status:     string(models.SessionStatusCompleted),
+			DeviceID:   "import",
+			EndReason:  &reason,
+			EmployerID: employerID,
+		})
+		sessionRows = append(sessionRows, i)
+	}
+
+	if mode != models.ImportModeCommit || len(sessions) == 0 {
+		return result, nil
+	}
+
+	event, _ := newSessionEvent("", models.SessionEventRecorded, userActor(user.ID, "import"),
+		map[string]interface{}{"source": "csv"})
+	if err := s.sessionRepo.CreateManualBatch(ctx, sessions, event); err != nil {
+		return nil, s.batchOverlapError(ctx, err, user.ID, sessions)
+	}
+	for i, session := range sessions {
+		id := session.ID
+		result.Rows[sessionRows[i]].SessionID = &id
+	}
+	result.Created = len(sessions)
+
+	return result, nil
+}
+
+// checkRow applies CreateManualSession's checks to one row, with the
+// duration rules applied to worked rather than the whole span. Returns the
+// row's error, or a non-nil error only when a check itself failed.
+func (s *ImportService) checkRow(ctx context.Context, user *models.User, policy models.EffectivePolicy, start, end time.Time, worked time.Duration, stored, imported int) (*models.ImportRowError, error) {
+	if !end.After(start) {
+		return &models.ImportRowError{Rule: ImportRuleTimeRange, Message: ErrInvalidTimeRange.Error()}, nil
+	}
+
+	err := s.policyService.CheckImported(policy, start, end, worked, stored+imported)
+	var violation *PolicyViolationError
+	if errors.As(err, &violation) {
+		message := violation.Message
+		if violation.Rule == PolicyRuleSessionsPerDay && stored < policy.MaxSessionsPerDay {
+			message = "Earlier rows in this file already use up this date. " + message
+		}
+		return &models.ImportRowError{Rule: violation.Rule, Message: message}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.timesheetService.CheckSessionUnlocked(ctx, user, start, end)
+	if err == ErrPeriodLocked {
+		return &models.ImportRowError{Rule: ImportRulePeriodLocked, Message: ErrPeriodLocked.Error()}, nil
+	}
+
+	return nil, err
+}
+
+// importOverlaps holds the intervals an imported session must not overlap:
+// the user's stored sessions around the import and the sessions accepted
+// from the import so far.
+type importOverlaps struct {
+	stored   []models.TimeSession
+	accepted []acceptedInterval
+}
+
+type acceptedInterval struct {
+	start, end time.Time
+	label      string
+}
+
+// loadOverlaps loads the user's sessions that overlap the span of the
+// candidates.
+func (s *ImportService) loadOverlaps(ctx context.Context, user *models.User, candidates []importCandidate) (*importOverlaps, error) {
+	var first, last time.Time
+	for _, c := range candidates {
+		if c.err != nil {
+			continue
+		}
+		if first.IsZero() || c.start.Before(first) {
+			first = c.start
+		}
+		if c.end.After(last) {
+			last = c.end
+		}
+	}
+	if first.IsZero() || !last.After(first) {
+		return &importOverlaps{}, nil
+	}
+
+	stored, err := s.sessionRepo.ListOverlapping(ctx, user.ID, first, &last, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &importOverlaps{stored: stored}, nil
+}
+
+// check returns the row error for a session over [start, end) that
+// overlaps a stored or already accepted one, or nil.
+func (o *importOverlaps) check(start, end time.Time) *models.ImportRowError {
+	var ids []uuid.UUID
+	for _, session := range o.stored {
+		// An active session has no end yet and overlaps everything after its start
+		if session.StartTime.Before(end) && (session.EndTime == nil || start.Before(*session.EndTime)) {
+			ids = append(ids, session.ID)
+		}
+	}
+	if len(ids) > 0 {
+		return &models.ImportRowError{
+			Rule:       ImportRuleOverlap,
+			Message:    "Overlaps an existing session",
+			SessionIDs: ids,
+		}
+	}
+
+	for _, a := range o.accepted {
+		if start.Before(a.end) && a.start.Before(end) {
+			return &models.ImportRowError{
+				Rule:    ImportRuleOverlap,
+				Message: "Overlaps " + a.label + " of this import",
+			}
+		}
+	}
+	return nil
+}
+
+func (o *importOverlaps) add(start, end time.Time, label string) {
+	o.accepted = append(o.accepted, acceptedInterval{start: start, end: end, label: label})
+}
+
+// batchOverlapError maps an exclusion violation from storing an import,
+// which the checks above only miss when another write raced the import, to
+// the SessionOverlapError of the first session that no longer fits.
+func (s *ImportService) batchOverlapError(ctx context.Context, err error, userID uuid.UUID, sessions []*models.TimeSession) error {
+	if !isExclusionViolation(err) {
+		return err
+	}
+	for _, session := range sessions {
+		if err := checkOverlap(ctx, s.sessionRepo, userID, nil, session.StartTime, session.EndTime); err != nil {
+			return err
+		}
+	}
+	return &SessionOverlapError{SessionIDs: []uuid.UUID{}}
+}
+
+// storedCounts counts the user's existing sessions on each day the
+// candidates fall on.
+func (s *ImportService) storedCounts(ctx context.Context, user *models.User, candidates []importCandidate) (map[time.Time]int, error) {
+	loc := user.Location()
+
+	var first, last time.Time
+	for _, c := range candidates {
+		if c.err != nil {
+			continue
+		}
+		day := localDate(c.start, loc)
+		if first.IsZero() || day.Before(first) {
+			first = day
+		}
+		if day.After(last) {
+			last = day
+		}
+	}
+	if first.IsZero() {
+		return map[time.Time]int{}, nil
+	}
+
+	return s.sessionRepo.CountSessionsByDate(ctx, user.ID, first, last, loc)
+}
+
+// parseSessionCSV reads the mapped columns of every non-blank data row.
+func parseSessionCSV(file io.Reader, input models.SessionImportInput, loc *time.Location) ([]importCandidate, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+	switch input.Delimiter {
+	case "semicolon":
+		reader.Comma = ';'
+	case "tab":
+		reader.Comma = '\t'
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrImportEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[key]; !ok {
+			columns[key] = i
+		}
+	}
+	column := func(name string) (int, error) {
+		i, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return 0, fmt.Errorf("%w: %q", ErrImportColumnNotFound, name)
+		}
+		return i, nil
+	}
+
+	startCol, err := column(input.StartColumn)
+	if err != nil {
+		return nil, err
+	}
+	endCol, err := column(input.EndColumn)
+	if err != nil {
+		return nil, err
+	}
+	dateCol := -1
+	if input.DateColumn != "" {
+		if dateCol, err = column(input.DateColumn); err != nil {
+			return nil, err
+		}
+	}
+
+	layouts := newImportLayouts(input.DayFirst)
+	var candidates []importCandidate
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		if blankRecord(record) {
+			continue
+		}
+		if len(candidates) == maxImportRows {
+			return nil, ErrImportTooManyRows
+		}
+
+		cell := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		c := importCandidate{row: line}
+		c.start, c.end, c.err = layouts.parseInterval(cell(dateCol), cell(startCol), cell(endCol), dateCol >= 0, loc)
+		candidates = append(candidates, c)
+	}
+
+	if len(candidates) == 0 {
+		return nil, ErrImportEmpty
+	}
+
+	return candidates, nil
+}
+
+func blankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// importLayouts holds the date and time formats accepted in import files.
+type importLayouts struct {
+	dates     []string
+	times     []string
+	dateTimes []string
+}
+
+func newImportLayouts(dayFirst bool) importLayouts {
+	dates := []string{"2006-01-02", "01/02/2006", "1/2/2006", "01/02/06", "1/2/06"}
+	if dayFirst {
+		dates = []string{"2006-01-02", "02/01/2006", "2/1/2006", "02/01/06", "2/1/06", "02.01.2006", "2.1.2006"}
+	}
+	times := []string{"15:04", "15:04:05", "3:04 PM", "3:04PM", "3:04:05 PM", "3PM", "3 PM"}
+
+	l := importLayouts{
+		dates:     dates,
+		times:     times,
+		dateTimes: []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04"},
+	}
+	for _, d := range dates {
+		for _, t := range times {
+			l.dateTimes = append(l.dateTimes, d+" "+t)
+		}
+	}
+	return l
+}
+
+// parseInterval reads a row's start and end. Without a date column both must
+// be full date-times. With one, each may be a time of day on that date, and
+// an end time at or before the start time falls on the next day.
+func (l importLayouts) parseInterval(date, start, end string, hasDate bool, loc *time.Location) (time.Time, time.Time, *models.ImportRowError) {
+	invalid := func(format string, args ...any) (time.Time, time.Time, *models.ImportRowError) {
+		return time.Time{}, time.Time{}, &models.ImportRowError{Rule: ImportRuleInvalidTime, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if !hasDate {
+		startTime, ok := parseAny(l.dateTimes, start, loc)
+		if !ok {
+			return invalid("Start %q is not a recognised date and time", start)
+		}
+		endTime, ok := parseAny(l.dateTimes, end, loc)
+		if !ok {
+			return invalid("End %q is not a recognised date and time", end)
+		}
+		return startTime, endTime, nil
+	}
+
+	day, ok := parseAny(l.dates, date, loc)
+	if !ok {
+		return invalid("Date %q is not a recognised date", date)
+	}
+
+	onDay := func(value string) (time.Time, bool, bool) {
+		if t, ok := parseAny(l.dateTimes, value, loc); ok {
+			return t, true, true
+		}
+		clock, ok := parseAny(l.times, value, time.UTC)
+		if !ok {
+			return time.Time{}, false, false
+		}
+		return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, loc), false, true
+	}
+
+	startTime, _, ok := onDay(start)
+	if !ok {
+		return invalid("Start %q is not a recognised time", start)
+	}
+	endTime, full, ok := onDay(end)
+	if !ok {
+		return invalid("End %q is not a recognised time", end)
+	}
+	if !full && !endTime.After(startTime) {
+		endTime = time.Date(day.Year(), day.Month(), day.Day()+1, endTime.Hour(), endTime.Minute(), endTime.Second(), 0, loc)
+	}
+
+	return startTime, endTime, nil
+}
+
+// parseAny parses value with the first layout that fits. Layouts without a
+// zone are read in loc.
+func parseAny(layouts []string, value string, loc *time.Location) (time.Time, bool) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// trackerEntry is an entry read from a tracker export. start and end are
+// only set when entry.Error is nil.
+type trackerEntry struct {
+	entry      models.TrackerEntry
+	start, end time.Time
+}
+
+// ImportTracker imports a Toggl or Clockify export. All entries starting on
+// the same day in the user's time zone, overlapping or not, become one
+// session, matching the one-session-per-day rule: the session runs from the
+// first start to the last end and the gaps between entries become breaks.
+// Days that already have a session are skipped. Entry descriptions become
+// the day's document, one paragraph each.
+func (s *ImportService) ImportTracker(ctx context.Context, clerkID string, input models.TrackerImportInput, file io.Reader) (*models.TrackerImportResult, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	employerID, err := resolveEmployerID(ctx, s.employerRepo, user.ID, &input.EmployerID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := user.Location()
+	source := models.ImportSource(input.Source)
+	entries, err := parseTrackerExport(source, file, newImportLayouts(input.DayFirst), loc)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > maxImportRows {
+		return nil, ErrImportTooManyRows
+	}
+
+	result := &models.TrackerImportResult{
+		Mode:       input.Mode,
+		Source:     input.Source,
+		Entries:    len(entries),
+		Days:       []models.TrackerImportDay{},
+		Unreadable: []models.TrackerEntry{},
+	}
+
+	// Group readable entries by the day they start on
+	byDay := make(map[time.Time][]trackerEntry)
+	var days []time.Time
+	candidates := make([]importCandidate, 0, len(entries))
+	for _, e := range entries {
+		if e.entry.Error != nil {
+			e.entry.Action = models.ImportActionSkipped
+			result.Unreadable = append(result.Unreadable, e.entry)
+			result.Skipped++
+			continue
+		}
+		day := localDate(e.start, loc)
+		if _, ok := byDay[day]; !ok {
+			days = append(days, day)
+		}
+		byDay[day] = append(byDay[day], e)
+		candidates = append(candidates, importCandidate{start: e.start, end: e.end})
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	policy, err := s.policyService.Effective(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.storedCounts(ctx, user, candidates)
+	if err != nil {
+		return nil, err
+	}
+	overlaps, err := s.loadOverlaps(ctx, user, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	var imports []models.ImportedSession
+	var importDays []int
+	reason := models.EndReasonManual
+	for _, day := range days {
+		dayEntries := byDay[day]
+		sort.SliceStable(dayEntries, func(i, j int) bool { return dayEntries[i].start.Before(dayEntries[j].start) })
+
+		spans := mergeSpans(dayEntries)
+		start, end := spans[0][0], spans[len(spans)-1][1]
+		tracked := time.Duration(0)
+		for _, span := range spans {
+			tracked += span[1].Sub(span[0])
+		}
+
+		result.Days = append(result.Days, models.TrackerImportDay{
+			Date:         day.Format("2006-01-02"),
+			StartTime:    start,
+			EndTime:      end,
+			TrackedHours: roundHours(tracked.Hours()),
+			BreakHours:   roundHours((end.Sub(start) - tracked).Hours()),
+		})
+		report := &result.Days[len(result.Days)-1]
+
+		if stored[day] > 0 {
+			report.Error = &models.ImportRowError{
+				Rule:    PolicyRuleSessionsPerDay,
+				Message: "A session already exists for this date",
+			}
+		} else if report.Error, err = s.checkRow(ctx, user, policy, start, end, tracked, 0, 0); err != nil {
+			return nil, err
+		}
+		if report.Error == nil {
+			report.Error = overlaps.check(start, end)
+		}
+		if report.Error == nil {
+			overlaps.add(start, end, "the session for "+report.Date)
+		}
+
+		report.Action = models.ImportActionCreated
+		if report.Error != nil {
+			report.Action = models.ImportActionSkipped
+		}
+		for i, e := range dayEntries {
+			switch {
+			case report.Error != nil:
+				e.entry.Action = models.ImportActionSkipped
+				result.Skipped++
+			case i == 0:
+				e.entry.Action = models.ImportActionCreated
+				result.Created++
+			default:
+				e.entry.Action = models.ImportActionMerged
+				result.Merged++
+			}
+			report.Entries = append(report.Entries, e.entry)
+		}
+		if report.Error != nil {
+			continue
+		}
+
+		var doc *models.Document
+		if content := trackerContent(dayEntries); content != nil {
+			report.Document = "created"
+			if existing, err := s.documentRepo.GetByUserAndDate(ctx, user.ID, day); err == nil && existing != nil {
+				report.Document = "exists"
+			} else {
+				doc = &models.Document{
+					UserID:     user.ID,
+					EmployerID: employerID,
+					LogDate:    day,
+					Title:      "Imported from " + source.Title(),
+					Content:    content,
+				}
+			}
+		}
+
+		endTime := end
+		var breaks []models.SessionBreak
+		for i := 1; i < len(spans); i++ {
+			breakEnd := spans[i][0]
+			breaks = append(breaks, models.SessionBreak{StartTime: spans[i-1][1], EndTime: &breakEnd})
+		}
+		imports = append(imports, models.ImportedSession{
+			Session: &models.TimeSession{
+				UserID:     user.ID,
+				StartTime:  start,
+				EndTime:    &endTime,
+				Status:     string(models.SessionStatusCompleted),
+				DeviceID:   string(source),
+				EndReason:  &reason,
+				EmployerID: employerID,
+			},
+			Breaks:   breaks,
+			Document: doc,
+		})
+		importDays = append(importDays, len(result.Days)-1)
+	}
+
+	if models.ImportMode(input.Mode) != models.ImportModeCommit || len(imports) == 0 {
+		return result, nil
+	}
+
+	event, _ := newSessionEvent("", models.SessionEventRecorded, userActor(user.ID, string(source)),
+		map[string]interface{}{"source": string(source)})
+	if err := s.sessionRepo.CreateImported(ctx, imports, event); err != nil {
+		sessions := make([]*models.TimeSession, len(imports))
+		for i, imp := range imports {
+			sessions[i] = imp.Session
+		}
+		return nil, s.batchOverlapError(ctx, err, user.ID, sessions)
+	}
+	for i, imp := range imports {
+		report := &result.Days[importDays[i]]
+		id := imp.Session.ID
+		report.SessionID = &id
+		if imp.Document != nil {
+			docID := imp.Document.ID
+			report.DocumentID = &docID
+		} else if report.Document == "created" {
+			report.Document = "exists"
+		}
+	}
+
+	return result, nil
+}
+
+// mergeSpans returns the union of the entries' intervals, in order. entries
+// must be sorted by start.
+func mergeSpans(entries []trackerEntry) [][2]time.Time {
+	var spans [][2]time.Time
+	for _, e := range entries {
+		if n := len(spans); n > 0 && !e.start.After(spans[n-1][1]) {
+			if e.end.After(spans[n-1][1]) {
+				spans[n-1][1] = e.end
+			}
+			continue
+		}
+		spans = append(spans, [2]time.Time{e.start, e.end})
+	}
+	return spans
+}
+
+// trackerContent builds a Tiptap document with a paragraph per distinct
+// entry description, prefixed with its project. Returns nil when no entry
+// has either.
+func trackerContent(entries []trackerEntry) json.RawMessage {
+	type textNode struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	type paragraph struct {
+		Type    string     `json:"type"`
+		Content []textNode `json:"content"`
+	}
+
+	seen := make(map[string]bool)
+	var paragraphs []paragraph
+	for _, e := range entries {
+		text := strings.TrimSpace(e.entry.Description)
+		if project := strings.TrimSpace(e.entry.Project); project != "" {
+			if text == "" {
+				text = project
+			} else {
+				text = project + ": " + text
+			}
+		}
+		if text == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		paragraphs = append(paragraphs, paragraph{
+			Type:    "paragraph",
+			Content: []textNode{{Type: "text", Text: text}},
+		})
+	}
+	if len(paragraphs) == 0 {
+		return nil
+	}
+
+	content, _ := json.Marshal(struct {
+		Type    string      `json:"type"`
+		Content []paragraph `json:"content"`
+	}{Type: "doc", Content: paragraphs})
+	return content
+}
+
+// parseTrackerExport reads a Toggl or Clockify export. JSON files are
+// Toggl time entries (Track API v9, or a detailed report with a "data"
+// array) or Clockify time entries; CSV files are either tracker's detailed
+// report.
+func parseTrackerExport(source models.ImportSource, file io.Reader, layouts importLayouts, loc *time.Location) ([]trackerEntry, error) {
+	body, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(trimmed) == 0 {
+		return nil, ErrImportEmpty
+	}
+
+	var entries []trackerEntry
+	switch {
+	case trimmed[0] != '[' && trimmed[0] != '{':
+		entries, err = parseTrackerCSV(bytes.NewReader(trimmed), layouts, loc)
+	case source == models.ImportSourceToggl:
+		entries, err = parseTogglJSON(trimmed)
+	default:
+		entries, err = parseClockifyJSON(trimmed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrImportEmpty
+	}
+
+	return entries, nil
+}
+
+func parseTogglJSON(body []byte) ([]trackerEntry, error) {
+	type togglEntry struct {
+		ID          json.RawMessage `json:"id"`
+		Description string          `json:"description"`
+		Project     string          `json:"project"`
+		ProjectName string          `json:"project_name"`
+		Start       string          `json:"start"`
+		Stop        string          `json:"stop"`
+		End         string          `json:"end"`
+	}
+
+	var raw []togglEntry
+	if body[0] == '{' {
+		var report struct {
+			Data []togglEntry `json:"data"`
+		}
+		if err := json.Unmarshal(body, &report); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTrackerExport, err)
+		}
+		raw = report.Data
+	} else if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrackerExport, err)
+	}
+
+	entries := make([]trackerEntry, len(raw))
+	for i, r := range raw {
+		project := r.Project
+		if project == "" {
+			project = r.ProjectName
+		}
+		end := r.Stop
+		if end == "" {
+			end = r.End
+		}
+		entries[i] = newJSONTrackerEntry(strings.Trim(string(r.ID), `"`), r.Description, project, r.Start, end)
+	}
+	return entries, nil
+}
+
+func parseClockifyJSON(body []byte) ([]trackerEntry, error) {
+	type clockifyEntry struct {
+		ID           string `json:"id"`
+		Description  string `json:"description"`
+		TimeInterval struct {
+			Start string `json:"start"`
+			End   string `json:"end"`
+		} `json:"timeInterval"`
+		// Only present on hydrated entries
+		Project *struct {
+			Name string `json:"name"`
+		} `json:"project"`
+	}
+
+	var raw []clockifyEntry
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrackerExport, err)
+	}
+
+	entries := make([]trackerEntry, len(raw))
+	for i, r := range raw {
+		project := ""
+		if r.Project != nil {
+			project = r.Project.Name
+		}
+		entries[i] = newJSONTrackerEntry(r.ID, r.Description, project, r.TimeInterval.Start, r.TimeInterval.End)
+	}
+	return entries, nil
+}
+
+func newJSONTrackerEntry(ref, description, project, start, end string) trackerEntry {
+	e := trackerEntry{entry: models.TrackerEntry{Ref: ref, Description: description, Project: project}}
+
+	var err error
+	if e.start, err = time.Parse(time.RFC3339, start); err != nil {
+		e.entry.Error = &models.ImportRowError{Rule: ImportRuleInvalidTime, Message: fmt.Sprintf("Start %q is not an RFC 3339 time", start)}
+		return e
+	}
+	if end == "" {
+		e.entry.Error = &models.ImportRowError{Rule: ImportRuleRunning, Message: "The entry is still running"}
+		return e
+	}
+	if e.end, err = time.Parse(time.RFC3339, end); err != nil {
+		e.entry.Error = &models.ImportRowError{Rule: ImportRuleInvalidTime, Message: fmt.Sprintf("End %q is not an RFC 3339 time", end)}
+		return e
+	}
+
+	return checkTrackerEntry(e)
+}
+
+// parseTrackerCSV reads a Toggl or Clockify detailed report CSV. Both have
+// Description, Project, Start Date, Start Time, End Date and End Time
+// columns.
+func parseTrackerCSV(file io.Reader, layouts importLayouts, loc *time.Location) ([]trackerEntry, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrImportEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrackerExport, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	required := []string{"start date", "start time", "end date", "end time"}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrImportColumnNotFound, name)
+		}
+	}
+	optional := func(name string) int {
+		if i, ok := columns[name]; ok {
+			return i
+		}
+		return -1
+	}
+	descCol, projectCol := optional("description"), optional("project")
+
+	var entries []trackerEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTrackerExport, err)
+		}
+		if blankRecord(record) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		cell := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		e := trackerEntry{entry: models.TrackerEntry{
+			Ref:         fmt.Sprintf("line %d", line),
+			Description: cell(descCol),
+			Project:     cell(projectCol),
+		}}
+
+		var readErr *models.ImportRowError
+		e.start, readErr = layouts.parseDateTime(cell(columns["start date"]), cell(columns["start time"]), loc)
+		if readErr == nil {
+			e.end, readErr = layouts.parseDateTime(cell(columns["end date"]), cell(columns["end time"]), loc)
+		}
+		if readErr != nil {
+			e.entry.Error = readErr
+			entries = append(entries, e)
+			continue
+		}
+		entries = append(entries, checkTrackerEntry(e))
+	}
+
+	return entries, nil
+}
+
+// parseDateTime reads a date and a time of day from separate cells.
+func (l importLayouts) parseDateTime(date, clock string, loc *time.Location) (time.Time, *models.ImportRowError) {
+	day, ok := parseAny(l.dates, date, loc)
+	if !ok {
+		return time.Time{}, &models.ImportRowError{Rule: ImportRuleInvalidTime, Message: fmt.Sprintf("Date %q is not a recognised date", date)}
+	}
+	t, ok := parseAny(l.times, clock, time.UTC)
+	if !ok {
+		return time.Time{}, &models.ImportRowError{Rule: ImportRuleInvalidTime, Message: fmt.Sprintf("Time %q is not a recognised time", clock)}
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc), nil
+}
+
+// checkTrackerEntry rejects entries that end before they start.
+func checkTrackerEntry(e trackerEntry) trackerEntry {
+	if !e.end.After(e.start) {
+		e.entry.Error = &models.ImportRowError{Rule: ImportRuleTimeRange, Message: ErrInvalidTimeRange.Error()}
+		return e
+	}
+	start, end := e.start.UTC(), e.end.UTC()
+	e.start, e.end = start, end
+	e.entry.StartTime, e.entry.EndTime = &start, &end
+	return e
+}
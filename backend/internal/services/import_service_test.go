@@ -0,0 +1,323 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestParseSessionCSV(t *testing.T) {
+	loc := time.FixedZone("EST", -5*3600)
+	file := "\ufeffDate;Clock In;Clock Out;Note\n" +
+		"2025-03-10;09:00;17:30;first\n" +
+		";;;\n" +
+		"2025-03-11;22:00;06:00;overnight\n" +
+		"2025-03-12;nine;17:00;bad\n"
+	input := models.SessionImportInput{
+		StartColumn: "clock in",
+		EndColumn:   "CLOCK OUT",
+		DateColumn:  "date",
+		Delimiter:   "semicolon",
+	}
+
+	candidates, err := parseSessionCSV(strings.NewReader(file), input, loc)
+	if err != nil {
+		t.Fatalf("parseSessionCSV() error = %v", err)
+	}
+	if len(candidates) != 3 {
+		t.Fatalf("got %d candidates, want 3 (blank row skipped)", len(candidates))
+	}
+
+	first := candidates[0]
+	if first.row != 2 || first.err != nil ||
+		!first.start.Equal(time.Date(2025, 3, 10, 9, 0, 0, 0, loc)) ||
+		!first.end.Equal(time.Date(2025, 3, 10, 17, 30, 0, 0, loc)) {
+		t.Errorf("first row = %+v", first)
+	}
+
+	overnight := candidates[1]
+	if overnight.row != 4 || !overnight.end.Equal(time.Date(2025, 3, 12, 6, 0, 0, 0, loc)) {
+		t.Errorf("overnight row = %+v, want it to end the next morning", overnight)
+	}
+
+	bad := candidates[2]
+	if bad.row != 5 || bad.err == nil || bad.err.Rule != ImportRuleInvalidTime {
+		t.Errorf("bad row = %+v, want an invalid_time error", bad)
+	}
+}
+
+func TestParseSessionCSVErrors(t *testing.T) {
+	input := models.SessionImportInput{StartColumn: "start", EndColumn: "end"}
+
+	tests := []struct {
+		name string
+		file string
+		want error
+	}{
+		{"empty file", "", ErrImportEmpty},
+		{"header only", "start,end\n", ErrImportEmpty},
+		{"missing column", "start,finish\n2025-03-10 09:00,2025-03-10 17:00\n", ErrImportColumnNotFound},
+		{"broken quoting", "start,end\n\"2025-03-10 09:00,2025-03-10 17:00\n", ErrInvalidImportFile},
+		{"too many rows", "start,end\n" + strings.Repeat("2025-03-10 09:00,2025-03-10 17:00\n", maxImportRows+1), ErrImportTooManyRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseSessionCSV(strings.NewReader(tt.file), input, time.UTC); !errors.Is(err, tt.want) {
+				t.Errorf("parseSessionCSV() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	loc := time.UTC
+	at := func(day, hour, min int) time.Time { return time.Date(2025, 3, day, hour, min, 0, 0, loc) }
+
+	tests := []struct {
+		name               string
+		dayFirst, hasDate  bool
+		date, start, end   string
+		wantStart, wantEnd time.Time
+		wantErr            bool
+	}{
+		{name: "full date-times", start: "2025-03-10 09:00", end: "2025-03-10T17:15", wantStart: at(10, 9, 0), wantEnd: at(10, 17, 15)},
+		{name: "US dates", start: "3/10/2025 9:00 am", end: "03/10/2025 5:00 PM", wantStart: at(10, 9, 0), wantEnd: at(10, 17, 0)},
+		{name: "day first", dayFirst: true, start: "10.03.2025 09:00", end: "10/03/2025 17:00", wantStart: at(10, 9, 0), wantEnd: at(10, 17, 0)},
+		{name: "times on a date", hasDate: true, date: "2025-03-10", start: "9AM", end: "13:30", wantStart: at(10, 9, 0), wantEnd: at(10, 13, 30)},
+		{name: "end past midnight", hasDate: true, date: "2025-03-10", start: "22:00", end: "02:00", wantStart: at(10, 22, 0), wantEnd: at(11, 2, 0)},
+		{name: "full end on a date", hasDate: true, date: "2025-03-10", start: "22:00", end: "2025-03-10 21:00", wantStart: at(10, 22, 0), wantEnd: at(10, 21, 0)},
+		{name: "time without date column", start: "09:00", end: "17:00", wantErr: true},
+		{name: "bad date", hasDate: true, date: "March 10", start: "09:00", end: "17:00", wantErr: true},
+		{name: "blank end", hasDate: true, date: "2025-03-10", start: "09:00", end: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, rowErr := newImportLayouts(tt.dayFirst).parseInterval(tt.date, tt.start, tt.end, tt.hasDate, loc)
+			if tt.wantErr {
+				if rowErr == nil || rowErr.Rule != ImportRuleInvalidTime {
+					t.Errorf("parseInterval() = %v, %v, %+v, want an invalid_time error", start, end, rowErr)
+				}
+				return
+			}
+			if rowErr != nil || !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("parseInterval() = %v, %v, %+v, want %v, %v", start, end, rowErr, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestImportOverlaps(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, 3, 10, hour, 0, 0, 0, time.UTC) }
+	storedEnd := at(12)
+	stored := models.TimeSession{ID: uuid.New(), StartTime: at(9), EndTime: &storedEnd}
+	active := models.TimeSession{ID: uuid.New(), StartTime: at(20)}
+
+	o := &importOverlaps{stored: []models.TimeSession{stored, active}}
+	o.add(at(13), at(15), "row 2")
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		wantIDs    []uuid.UUID
+		overlap    bool
+	}{
+		{name: "before everything", start: at(6), end: at(9)},
+		{name: "overlaps stored", start: at(11), end: at(13), overlap: true, wantIDs: []uuid.UUID{stored.ID}},
+		{name: "touches stored end", start: at(12), end: at(13)},
+		{name: "overlaps accepted row", start: at(14), end: at(16), overlap: true},
+		{name: "after active start", start: at(21), end: at(22), overlap: true, wantIDs: []uuid.UUID{active.ID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rowErr := o.check(tt.start, tt.end)
+			if (rowErr != nil) != tt.overlap {
+				t.Fatalf("check() = %+v, want overlap %v", rowErr, tt.overlap)
+			}
+			if rowErr == nil {
+				return
+			}
+			if rowErr.Rule != ImportRuleOverlap {
+				t.Errorf("Rule = %q, want %q", rowErr.Rule, ImportRuleOverlap)
+			}
+			if len(rowErr.SessionIDs) != len(tt.wantIDs) || (len(tt.wantIDs) > 0 && rowErr.SessionIDs[0] != tt.wantIDs[0]) {
+				t.Errorf("SessionIDs = %v, want %v", rowErr.SessionIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestParseTrackerExportToggl(t *testing.T) {
+	tests := map[string]string{
+		"track api": `[
+			{"id": 101, "description": "Code review", "project": "Web", "start": "2025-03-10T09:00:00Z", "stop": "2025-03-10T10:30:00Z"},
+			{"id": 102, "description": "Standup", "start": "2025-03-10T11:00:00+01:00", "stop": null},
+			{"id": 103, "description": "Backwards", "start": "2025-03-10T12:00:00Z", "stop": "2025-03-10T11:00:00Z"}
+		]`,
+		"detailed report": `{"data": [
+			{"id": 101, "description": "Code review", "project_name": "Web", "start": "2025-03-10T09:00:00Z", "end": "2025-03-10T10:30:00Z"},
+			{"id": 102, "description": "Standup", "start": "2025-03-10T11:00:00+01:00"},
+			{"id": 103, "description": "Backwards", "start": "2025-03-10T12:00:00Z", "end": "2025-03-10T11:00:00Z"}
+		]}`,
+	}
+
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := parseTrackerExport(models.ImportSourceToggl, strings.NewReader(body), newImportLayouts(false), time.UTC)
+			if err != nil {
+				t.Fatalf("parseTrackerExport() error = %v", err)
+			}
+			if len(entries) != 3 {
+				t.Fatalf("got %d entries, want 3", len(entries))
+			}
+
+			done := entries[0]
+			if done.entry.Ref != "101" || done.entry.Project != "Web" || done.entry.Error != nil ||
+				done.end.Sub(done.start) != 90*time.Minute || done.entry.StartTime == nil {
+				t.Errorf("entry 101 = %+v", done)
+			}
+			if e := entries[1].entry.Error; e == nil || e.Rule != ImportRuleRunning {
+				t.Errorf("running entry error = %+v, want %s", e, ImportRuleRunning)
+			}
+			if e := entries[2].entry.Error; e == nil || e.Rule != ImportRuleTimeRange {
+				t.Errorf("backwards entry error = %+v, want %s", e, ImportRuleTimeRange)
+			}
+		})
+	}
+}
+
+func TestParseTrackerExportClockify(t *testing.T) {
+	body := "\xef\xbb\xbf" + `[
+		{"id": "a1", "description": "Design", "timeInterval": {"start": "2025-03-10T14:00:00Z", "end": "2025-03-10T15:00:00Z"}, "project": {"name": "App"}},
+		{"id": "a2", "description": "", "timeInterval": {"start": "10 March", "end": "2025-03-10T16:00:00Z"}}
+	]`
+
+	entries, err := parseTrackerExport(models.ImportSourceClockify, strings.NewReader(body), newImportLayouts(false), time.UTC)
+	if err != nil {
+		t.Fatalf("parseTrackerExport() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if e := entries[0]; e.entry.Ref != "a1" || e.entry.Project != "App" || e.entry.Error != nil {
+		t.Errorf("entry a1 = %+v", e)
+	}
+	if e := entries[1].entry.Error; e == nil || e.Rule != ImportRuleInvalidTime {
+		t.Errorf("entry a2 error = %+v, want %s", e, ImportRuleInvalidTime)
+	}
+}
+
+func TestParseTrackerExportCSV(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	body := "Project,Description,Start Date,Start Time,End Date,End Time,Duration\n" +
+		"Web,Code review,10/03/2025,09:00:00,10/03/2025,10:30:00,01:30:00\n" +
+		",,,,,,\n" +
+		"Web,Late,10/03/2025,23:00:00,11/03/2025,01:00:00,02:00:00\n" +
+		"Web,Bad,10/03/2025,noon,10/03/2025,13:00:00,\n"
+
+	entries, err := parseTrackerExport(models.ImportSourceClockify, strings.NewReader(body), newImportLayouts(true), loc)
+	if err != nil {
+		t.Fatalf("parseTrackerExport() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3 (blank row skipped)", len(entries))
+	}
+
+	first := entries[0]
+	if first.entry.Ref != "line 2" || first.entry.Description != "Code review" ||
+		!first.start.Equal(time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("first entry = %+v", first)
+	}
+	if late := entries[1]; late.entry.Ref != "line 4" || late.end.Sub(late.start) != 2*time.Hour {
+		t.Errorf("late entry = %+v", late)
+	}
+	if e := entries[2].entry.Error; e == nil || e.Rule != ImportRuleInvalidTime {
+		t.Errorf("bad entry error = %+v, want %s", e, ImportRuleInvalidTime)
+	}
+}
+
+func TestParseTrackerExportErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source models.ImportSource
+		body   string
+		want   error
+	}{
+		{"empty", models.ImportSourceToggl, " \n", ErrImportEmpty},
+		{"empty array", models.ImportSourceToggl, "[]", ErrImportEmpty},
+		{"broken json", models.ImportSourceToggl, `[{"id": 1,`, ErrInvalidTrackerExport},
+		{"clockify object", models.ImportSourceClockify, `{"data": []}`, ErrInvalidTrackerExport},
+		{"csv missing column", models.ImportSourceToggl, "Description,Start Date,Start Time\nx,2025-03-10,09:00\n", ErrImportColumnNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseTrackerExport(tt.source, strings.NewReader(tt.body), newImportLayouts(false), time.UTC); !errors.Is(err, tt.want) {
+				t.Errorf("parseTrackerExport() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeSpans(t *testing.T) {
+	at := func(hour, min int) time.Time { return time.Date(2025, 3, 10, hour, min, 0, 0, time.UTC) }
+	entry := func(start, end time.Time) trackerEntry { return trackerEntry{start: start, end: end} }
+
+	spans := mergeSpans([]trackerEntry{
+		entry(at(9, 0), at(10, 0)),
+		entry(at(9, 30), at(9, 45)), // inside the first
+		entry(at(10, 0), at(11, 0)), // touches the first
+		entry(at(12, 0), at(13, 0)),
+	})
+
+	want := [][2]time.Time{{at(9, 0), at(11, 0)}, {at(12, 0), at(13, 0)}}
+	if len(spans) != len(want) {
+		t.Fatalf("mergeSpans() = %v, want %v", spans, want)
+	}
+	for i := range want {
+		if !spans[i][0].Equal(want[i][0]) || !spans[i][1].Equal(want[i][1]) {
+			t.Errorf("span %d = %v, want %v", i, spans[i], want[i])
+		}
+	}
+}
+
+func TestTrackerContent(t *testing.T) {
+	if trackerContent([]trackerEntry{{}}) != nil {
+		t.Error("entries without text should give no document")
+	}
+
+	content := trackerContent([]trackerEntry{
+		{entry: models.TrackerEntry{Description: "Code review", Project: "Web"}},
+		{entry: models.TrackerEntry{Description: " Code review ", Project: "Web"}},
+		{entry: models.TrackerEntry{Project: "Admin"}},
+		{entry: models.TrackerEntry{Description: "Lunch"}},
+	})
+
+	var doc struct {
+		Type    string `json:"type"`
+		Content []struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		t.Fatalf("content is not JSON: %v", err)
+	}
+
+	var texts []string
+	for _, p := range doc.Content {
+		texts = append(texts, p.Content[0].Text)
+	}
+	if doc.Type != "doc" || strings.Join(texts, "|") != "Web: Code review|Admin|Lunch" {
+		t.Errorf("paragraphs = %q", texts)
+	}
+}
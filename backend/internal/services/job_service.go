@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"log_book/internal/models"
+	"log_book/internal/repository"
+)
+
+// Background job types.
+const (
+	JobTypeStopScheduledSessions = "sessions.stop_scheduled"
+	JobTypeProcessIdleSessions   = "sessions.process_idle"
+	JobTypeCloseOverdueSessions  = "sessions.close_overdue"
+	JobTypeStartPlannedSessions  = "sessions.start_planned"
+	JobTypeRecordAIUsage         = "ai_usage.record"
+	JobTypePruneJobs             = "jobs.prune"
+	JobTypeSendMail              = "mail.send"
+	JobTypeSendWeeklyReminders   = "compliance.weekly_reminders"
+	JobTypeSendSignatureRequest  = "signature_requests.send"
+)
+
+const (
+	defaultJobMaxAttempts = 5
+	jobRetryBaseDelay     = 30 * time.Second
+	jobRetryMaxDelay      = time.Hour
+	// JobTimeout bounds a single attempt. A job still marked running well
+	// after that belongs to a worker that died and is reclaimed.
+	JobTimeout        = 2 * time.Minute
+	jobStaleAfter     = 5 * JobTimeout
+	jobRetentionAfter = 7 * 24 * time.Hour
+)
+
+// JobOptions tunes how a job is queued. A UniqueKey keeps at most one job with
+// that key queued or running; a zero MaxAttempts uses the default of 5.
+type JobOptions struct {
+	UniqueKey   string
+	MaxAttempts int
+	RunAt       time.Time
+}
+
+type JobService struct {
+	jobRepo *repository.JobRepository
+}
+
+func NewJobService(jobRepo *repository.JobRepository) *JobService {
+	return &JobService{jobRepo: jobRepo}
+}
+
+// Enqueue queues a job of jobType with payload encoded as JSON. It returns
+// nil without queueing when opts.UniqueKey is already queued or running.
+func (s *JobService) Enqueue(ctx context.Context, jobType string, payload interface{}, opts JobOptions) (*models.Job, error) {
+	if payload == nil {
+		payload = struct{}{}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.Job{
+		Type:        jobType,
+		Payload:     data,
+		MaxAttempts: opts.MaxAttempts,
+		RunAt:       opts.RunAt,
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = defaultJobMaxAttempts
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now().UTC()
+	}
+	if opts.UniqueKey != "" {
+		job.UniqueKey = &opts.UniqueKey
+	}
+
+	queued, err := s.jobRepo.Enqueue(ctx, job)
+	if err != nil || !queued {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Claim locks the next ready job for worker, or returns nil.
+func (s *JobService) Claim(ctx context.Context, worker string) (*models.Job, error) {
+	return s.jobRepo.Claim(ctx, worker, time.Now().UTC().Add(-jobStaleAfter))
+}
+
+// Finish records the outcome of an attempt. Failed jobs are retried with
+// exponential backoff until they run out of attempts and become dead.
+func (s *JobService) Finish(ctx context.Context, job *models.Job, worker string, startedAt time.Time, jobErr error) error {
+	run := &models.JobRun{
+		Attempt:    job.Attempts,
+		Worker:     worker,
+		StartedAt:  startedAt,
+		FinishedAt: time.Now().UTC(),
+		Succeeded:  jobErr == nil,
+	}
+
+	if jobErr != nil {
+		msg := jobErr.Error()
+		run.Error = &msg
+	}
+
+	status, retryAt := nextJobStatus(job, jobErr != nil, run.FinishedAt)
+	return s.jobRepo.Finish(ctx, job, run, status, retryAt)
+}
+
+// nextJobStatus returns where an attempt that finished at finishedAt leaves
+// the job, and when it runs again if it is retried.
+func nextJobStatus(job *models.Job, failed bool, finishedAt time.Time) (models.JobStatus, time.Time) {
+	switch {
+	case !failed:
+		return models.JobStatusSucceeded, job.RunAt
+	case job.Attempts < job.MaxAttempts:
+		return models.JobStatusPending, finishedAt.Add(retryDelay(job.Attempts))
+	default:
+		return models.JobStatusDead, job.RunAt
+	}
+}
+
+// retryDelay doubles from 30 seconds per attempt, capped at an hour.
+func retryDelay(attempt int) time.Duration {
+	delay := jobRetryBaseDelay
+	for i := 1; i < attempt && delay < jobRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > jobRetryMaxDelay {
+		delay = jobRetryMaxDelay
+	}
+	return delay
+}
+
+// PruneJobs deletes succeeded jobs older than the retention period.
+func (s *JobService) PruneJobs(ctx context.Context) (int64, error) {
+	return s.jobRepo.PruneSucceeded(ctx, time.Now().UTC().Add(-jobRetentionAfter))
+}
+
+// Admin management
+
+func (s *JobService) ListJobs(ctx context.Context, params models.JobListParams) ([]models.Job, int, error) {
+	return s.jobRepo.List(ctx, params)
+}
+
+// GetJob returns a job with its run history.
+func (s *JobService) GetJob(ctx context.Context, jobID string) (*models.Job, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+
+	job.Runs, err = s.jobRepo.ListRuns(ctx, job.ID)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// RetryJob queues a dead job again.
+func (s *JobService) RetryJob(ctx context.Context, jobID string) (*models.Job, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+
+	ok, err := s.jobRepo.Retry(ctx, job.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrJobAlreadyQueued
+		}
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrJobNotDead
+	}
+
+	return s.jobRepo.GetByID(ctx, jobID)
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"log_book/internal/models"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		7:  32 * time.Minute,
+		8:  time.Hour,
+		50: time.Hour,
+	}
+	for attempt, want := range tests {
+		if got := retryDelay(attempt); got != want {
+			t.Errorf("retryDelay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestNextJobStatus(t *testing.T) {
+	runAt := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	finished := runAt.Add(time.Minute)
+
+	tests := []struct {
+		name       string
+		attempts   int
+		failed     bool
+		wantStatus models.JobStatus
+		wantRunAt  time.Time
+	}{
+		{"success", 1, false, models.JobStatusSucceeded, runAt},
+		{"failure with attempts left", 2, true, models.JobStatusPending, finished.Add(time.Minute)},
+		{"failure on the last attempt", 3, true, models.JobStatusDead, runAt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &models.Job{Attempts: tt.attempts, MaxAttempts: 3, RunAt: runAt}
+			status, next := nextJobStatus(job, tt.failed, finished)
+			if status != tt.wantStatus || !next.Equal(tt.wantRunAt) {
+				t.Errorf("nextJobStatus() = %s, %v; want %s, %v", status, next, tt.wantStatus, tt.wantRunAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"log_book/internal/models"
+	"log_book/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// plannedStartGrace is how late a planned start may still fire, e.g. after a
+// restart. Older occurrences are recorded as missed instead.
+const plannedStartGrace = 15 * time.Minute
+
+// Outcomes recorded on a planned start after the scheduler fires it.
+const (
+	PlannedStartResultStarted = "started"
+	PlannedStartResultMissed  = "missed"
+)
+
+type PlannedStartService struct {
+	startRepo   *repository.PlannedStartRepository
+	userRepo    *repository.UserRepository
+	timeService *TimeService
+}
+
+func NewPlannedStartService(startRepo *repository.PlannedStartRepository, userRepo *repository.UserRepository, timeService *TimeService) *PlannedStartService {
+	return &PlannedStartService{
+		startRepo:   startRepo,
+		userRepo:    userRepo,
+		timeService: timeService,
+	}
+}
+
+// ListPlannedStarts returns the user's planned shift starts.
+func (s *PlannedStartService) ListPlannedStarts(ctx context.Context, clerkID string) ([]models.PlannedStart, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.startRepo.ListByUser(ctx, user.ID)
+}
+
+func (s *PlannedStartService) CreatePlannedStart(ctx context.Context, clerkID string, input models.PlannedStartInput) (*models.PlannedStart, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := &models.PlannedStart{UserID: user.ID, Enabled: true}
+	if err := applyPlannedStartInput(start, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.startRepo.Create(ctx, start); err != nil {
+		return nil, err
+	}
+
+	return start, nil
+}
+
+func (s *PlannedStartService) UpdatePlannedStart(ctx context.Context, clerkID string, startID string, input models.PlannedStartInput) (*models.PlannedStart, error) {
+	start, err := s.getOwnedPlannedStart(ctx, clerkID, startID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyPlannedStartInput(start, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.startRepo.Update(ctx, start); err != nil {
+		return nil, err
+	}
+
+	return start, nil
+}
+
+func (s *PlannedStartService) DeletePlannedStart(ctx context.Context, clerkID string, startID string) error {
+	start, err := s.getOwnedPlannedStart(ctx, clerkID, startID)
+	if err != nil {
+		return err
+	}
+
+	return s.startRepo.Delete(ctx, start.ID)
+}
+
+func (s *PlannedStartService) getOwnedPlannedStart(ctx context.Context, clerkID string, startID string) (*models.PlannedStart, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := s.startRepo.GetByID(ctx, startID)
+	if err != nil {
+		return nil, ErrPlannedStartNotFound
+	}
+
+	if start.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	return start, nil
+}
+
+// applyPlannedStartInput sets either the one-off or the recurring fields.
+func applyPlannedStartInput(start *models.PlannedStart, input models.PlannedStartInput) error {
+	start.Name = input.Name
+	if input.Enabled != nil {
+		start.Enabled = *input.Enabled
+	}
+
+	if input.StartAt != "" {
+		if len(input.Days) > 0 || input.StartTime != "" {
+			return ErrInvalidPlannedStart
+		}
+		at, err := time.Parse(time.RFC3339, input.StartAt)
+		if err != nil || !at.After(time.Now()) {
+			return ErrInvalidPlannedStart
+		}
+		at = at.UTC()
+		start.StartAt = &at
+		start.Days = nil
+		start.StartTime = nil
+		return nil
+	}
+
+	if len(input.Days) == 0 {
+		return ErrInvalidPlannedStart
+	}
+	if _, _, ok := parseClock(input.StartTime); !ok {
+		return ErrInvalidPlannedStart
+	}
+	startTime := input.StartTime
+	start.StartAt = nil
+	start.Days = uniqueDays(input.Days)
+	start.StartTime = &startTime
+	return nil
+}
+
+// ProcessPlannedStarts is called by the scheduler to open sessions for
+// planned starts that are due. A start that is blocked by the active-session
+// or per-day rules is recorded as skipped rather than retried.
+func (s *PlannedStartService) ProcessPlannedStarts(ctx context.Context) (int, error) {
+	starts, err := s.startRepo.ListEnabled(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	users := make(map[uuid.UUID]*models.User)
+
+	count := 0
+	for _, start := range starts {
+		user, ok := users[start.UserID]
+		if !ok {
+			user, err = s.userRepo.GetByID(ctx, start.UserID)
+			if err != nil {
+				continue
+			}
+			users[start.UserID] = user
+		}
+
+		due, ok := dueOccurrence(&start, user.Location(), now)
+		if !ok {
+			continue
+		}
+
+		if now.Sub(due) > plannedStartGrace {
+			if err := s.startRepo.MarkFired(ctx, start.ID, now, PlannedStartResultMissed, nil); err != nil {
+				log.Printf("Failed to record missed planned start %s: %v", start.ID, err)
+			}
+			continue
+		}
+
+		result := PlannedStartResultStarted
+		var sessionID *uuid.UUID
+
+		session, err := s.timeService.StartPlannedSession(ctx, user, due)
+		var violation *PolicyViolationError
+		switch {
+		case err == nil:
+			sessionID = &session.ID
+			count++
+		case errors.Is(err, ErrSessionAlreadyActive):
+			result = "skipped: a session was already active"
+		case errors.As(err, &violation):
+			result = "skipped: " + violation.Message
+		default:
+			// Transient failure; try again on the next tick
+			log.Printf("Failed to start planned session %s: %v", start.ID, err)
+			continue
+		}
+
+		if err := s.startRepo.MarkFired(ctx, start.ID, now, result, sessionID); err != nil {
+			log.Printf("Failed to record planned start %s: %v", start.ID, err)
+		}
+	}
+
+	return count, nil
+}
+
+// dueOccurrence returns the most recent occurrence of a planned start that is
+// at or before now and has not fired yet. Recurring starts are evaluated in
+// the user's time zone; yesterday is checked so a start just before midnight
+// is not lost. Occurrences from before the start was created or last edited
+// never fire.
+func dueOccurrence(start *models.PlannedStart, loc *time.Location, now time.Time) (time.Time, bool) {
+	if !start.Recurring() {
+		if start.StartAt.After(now) {
+			return time.Time{}, false
+		}
+		return *start.StartAt, true
+	}
+
+	hour, minute, ok := parseClock(*start.StartTime)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	local := now.In(loc)
+	for offset := 0; offset >= -1; offset-- {
+		day := local.AddDate(0, 0, offset)
+		if !start.AppliesOn(day.Weekday()) {
+			continue
+		}
+
+		occurrence := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc).UTC()
+		if occurrence.After(now) {
+			continue
+		}
+		if start.LastFiredAt == nil && occurrence.Before(start.UpdatedAt) {
+			return time.Time{}, false
+		}
+		if start.LastFiredAt != nil && !start.LastFiredAt.Before(occurrence) {
+			return time.Time{}, false
+		}
+		return occurrence, true
+	}
+
+	return time.Time{}, false
+}
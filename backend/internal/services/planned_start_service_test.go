@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"log_book/internal/models"
+)
+
+func TestDueOccurrence(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 1, day, hour, minute, 0, 0, ny).UTC()
+	}
+	clock := func(v string) *string { return &v }
+	created := at(1, 0, 0)
+
+	tests := []struct {
+		name   string
+		start  models.PlannedStart
+		now    time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name:   "one-off in the past",
+			start:  models.PlannedStart{StartAt: ptrTime(at(13, 9, 0))},
+			now:    at(13, 9, 1),
+			want:   at(13, 9, 0),
+			wantOK: true,
+		},
+		{
+			name:  "one-off in the future",
+			start: models.PlannedStart{StartAt: ptrTime(at(13, 9, 0))},
+			now:   at(13, 8, 59),
+		},
+		{
+			// 13 January 2025 is a Monday
+			name:   "recurring today in the user's zone",
+			start:  models.PlannedStart{Days: []int{1}, StartTime: clock("09:00"), UpdatedAt: created},
+			now:    at(13, 10, 0),
+			want:   at(13, 9, 0),
+			wantOK: true,
+		},
+		{
+			name:  "recurring on another weekday",
+			start: models.PlannedStart{Days: []int{2}, StartTime: clock("09:00"), UpdatedAt: created},
+			now:   at(13, 10, 0),
+		},
+		{
+			name: "recurring already fired",
+			start: models.PlannedStart{Days: []int{1}, StartTime: clock("09:00"), UpdatedAt: created,
+				LastFiredAt: ptrTime(at(13, 9, 0).Add(30 * time.Second))},
+			now: at(13, 10, 0),
+		},
+		{
+			name:  "recurring edited after today's occurrence",
+			start: models.PlannedStart{Days: []int{1}, StartTime: clock("09:00"), UpdatedAt: at(13, 9, 30)},
+			now:   at(13, 10, 0),
+		},
+		{
+			name:   "recurring just before midnight yesterday",
+			start:  models.PlannedStart{Days: []int{0}, StartTime: clock("23:50"), UpdatedAt: created},
+			now:    at(13, 0, 5),
+			want:   at(12, 23, 50),
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := dueOccurrence(&tt.start, ny, tt.now)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("dueOccurrence() = %v, %v; want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"log_book/internal/models"
+	"log_book/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Session policy rule names, reported back to clients when a rule blocks an action.
+const (
+	PolicyRuleMinDuration    = "min_duration"
+	PolicyRuleMaxDuration    = "max_duration"
+	PolicyRuleSessionsPerDay = "max_sessions_per_day"
+	PolicyRuleNoFutureEnd    = "no_future_end"
+)
+
+// PolicyViolationError is returned when a session policy rule blocks an
+// action. It unwraps to the matching legacy sentinel (ErrSessionTooShort etc.)
+// so errors.Is keeps working for callers that only care about the kind.
+type PolicyViolationError struct {
+	Rule    string
+	Message string
+	err     error
+}
+
+func (e *PolicyViolationError) Error() string { return e.Message }
+
+func (e *PolicyViolationError) Unwrap() error { return e.err }
+
+type PolicyService struct {
+	policyRepo  *repository.PolicyRepository
+	sessionRepo *repository.SessionRepository
+	userRepo    *repository.UserRepository
+}
+
+func NewPolicyService(policyRepo *repository.PolicyRepository, sessionRepo *repository.SessionRepository, userRepo *repository.UserRepository) *PolicyService {
+	return &PolicyService{
+		policyRepo:  policyRepo,
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// Effective resolves the policy for a user: built-in defaults, then the
+// global row, then the user's role, then the user's own row.
+func (s *PolicyService) Effective(ctx context.Context, user *models.User) (models.EffectivePolicy, error) {
+	policy := models.DefaultSessionPolicy
+
+	rows, err := s.policyRepo.ListApplicable(ctx, user.ID, user.Role)
+	if err != nil {
+		return policy, err
+	}
+	for _, row := range rows {
+		policy = policy.Apply(row)
+	}
+
+	return policy, nil
+}
+
+// GetEffectivePolicy returns the resolved policy for the calling user.
+func (s *PolicyService) GetEffectivePolicy(ctx context.Context, clerkID string) (*models.EffectivePolicy, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.Effective(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// CheckStart validates starting a new session at the given time.
+func (s *PolicyService) CheckStart(ctx context.Context, user *models.User, at time.Time) error {
+	policy, err := s.Effective(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return s.checkSessionsPerDay(ctx, policy, user, at)
+}
+
+// CheckStop validates stopping a running session at now, using net worked time.
+func (s *PolicyService) CheckStop(ctx context.Context, user *models.User, session *models.TimeSession, now time.Time) error {
+	policy, err := s.Effective(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return checkMinDuration(policy, session.NetDuration(now))
+}
+
+// CheckManual validates a completed session entered after the fact. worked
+// is the time between start and end that is not a break.
+func (s *PolicyService) CheckManual(ctx context.Context, user *models.User, start, end time.Time, worked time.Duration) error {
+	policy, err := s.Effective(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if err := checkInterval(policy, start, end, worked); err != nil {
+		return err
+	}
+
+	return s.checkSessionsPerDay(ctx, policy, user, start)
+}
+
+// CheckImported validates a session from a bulk import like CheckManual,
+// against a policy resolved once for the whole import. worked is the time
+// between start and end that is not a break. sameDay counts the sessions
+// already on the session's day, stored or earlier in the import.
+func (s *PolicyService) CheckImported(policy models.EffectivePolicy, start, end time.Time, worked time.Duration, sameDay int) error {
+	if err := checkInterval(policy, start, end, worked); err != nil {
+		return err
+	}
+
+	return checkSessionCount(policy, sameDay)
+}
+
+// CheckInterval validates the start and end of an existing session without
+// the per-day limit, e.g. when correcting its end time. worked is the time
+// between start and end that is not a break.
+func (s *PolicyService) CheckInterval(ctx context.Context, user *models.User, start, end time.Time, worked time.Duration) error {
+	policy, err := s.Effective(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return checkInterval(policy, start, end, worked)
+}
+
+// MaxDuration returns the maximum session length allowed for a user.
+func (s *PolicyService) MaxDuration(ctx context.Context, user *models.User) (time.Duration, error) {
+	policy, err := s.Effective(ctx, user)
+	if err != nil {
+		return 0, err
+	}
+	return policy.MaxDuration(), nil
+}
+
+// ShortestMaxDuration returns the smallest maximum session length any user
+// can have, so sweeps only need to look at sessions older than that.
+func (s *PolicyService) ShortestMaxDuration(ctx context.Context) (time.Duration, error) {
+	shortest := models.DefaultSessionPolicy.MaxDurationMinutes
+
+	minutes, err := s.policyRepo.ShortestMaxDuration(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if minutes != nil && *minutes < shortest {
+		shortest = *minutes
+	}
+
+	return time.Duration(shortest) * time.Minute, nil
+}
+
+// checkInterval applies the duration rules to worked, the time between start
+// and end less breaks, and the future-end rule to end.
+func checkInterval(policy models.EffectivePolicy, start, end time.Time, worked time.Duration) error {
+	if err := checkMinDuration(policy, worked); err != nil {
+		return err
+	}
+
+	if worked > policy.MaxDuration() {
+		return &PolicyViolationError{
+			Rule:    PolicyRuleMaxDuration,
+			Message: fmt.Sprintf("Session duration cannot exceed %s", formatMinutes(policy.MaxDurationMinutes)),
+			err:     ErrSessionTooLong,
+		}
+	}
+
+	if !policy.AllowFutureEnd && end.After(time.Now().UTC()) {
+		return &PolicyViolationError{
+			Rule:    PolicyRuleNoFutureEnd,
+			Message: "End time cannot be in the future",
+			err:     ErrFutureEndTime,
+		}
+	}
+
+	return nil
+}
+
+func checkMinDuration(policy models.EffectivePolicy, worked time.Duration) error {
+	if worked < policy.MinDuration() {
+		return &PolicyViolationError{
+			Rule:    PolicyRuleMinDuration,
+			Message: fmt.Sprintf("Session must be at least %s of worked time", formatMinutes(policy.MinDurationMinutes)),
+			err:     ErrSessionTooShort,
+		}
+	}
+	return nil
+}
+
+func (s *PolicyService) checkSessionsPerDay(ctx context.Context, policy models.EffectivePolicy, user *models.User, at time.Time) error {
+	if policy.MaxSessionsPerDay == 0 {
+		return nil
+	}
+
+	count, err := s.sessionRepo.CountSessionsOnDate(ctx, user.ID, at, user.Location())
+	if err != nil {
+		return err
+	}
+
+	return checkSessionCount(policy, count)
+}
+
+// checkSessionCount fails when a day that already has count sessions cannot
+// take another.
+func checkSessionCount(policy models.EffectivePolicy, count int) error {
+	if policy.MaxSessionsPerDay > 0 && count >= policy.MaxSessionsPerDay {
+		msg := "A session already exists for this date. Only one session per day is allowed."
+		if policy.MaxSessionsPerDay > 1 {
+			msg = fmt.Sprintf("Only %d sessions per day are allowed.", policy.MaxSessionsPerDay)
+		}
+		return &PolicyViolationError{
+			Rule:    PolicyRuleSessionsPerDay,
+			Message: msg,
+			err:     ErrSessionExistsForDate,
+		}
+	}
+	return nil
+}
+
+// formatMinutes renders a minute count as "4 hours", "90 minutes" etc.
+func formatMinutes(m int) string {
+	switch {
+	case m == 60:
+		return "1 hour"
+	case m%60 == 0:
+		return fmt.Sprintf("%d hours", m/60)
+	default:
+		return fmt.Sprintf("%d minutes", m)
+	}
+}
+
+// Admin management
+
+func (s *PolicyService) ListPolicies(ctx context.Context) ([]models.SessionPolicy, error) {
+	return s.policyRepo.List(ctx)
+}
+
+func (s *PolicyService) CreatePolicy(ctx context.Context, input models.SessionPolicyInput) (*models.SessionPolicy, error) {
+	if err := checkPolicyDurations(input); err != nil {
+		return nil, err
+	}
+
+	policy := &models.SessionPolicy{Scope: input.Scope}
+
+	switch models.PolicyScope(input.Scope) {
+	case models.PolicyScopeRole:
+		if input.Role == nil || *input.Role == "" {
+			return nil, ErrInvalidPolicyScope
+		}
+		policy.Role = input.Role
+	case models.PolicyScopeUser:
+		if input.UserID == nil {
+			return nil, ErrInvalidPolicyScope
+		}
+		id, err := uuid.Parse(*input.UserID)
+		if err != nil {
+			return nil, ErrInvalidPolicyScope
+		}
+		if _, err := s.userRepo.GetByID(ctx, id); err != nil {
+			return nil, ErrUserNotFound
+		}
+		policy.UserID = &id
+	}
+
+	applyPolicyInput(policy, input)
+
+	if err := s.policyRepo.Create(ctx, policy); err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrPolicyExists
+		}
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (s *PolicyService) UpdatePolicy(ctx context.Context, policyID string, input models.SessionPolicyInput) (*models.SessionPolicy, error) {
+	policy, err := s.policyRepo.GetByID(ctx, policyID)
+	if err != nil {
+		return nil, ErrPolicyNotFound
+	}
+
+	if err := checkPolicyDurations(input); err != nil {
+		return nil, err
+	}
+
+	applyPolicyInput(policy, input)
+
+	if err := s.policyRepo.Update(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (s *PolicyService) DeletePolicy(ctx context.Context, policyID string) error {
+	policy, err := s.policyRepo.GetByID(ctx, policyID)
+	if err != nil {
+		return ErrPolicyNotFound
+	}
+
+	return s.policyRepo.Delete(ctx, policy.ID)
+}
+
+// checkPolicyDurations refuses a policy whose minimum duration is longer
+// than its maximum.
+func checkPolicyDurations(input models.SessionPolicyInput) error {
+	if input.MinDurationMinutes != nil && input.MaxDurationMinutes != nil &&
+		*input.MinDurationMinutes > *input.MaxDurationMinutes {
+		return ErrPolicyDurations
+	}
+	return nil
+}
+
+func applyPolicyInput(policy *models.SessionPolicy, input models.SessionPolicyInput) {
+	policy.MinDurationMinutes = input.MinDurationMinutes
+	policy.MaxDurationMinutes = input.MaxDurationMinutes
+	policy.MaxSessionsPerDay = input.MaxSessionsPerDay
+	policy.AllowFutureEnd = input.AllowFutureEnd
+}
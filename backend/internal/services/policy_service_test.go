@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"log_book/internal/models"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestCheckPolicyDurations(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max *int
+		wantErr  bool
+	}{
+		{"both unset", nil, nil, false},
+		{"only min", intPtr(600), nil, false},
+		{"only max", nil, intPtr(60), false},
+		{"min below max", intPtr(60), intPtr(480), false},
+		{"min equals max", intPtr(240), intPtr(240), false},
+		{"min above max", intPtr(480), intPtr(60), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := models.SessionPolicyInput{MinDurationMinutes: tt.min, MaxDurationMinutes: tt.max}
+			err := checkPolicyDurations(input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkPolicyDurations() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrPolicyDurations) {
+				t.Errorf("checkPolicyDurations() = %v, want ErrPolicyDurations", err)
+			}
+		})
+	}
+}
+
+func TestCheckInterval(t *testing.T) {
+	policy := models.EffectivePolicy{MinDurationMinutes: 60, MaxDurationMinutes: 480}
+	start := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		end    time.Time
+		worked time.Duration
+		want   error
+	}{
+		{"within limits", start.Add(4 * time.Hour), 4 * time.Hour, nil},
+		{"too short", start.Add(30 * time.Minute), 30 * time.Minute, ErrSessionTooShort},
+		{"too long", start.Add(9 * time.Hour), 9 * time.Hour, ErrSessionTooLong},
+		{"long span with breaks", start.Add(14 * time.Hour), 4 * time.Hour, nil},
+		{"short after breaks", start.Add(4 * time.Hour), 45 * time.Minute, ErrSessionTooShort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkInterval(policy, start, tt.end, tt.worked)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("checkInterval() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	future := time.Now().UTC().Add(time.Hour)
+	if err := checkInterval(policy, future.Add(-2*time.Hour), future, 2*time.Hour); !errors.Is(err, ErrFutureEndTime) {
+		t.Errorf("checkInterval() with future end = %v, want ErrFutureEndTime", err)
+	}
+	policy.AllowFutureEnd = true
+	if err := checkInterval(policy, future.Add(-2*time.Hour), future, 2*time.Hour); err != nil {
+		t.Errorf("checkInterval() with future end allowed = %v, want nil", err)
+	}
+}
+
+func TestCheckSessionCount(t *testing.T) {
+	tests := []struct {
+		max, count int
+		wantErr    bool
+	}{
+		{0, 10, false},
+		{1, 0, false},
+		{1, 1, true},
+		{3, 2, false},
+		{3, 3, true},
+	}
+
+	for _, tt := range tests {
+		policy := models.EffectivePolicy{MaxSessionsPerDay: tt.max}
+		err := checkSessionCount(policy, tt.count)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkSessionCount(max %d, count %d) = %v, wantErr %v", tt.max, tt.count, err, tt.wantErr)
+		}
+		var violation *PolicyViolationError
+		if err != nil && (!errors.As(err, &violation) || violation.Rule != PolicyRuleSessionsPerDay) {
+			t.Errorf("checkSessionCount() = %v, want a sessions-per-day violation", err)
+		}
+	}
+}
+
+func TestFormatMinutes(t *testing.T) {
+	tests := map[int]string{
+		60:   "1 hour",
+		240:  "4 hours",
+		1440: "24 hours",
+		90:   "90 minutes",
+	}
+	for minutes, want := range tests {
+		if got := formatMinutes(minutes); got != want {
+			t.Errorf("formatMinutes(%d) = %q, want %q", minutes, got, want)
+		}
+	}
+}
@@ -0,0 +1,527 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"log_book/internal/models"
+	"log_book/internal/pdf"
+	"log_book/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// maxReportDays bounds the date range of a single timesheet report.
+const maxReportDays = 366
+
+type ReportService struct {
+	sessionRepo  *repository.SessionRepository
+	userRepo     *repository.UserRepository
+	employerRepo *repository.EmployerRepository
+}
+
+func NewReportService(sessionRepo *repository.SessionRepository, userRepo *repository.UserRepository, employerRepo *repository.EmployerRepository) *ReportService {
+	return &ReportService{
+		sessionRepo:  sessionRepo,
+		userRepo:     userRepo,
+		employerRepo: employerRepo,
+	}
+}
+
+// TimesheetPDF renders the user's sessions as a printable timesheet. Returns
+// the document and a file name for it.
+func (s *ReportService) TimesheetPDF(ctx context.Context, clerkID string, params models.TimesheetReportParams) ([]byte, string, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var from, to *time.Time
+	if params.From != "" {
+		d, err := time.Parse("2006-01-02", params.From)
+		if err != nil {
+			return nil, "", ErrInvalidDate
+		}
+		from = &d
+	}
+	if params.To != "" {
+		d, err := time.Parse("2006-01-02", params.To)
+		if err != nil {
+			return nil, "", ErrInvalidDate
+		}
+		to = &d
+	}
+	if from != nil && to != nil && (to.Before(*from) || daysBetween(*from, *to) >= maxReportDays) {
+		return nil, "", ErrInvalidDateRange
+	}
+
+	var employer *models.Employer
+	if params.EmployerID != "" {
+		employer, err = s.employerRepo.GetByID(ctx, params.EmployerID)
+		if err != nil || employer.UserID != user.ID {
+			return nil, "", ErrEmployerNotFound
+		}
+	}
+
+	loc := user.Location()
+	sessions, err := s.sessionRepo.ListAllByUser(ctx, user.ID, models.SessionListParams{
+		Status:     params.Status,
+		FromDate:   params.From,
+		ToDate:     params.To,
+		EmployerID: params.EmployerID,
+	}, loc)
+	if err != nil {
+		return nil, "", err
+	}
+
+	report := &timesheetReport{
+		user:     user,
+		employer: employer,
+		from:     from,
+		to:       to,
+		loc:      loc,
+		sessions: sessions,
+	}
+	if len(sessions) > 0 {
+		if report.days, err = s.reportDays(ctx, user, params, from, to, sessions); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := report.render().WriteTo(&buf); err != nil {
+		if errors.Is(err, pdf.ErrUnsupportedText) {
+			return nil, "", ErrReportUnsupportedText
+		}
+		return nil, "", err
+	}
+
+	return buf.Bytes(), report.fileName(), nil
+}
+
+// HoursTotals sums the user's net time per day, ISO week or calendar month
+// over a date range. Time counts toward the day it was worked in the user's
+// time zone, so a session across midnight is split between the two days.
+func (s *ReportService) HoursTotals(ctx context.Context, clerkID string, params models.HoursTotalsParams) (*models.HoursTotalsReport, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := time.Parse("2006-01-02", params.From)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+	to, err := time.Parse("2006-01-02", params.To)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+	if to.Before(from) || daysBetween(from, to) >= maxReportDays {
+		return nil, ErrInvalidDateRange
+	}
+
+	var employerID *uuid.UUID
+	if params.EmployerID != "" {
+		employer, err := s.employerRepo.GetByID(ctx, params.EmployerID)
+		if err != nil || employer.UserID != user.ID {
+			return nil, ErrEmployerNotFound
+		}
+		employerID = &employer.ID
+	}
+
+	days, err := s.sessionRepo.ListDays(ctx, user.ID, from, to, "", employerID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.HoursTotalsReport{
+		Period: params.Period,
+		From:   from,
+		To:     to,
+		Totals: []models.HoursTotal{},
+	}
+
+	// One total per period touching the range, clipped to it
+	index := make(map[time.Time]int)
+	for start := periodStart(params.Period, from); !start.After(to); start = nextPeriod(params.Period, start) {
+		total := models.HoursTotal{PeriodStart: start, PeriodEnd: nextPeriod(params.Period, start).AddDate(0, 0, -1)}
+		if total.PeriodStart.Before(from) {
+			total.PeriodStart = from
+		}
+		if total.PeriodEnd.After(to) {
+			total.PeriodEnd = to
+		}
+		index[start] = len(report.Totals)
+		report.Totals = append(report.Totals, total)
+	}
+
+	// Days come in order, so a session seen in the same period is already counted
+	lastPeriod := make(map[uuid.UUID]int)
+	var seconds int64
+	for _, day := range days {
+		i := index[periodStart(params.Period, day.Date)]
+		total := &report.Totals[i]
+		total.Seconds += day.NetSeconds
+		if p, ok := lastPeriod[day.SessionID]; !ok || p != i {
+			lastPeriod[day.SessionID] = i
+			total.Sessions++
+		}
+		seconds += day.NetSeconds
+	}
+	for i := range report.Totals {
+		report.Totals[i].Hours = roundHours(float64(report.Totals[i].Seconds) / 3600)
+	}
+	report.TotalHours = roundHours(float64(seconds) / 3600)
+
+	return report, nil
+}
+
+// periodStart returns the first day of the day, ISO week or month containing
+// date (midnight UTC).
+func periodStart(period string, date time.Time) time.Time {
+	switch period {
+	case "week":
+		return isoWeekStart(date)
+	case "month":
+		return date.AddDate(0, 0, 1-date.Day())
+	default:
+		return date
+	}
+}
+
+// nextPeriod returns the first day of the period after the one starting at
+// start.
+func nextPeriod(period string, start time.Time) time.Time {
+	switch period {
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// reportDays apportions the listed sessions' time to the days of the report.
+// Without an explicit range the days run from the first session's start to
+// the last one's end.
+func (s *ReportService) reportDays(ctx context.Context, user *models.User, params models.TimesheetReportParams, from, to *time.Time, sessions []models.TimeSession) ([]models.SessionDay, error) {
+	loc := user.Location()
+	first := localDate(sessions[0].StartTime, loc)
+	if from != nil {
+		first = *from
+	}
+	last := localDate(time.Now(), loc)
+	for _, session := range sessions {
+		if session.EndTime != nil && localDate(*session.EndTime, loc).After(last) {
+			last = localDate(*session.EndTime, loc)
+		}
+	}
+	if to != nil {
+		last = *to
+	}
+
+	var employerID *uuid.UUID
+	if params.EmployerID != "" {
+		id, err := uuid.Parse(params.EmployerID)
+		if err != nil {
+			return nil, ErrEmployerNotFound
+		}
+		employerID = &id
+	}
+
+	return s.sessionRepo.ListDays(ctx, user.ID, first, last, params.Status, employerID)
+}
+
+// timesheetReport lays out a timesheet on US Letter pages. Sessions are
+// listed under the day they started with their full net time; week and
+// report totals count time on the day it was worked, from days.
+type timesheetReport struct {
+	user     *models.User
+	employer *models.Employer
+	from, to *time.Time
+	loc      *time.Location
+	sessions []models.TimeSession
+	days     []models.SessionDay
+
+	doc *pdf.Document
+	y   float64
+
+	// weeks holds every week with sessions or time, in order; the first
+	// printed of them have had their subtotal row drawn
+	weeks       []time.Time
+	weekSeconds map[time.Time]int64
+	printed     int
+}
+
+const (
+	reportMargin   = 50.0
+	reportRight    = pdf.PageWidth - reportMargin
+	reportBottom   = pdf.PageHeight - 60
+	reportRow      = 16.0
+	reportFontSize = 9.5
+
+	colDate  = reportMargin + 4
+	colStart = reportMargin + 140
+	colEnd   = reportMargin + 215
+	colNote  = reportMargin + 290
+	colBreak = reportRight - 90
+	colNet   = reportRight - 4
+)
+
+func (r *timesheetReport) render() *pdf.Document {
+	r.doc = pdf.New("Timesheet")
+	r.newPage()
+	r.header()
+	r.tableHeader()
+
+	total := r.tallyWeeks()
+	var day time.Time
+	for i, session := range r.sessions {
+		date := localDate(session.StartTime, r.loc)
+		r.weekRowsBefore(isoWeekStart(date))
+
+		r.ensureSpace(reportRow)
+		r.y += reportRow
+		if i == 0 || !date.Equal(day) {
+			r.doc.Text(colDate, r.y, pdf.Regular, reportFontSize, date.Format("Mon Jan 2, 2006"))
+		}
+		day = date
+		r.sessionRow(session)
+	}
+	if len(r.sessions) > 0 {
+		r.weekRowsBefore(time.Time{})
+	} else {
+		r.y += reportRow
+		r.doc.Text(colDate, r.y, pdf.Regular, reportFontSize, "No sessions in this period.")
+	}
+
+	r.ensureSpace(reportRow * 2)
+	r.y += 6
+	r.doc.Line(reportMargin, r.y, reportRight, r.y, 1)
+	r.y += reportRow
+	r.doc.Text(colDate, r.y, pdf.Bold, 11, "Total")
+	r.doc.TextRight(colNet, r.y, pdf.Bold, 11, reportHours(total))
+
+	r.signatureBlock()
+
+	return r.doc
+}
+
+// tallyWeeks sums the days into weeks and returns the report total.
+func (r *timesheetReport) tallyWeeks() int64 {
+	r.weekSeconds = make(map[time.Time]int64)
+	seen := make(map[time.Time]bool)
+	add := func(week time.Time) {
+		if !seen[week] {
+			seen[week] = true
+			r.weeks = append(r.weeks, week)
+		}
+	}
+
+	var total int64
+	for _, session := range r.sessions {
+		add(isoWeekStart(localDate(session.StartTime, r.loc)))
+	}
+	for _, day := range r.days {
+		week := isoWeekStart(day.Date)
+		add(week)
+		r.weekSeconds[week] += day.NetSeconds
+		total += day.NetSeconds
+	}
+	sort.Slice(r.weeks, func(i, j int) bool { return r.weeks[i].Before(r.weeks[j]) })
+
+	return total
+}
+
+// weekRowsBefore draws the subtotal rows of the weeks before week that are
+// still outstanding. A zero week draws all of them.
+func (r *timesheetReport) weekRowsBefore(week time.Time) {
+	for r.printed < len(r.weeks) && (week.IsZero() || r.weeks[r.printed].Before(week)) {
+		r.weekRow(r.weeks[r.printed], r.weekSeconds[r.weeks[r.printed]])
+		r.printed++
+	}
+}
+
+func (r *timesheetReport) newPage() {
+	r.doc.AddPage()
+	r.y = reportMargin
+	r.doc.TextRight(reportRight, pdf.PageHeight-30, pdf.Regular, 8, fmt.Sprintf("Page %d", r.doc.PageCount()))
+}
+
+// ensureSpace starts a new page, repeating the table header, when the next
+// height points would run past the bottom margin.
+func (r *timesheetReport) ensureSpace(height float64) {
+	if r.y+height <= reportBottom {
+		return
+	}
+	r.newPage()
+	r.tableHeader()
+}
+
+func (r *timesheetReport) header() {
+	r.y += 18
+	r.doc.Text(reportMargin, r.y, pdf.Bold, 18, "Timesheet")
+	r.y += 8
+
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		r.y += 14
+		r.doc.Text(reportMargin, r.y, pdf.Bold, 10, label)
+		r.doc.Text(reportMargin+90, r.y, pdf.Regular, 10, value)
+	}
+
+	r.y += 6
+	employee := r.user.Name
+	if employee == "" {
+		employee = r.user.Email
+	} else if r.user.Email != "" {
+		employee += " <" + r.user.Email + ">"
+	}
+	field("Employee", employee)
+	field("Period", r.period())
+	field("Time zone", r.loc.String())
+
+	if r.employer != nil {
+		r.y += 6
+		field("Employer", r.employer.Name)
+		field("Address", r.employer.Address)
+		field("EIN", r.employer.EIN)
+		field("Supervisor", r.employer.SupervisorName)
+	}
+
+	r.y += 14
+	r.doc.Text(reportMargin, r.y, pdf.Regular, 8,
+		"Generated "+time.Now().In(r.loc).Format("Jan 2, 2006 15:04 MST")+
+			". Hours are net of breaks; totals count time on the day it was worked.")
+	r.y += 12
+}
+
+func (r *timesheetReport) tableHeader() {
+	r.doc.FillRect(reportMargin, r.y, reportRight-reportMargin, reportRow+4, 0.88)
+	r.y += reportRow - 2
+	r.doc.Text(colDate, r.y, pdf.Bold, reportFontSize, "Date")
+	r.doc.Text(colStart, r.y, pdf.Bold, reportFontSize, "Start")
+	r.doc.Text(colEnd, r.y, pdf.Bold, reportFontSize, "End")
+	r.doc.Text(colNote, r.y, pdf.Bold, reportFontSize, "Note")
+	r.doc.TextRight(colBreak, r.y, pdf.Bold, reportFontSize, "Break")
+	r.doc.TextRight(colNet, r.y, pdf.Bold, reportFontSize, "Net hours")
+	r.y += 6
+}
+
+func (r *timesheetReport) sessionRow(session models.TimeSession) {
+	start := session.StartTime.In(r.loc)
+	r.doc.Text(colStart, r.y, pdf.Regular, reportFontSize, start.Format("15:04"))
+
+	end := "-"
+	if session.EndTime != nil {
+		endLocal := session.EndTime.In(r.loc)
+		end = endLocal.Format("15:04")
+		if days := daysBetween(localDate(session.StartTime, r.loc), localDate(*session.EndTime, r.loc)); days > 0 {
+			end += fmt.Sprintf(" (+%d)", days)
+		}
+	}
+	r.doc.Text(colEnd, r.y, pdf.Regular, reportFontSize, end)
+
+	note := ""
+	switch models.SessionStatus(session.Status) {
+	case models.SessionStatusActive:
+		note = "In progress"
+	case models.SessionStatusCancelled:
+		note = "Cancelled"
+	}
+	r.doc.Text(colNote, r.y, pdf.Regular, reportFontSize, note)
+
+	if session.BreakSeconds > 0 {
+		r.doc.TextRight(colBreak, r.y, pdf.Regular, reportFontSize, reportHours(session.BreakSeconds))
+	}
+	r.doc.TextRight(colNet, r.y, pdf.Regular, reportFontSize, reportHours(session.NetSeconds))
+}
+
+func (r *timesheetReport) weekRow(week time.Time, seconds int64) {
+	r.ensureSpace(reportRow + 4)
+	r.y += 4
+	r.doc.FillRect(reportMargin, r.y, reportRight-reportMargin, reportRow, 0.95)
+	r.y += reportRow - 4
+	_, number := week.ISOWeek()
+	label := fmt.Sprintf("Week %d (%s - %s)", number, week.Format("Jan 2"), week.AddDate(0, 0, 6).Format("Jan 2"))
+	r.doc.Text(colDate, r.y, pdf.Bold, reportFontSize, label)
+	r.doc.TextRight(colNet, r.y, pdf.Bold, reportFontSize, reportHours(seconds))
+	r.y += 4
+}
+
+func (r *timesheetReport) signatureBlock() {
+	const height = 110.0
+	if r.y+height > reportBottom {
+		r.newPage()
+	}
+
+	r.y += 30
+	r.doc.Text(reportMargin, r.y, pdf.Regular, 9,
+		"I certify that the hours above are a true and accurate record of time worked.")
+
+	supervisor := "Supervisor"
+	if r.employer != nil && r.employer.SupervisorName != "" {
+		supervisor = "Supervisor: " + r.employer.SupervisorName
+	}
+
+	r.y += 44
+	for _, signer := range []struct {
+		x     float64
+		label string
+	}{
+		{reportMargin, "Employee: " + r.displayName()},
+		{reportMargin + 265, supervisor},
+	} {
+		r.doc.Line(signer.x, r.y, signer.x+160, r.y, 0.75)
+		r.doc.Line(signer.x+175, r.y, signer.x+245, r.y, 0.75)
+		r.doc.Text(signer.x, r.y+12, pdf.Regular, 8.5, signer.label)
+		r.doc.Text(signer.x+175, r.y+12, pdf.Regular, 8.5, "Date")
+	}
+	r.y += 12
+}
+
+func (r *timesheetReport) displayName() string {
+	if r.user.Name != "" {
+		return r.user.Name
+	}
+	return r.user.Email
+}
+
+func (r *timesheetReport) period() string {
+	const layout = "Jan 2, 2006"
+	switch {
+	case r.from != nil && r.to != nil:
+		return r.from.Format(layout) + " - " + r.to.Format(layout)
+	case r.from != nil:
+		return "From " + r.from.Format(layout)
+	case r.to != nil:
+		return "Through " + r.to.Format(layout)
+	default:
+		return "All sessions"
+	}
+}
+
+func (r *timesheetReport) fileName() string {
+	switch {
+	case r.from != nil && r.to != nil:
+		return fmt.Sprintf("timesheet-%s-to-%s.pdf", r.from.Format("2006-01-02"), r.to.Format("2006-01-02"))
+	case r.from != nil:
+		return fmt.Sprintf("timesheet-from-%s.pdf", r.from.Format("2006-01-02"))
+	case r.to != nil:
+		return fmt.Sprintf("timesheet-through-%s.pdf", r.to.Format("2006-01-02"))
+	default:
+		return "timesheet.pdf"
+	}
+}
+
+// reportHours formats seconds as decimal hours.
+func reportHours(seconds int64) string {
+	return fmt.Sprintf("%.2f", float64(seconds)/3600)
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestPeriodStart(t *testing.T) {
+	tests := []struct {
+		period string
+		date   string
+		want   string
+	}{
+		{"day", "2025-03-12", "2025-03-12"},
+		{"week", "2025-03-12", "2025-03-10"}, // Wednesday
+		{"week", "2025-03-16", "2025-03-10"}, // Sunday belongs to the week before
+		{"week", "2025-01-01", "2024-12-30"},
+		{"month", "2025-03-12", "2025-03-01"},
+		{"month", "2025-03-01", "2025-03-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period+" "+tt.date, func(t *testing.T) {
+			if got := periodStart(tt.period, mustDate(tt.date)); !got.Equal(mustDate(tt.want)) {
+				t.Errorf("periodStart() = %s, want %s", got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+func TestNextPeriod(t *testing.T) {
+	tests := []struct {
+		period string
+		start  string
+		want   string
+	}{
+		{"day", "2025-02-28", "2025-03-01"},
+		{"week", "2024-12-30", "2025-01-06"},
+		{"month", "2025-01-01", "2025-02-01"},
+		{"month", "2024-12-01", "2025-01-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period+" "+tt.start, func(t *testing.T) {
+			if got := nextPeriod(tt.period, mustDate(tt.start)); !got.Equal(mustDate(tt.want)) {
+				t.Errorf("nextPeriod() = %s, want %s", got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+func TestTallyWeeks(t *testing.T) {
+	loc := time.FixedZone("EST", -5*3600)
+	// Sunday 22:00 to Monday 02:00 local, split across two ISO weeks
+	late := models.TimeSession{ID: uuid.New(), StartTime: time.Date(2025, 3, 17, 3, 0, 0, 0, time.UTC)}
+	// Wednesday of the following week, with no time recorded
+	empty := models.TimeSession{ID: uuid.New(), StartTime: time.Date(2025, 3, 26, 14, 0, 0, 0, time.UTC)}
+
+	r := &timesheetReport{
+		loc:      loc,
+		sessions: []models.TimeSession{late, empty},
+		days: []models.SessionDay{
+			{SessionID: late.ID, Date: mustDate("2025-03-16"), NetSeconds: 2 * 3600},
+			{SessionID: late.ID, Date: mustDate("2025-03-17"), NetSeconds: 2 * 3600},
+		},
+	}
+
+	if total := r.tallyWeeks(); total != 4*3600 {
+		t.Errorf("total = %d, want %d", total, 4*3600)
+	}
+
+	want := []string{"2025-03-10", "2025-03-17", "2025-03-24"}
+	if len(r.weeks) != len(want) {
+		t.Fatalf("weeks = %v, want %v", r.weeks, want)
+	}
+	for i, week := range want {
+		if !r.weeks[i].Equal(mustDate(week)) {
+			t.Errorf("weeks[%d] = %s, want %s", i, r.weeks[i].Format("2006-01-02"), week)
+		}
+	}
+	if got := r.weekSeconds[mustDate("2025-03-10")]; got != 2*3600 {
+		t.Errorf("first week = %d seconds, want %d", got, 2*3600)
+	}
+	if got := r.weekSeconds[mustDate("2025-03-24")]; got != 0 {
+		t.Errorf("empty week = %d seconds, want 0", got)
+	}
+}
+
+func TestReportHours(t *testing.T) {
+	for seconds, want := range map[int64]string{0: "0.00", 5400: "1.50", 3600*8 + 60: "8.02"} {
+		if got := reportHours(seconds); got != want {
+			t.Errorf("reportHours(%d) = %s, want %s", seconds, got, want)
+		}
+	}
+}
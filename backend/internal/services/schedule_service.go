@@ -9,14 +9,18 @@ import (
 )
 
 type ScheduleService struct {
-	sessionRepo *repository.SessionRepository
-	userRepo    *repository.UserRepository
+	sessionRepo   *repository.SessionRepository
+	ruleRepo      *repository.ScheduleRuleRepository
+	userRepo      *repository.UserRepository
+	policyService *PolicyService
 }
 
-func NewScheduleService(sessionRepo *repository.SessionRepository, userRepo *repository.UserRepository) *ScheduleService {
+func NewScheduleService(sessionRepo *repository.SessionRepository, ruleRepo *repository.ScheduleRuleRepository, userRepo *repository.UserRepository, policyService *PolicyService) *ScheduleService {
 	return &ScheduleService{
-		sessionRepo: sessionRepo,
-		userRepo:    userRepo,
+		sessionRepo:   sessionRepo,
+		ruleRepo:      ruleRepo,
+		userRepo:      userRepo,
+		policyService: policyService,
 	}
 }
 
@@ -40,13 +44,21 @@ func (s *ScheduleService) SetSchedule(ctx context.Context, clerkID string, input
 		return nil, ErrInvalidScheduleTime
 	}
 
-	session.ScheduledEnd = &input.ScheduledEnd
+	event, ok := newSessionEvent(session.Status, models.SessionEventScheduleSet, userActor(user.ID, input.DeviceID),
+		map[string]interface{}{"scheduled_end": input.ScheduledEnd})
+	if !ok {
+		return nil, ErrNoActiveSession
+	}
 
-	err = s.sessionRepo.Update(ctx, session)
+	ok, err = s.sessionRepo.SetScheduledEnd(ctx, session.ID, &input.ScheduledEnd, event)
 	if err != nil {
 		return nil, err
 	}
+	if !ok {
+		return nil, ErrNoActiveSession
+	}
 
+	session.ScheduledEnd = &input.ScheduledEnd
 	return session, nil
 }
 
@@ -83,25 +95,265 @@ func (s *ScheduleService) CancelSchedule(ctx context.Context, clerkID string, se
 		return ErrUnauthorized
 	}
 
-	session.ScheduledEnd = nil
+	event, ok := newSessionEvent(session.Status, models.SessionEventScheduleCleared, userActor(user.ID, ""), nil)
+	if !ok {
+		return ErrNoActiveSession
+	}
+
+	ok, err = s.sessionRepo.SetScheduledEnd(ctx, session.ID, nil, event)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoActiveSession
+	}
+	return nil
+}
+
+// ListRules returns the user's recurring auto-stop rules.
+func (s *ScheduleService) ListRules(ctx context.Context, clerkID string) ([]models.ScheduleRule, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ruleRepo.ListByUser(ctx, user.ID, false)
+}
+
+func (s *ScheduleService) CreateRule(ctx context.Context, clerkID string, input models.ScheduleRuleInput) (*models.ScheduleRule, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, ok := parseClock(input.StopTime); !ok {
+		return nil, ErrInvalidStopTime
+	}
+
+	rule := &models.ScheduleRule{
+		UserID:   user.ID,
+		Name:     input.Name,
+		Days:     uniqueDays(input.Days),
+		StopTime: input.StopTime,
+		Enabled:  input.Enabled == nil || *input.Enabled,
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *ScheduleService) UpdateRule(ctx context.Context, clerkID string, ruleID string, input models.ScheduleRuleInput) (*models.ScheduleRule, error) {
+	rule, err := s.getOwnedRule(ctx, clerkID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, ok := parseClock(input.StopTime); !ok {
+		return nil, ErrInvalidStopTime
+	}
+
+	rule.Name = input.Name
+	rule.Days = uniqueDays(input.Days)
+	rule.StopTime = input.StopTime
+	if input.Enabled != nil {
+		rule.Enabled = *input.Enabled
+	}
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *ScheduleService) DeleteRule(ctx context.Context, clerkID string, ruleID string) error {
+	rule, err := s.getOwnedRule(ctx, clerkID, ruleID)
+	if err != nil {
+		return err
+	}
+
+	return s.ruleRepo.Delete(ctx, rule.ID)
+}
+
+func (s *ScheduleService) getOwnedRule(ctx context.Context, clerkID string, ruleID string) (*models.ScheduleRule, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.ruleRepo.GetByID(ctx, ruleID)
+	if err != nil {
+		return nil, ErrScheduleRuleNotFound
+	}
+
+	if rule.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	return rule, nil
+}
+
+// ApplyRules sets the scheduled end of a newly started session from the
+// user's recurring rules. Rules are evaluated in the user's time zone and the
+// earliest stop time still ahead of the start wins.
+func (s *ScheduleService) ApplyRules(ctx context.Context, user *models.User, session *models.TimeSession) error {
+	rules, err := s.ruleRepo.ListByUser(ctx, user.ID, true)
+	if err != nil {
+		return err
+	}
+
+	loc := user.Location()
+	start := session.StartTime.In(loc)
+
+	var earliest *time.Time
+	for _, rule := range rules {
+		if !rule.AppliesOn(start.Weekday()) {
+			continue
+		}
+
+		hour, minute, ok := parseClock(rule.StopTime)
+		if !ok {
+			continue
+		}
+
+		stop := time.Date(start.Year(), start.Month(), start.Day(), hour, minute, 0, 0, loc).UTC()
+		if !stop.After(session.StartTime) {
+			continue
+		}
+		if earliest == nil || stop.Before(*earliest) {
+			earliest = &stop
+		}
+	}
+
+	if earliest == nil {
+		return nil
+	}
+
+	event, ok := newSessionEvent(session.Status, models.SessionEventScheduleSet, systemActor,
+		map[string]interface{}{"scheduled_end": *earliest})
+	if !ok {
+		return nil
+	}
+
+	if _, err := s.sessionRepo.SetScheduledEnd(ctx, session.ID, earliest, event); err != nil {
+		return err
+	}
+	session.ScheduledEnd = earliest
+	return nil
+}
 
-	return s.sessionRepo.Update(ctx, session)
+// parseClock parses a "HH:MM" wall-clock time.
+func parseClock(value string) (hour, minute int, ok bool) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, false
+	}
+	return t.Hour(), t.Minute(), true
+}
+
+// uniqueDays returns days sorted with duplicates removed.
+func uniqueDays(days []int) []int {
+	present := make(map[int]bool, len(days))
+	for _, d := range days {
+		present[d] = true
+	}
+
+	result := make([]int, 0, len(present))
+	for d := 0; d <= 6; d++ {
+		if present[d] {
+			result = append(result, d)
+		}
+	}
+	return result
 }
 
 // ProcessScheduledSessions is called by the scheduler to auto-stop sessions
 func (s *ScheduleService) ProcessScheduledSessions(ctx context.Context) (int, error) {
-	sessions, err := s.sessionRepo.GetDueScheduledSessions(ctx)
+	event, _ := newSessionEvent(string(models.SessionStatusActive), models.SessionEventAutoStopped, systemActor,
+		map[string]interface{}{"end_reason": models.EndReasonScheduled})
+	stopped, err := s.sessionRepo.StopDueScheduled(ctx, time.Now().UTC(), event)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(stopped), nil
+}
+
+// ProcessIdleSessions is called by the scheduler to handle sessions whose
+// device has not sent a heartbeat within timeout. With stop set, they are
+// completed at their last heartbeat; otherwise they are only flagged.
+func (s *ScheduleService) ProcessIdleSessions(ctx context.Context, timeout time.Duration, stop bool) (int, error) {
+	sessions, err := s.sessionRepo.GetIdleSessions(ctx, time.Now().UTC().Add(-timeout))
 	if err != nil {
 		return 0, err
 	}
 
+	event, _ := newSessionEvent(string(models.SessionStatusActive), models.SessionEventAutoStopped, systemActor,
+		map[string]interface{}{"end_reason": models.EndReasonIdle})
+
 	count := 0
 	for _, session := range sessions {
-		session.EndTime = session.ScheduledEnd
-		session.Status = string(models.SessionStatusCompleted)
+		if !stop {
+			if err := s.sessionRepo.FlagIdle(ctx, session.ID); err != nil {
+				continue
+			}
+			count++
+			continue
+		}
+
+		ok, err := s.sessionRepo.StopIdle(ctx, session.ID, event)
+		if err != nil || !ok {
+			continue
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// ProcessOverdueSessions is called by the scheduler to close forgotten
+// sessions that have run past the user's maximum duration. The end time is
+// capped at start + maximum and the session is marked auto_closed.
+func (s *ScheduleService) ProcessOverdueSessions(ctx context.Context) (int, error) {
+	shortest, err := s.policyService.ShortestMaxDuration(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	sessions, err := s.sessionRepo.GetActiveStartedBefore(ctx, now.Add(-shortest))
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, session := range sessions {
+		user, err := s.userRepo.GetByID(ctx, session.UserID)
+		if err != nil {
+			continue
+		}
+
+		maxDuration, err := s.policyService.MaxDuration(ctx, user)
+		if err != nil {
+			continue
+		}
+
+		endTime := session.StartTime.Add(maxDuration)
+		if endTime.After(now) {
+			continue
+		}
+
+		event, ok := newSessionEvent(session.Status, models.SessionEventAutoStopped, systemActor,
+			map[string]interface{}{"end_reason": models.EndReasonAutoClosed, "end_time": endTime})
+		if !ok {
+			continue
+		}
 
-		if err := s.sessionRepo.Update(ctx, &session); err != nil {
-			// Log error but continue processing other sessions
+		ok, err = s.sessionRepo.Complete(ctx, session.ID, endTime, models.EndReasonAutoClosed, event)
+		if err != nil || !ok {
 			continue
 		}
 		count++
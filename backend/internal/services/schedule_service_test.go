@@ -0,0 +1,54 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"log_book/internal/models"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		value        string
+		hour, minute int
+		ok           bool
+	}{
+		{"17:30", 17, 30, true},
+		{"00:00", 0, 0, true},
+		{"23:59", 23, 59, true},
+		{"24:00", 0, 0, false},
+		{"5pm", 0, 0, false},
+		{"", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		hour, minute, ok := parseClock(tt.value)
+		if ok != tt.ok || hour != tt.hour || minute != tt.minute {
+			t.Errorf("parseClock(%q) = %d, %d, %v; want %d, %d, %v",
+				tt.value, hour, minute, ok, tt.hour, tt.minute, tt.ok)
+		}
+	}
+}
+
+func TestUniqueDays(t *testing.T) {
+	got := uniqueDays([]int{5, 1, 3, 1, 5})
+	want := []int{1, 3, 5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("uniqueDays() = %v, want %v", got, want)
+	}
+
+	if got := uniqueDays(nil); len(got) != 0 {
+		t.Errorf("uniqueDays(nil) = %v, want empty", got)
+	}
+}
+
+func TestScheduleRuleAppliesOn(t *testing.T) {
+	rule := &models.ScheduleRule{Days: []int{1, 2, 3, 4, 5}}
+	if !rule.AppliesOn(time.Monday) || !rule.AppliesOn(time.Friday) {
+		t.Error("weekday rule should apply Monday to Friday")
+	}
+	if rule.AppliesOn(time.Sunday) || rule.AppliesOn(time.Saturday) {
+		t.Error("weekday rule should not apply at the weekend")
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"encoding/json"
+
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// sessionActor is who changes a session, as recorded on its events.
+type sessionActor struct {
+	kind     string
+	userID   *uuid.UUID
+	deviceID string
+}
+
+// systemActor is the scheduler and other server-side jobs.
+var systemActor = sessionActor{kind: models.SessionActorSystem}
+
+// userActor is the session's owner acting from deviceID, which may be empty.
+func userActor(userID uuid.UUID, deviceID string) sessionActor {
+	return sessionActor{kind: models.SessionActorUser, userID: &userID, deviceID: deviceID}
+}
+
+// adminActor is an admin acting on someone else's session.
+func adminActor(userID uuid.UUID) sessionActor {
+	return sessionActor{kind: models.SessionActorAdmin, userID: &userID}
+}
+
+// newSessionEvent returns the event recording that actor applied event to a
+// session in status from, or false when the session state machine does not
+// allow it. from is empty for a session being created. details, if not nil,
+// is stored as JSON.
+func newSessionEvent(from string, event string, actor sessionActor, details map[string]interface{}) (*models.SessionEvent, bool) {
+	to, ok := models.SessionStatus(from).Next(event)
+	if !ok {
+		return nil, false
+	}
+
+	e := &models.SessionEvent{
+		Event:       event,
+		ToStatus:    string(to),
+		Actor:       actor.kind,
+		ActorUserID: actor.userID,
+	}
+	if from != "" {
+		e.FromStatus = &from
+	}
+	if actor.deviceID != "" {
+		e.DeviceID = &actor.deviceID
+	}
+	if details != nil {
+		// Only strings and times go in here, which always marshal
+		e.Details, _ = json.Marshal(details)
+	}
+	return e, true
+}
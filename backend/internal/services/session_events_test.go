@@ -0,0 +1,77 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestNewSessionEvent(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("user creating a session", func(t *testing.T) {
+		e, ok := newSessionEvent("", models.SessionEventStarted, userActor(userID, "laptop"), nil)
+		if !ok {
+			t.Fatal("started should be allowed on a new session")
+		}
+		if e.FromStatus != nil || e.ToStatus != string(models.SessionStatusActive) {
+			t.Errorf("status = %v -> %s", e.FromStatus, e.ToStatus)
+		}
+		if e.Actor != models.SessionActorUser || e.ActorUserID == nil || *e.ActorUserID != userID {
+			t.Errorf("actor = %s %v", e.Actor, e.ActorUserID)
+		}
+		if e.DeviceID == nil || *e.DeviceID != "laptop" || e.Details != nil {
+			t.Errorf("device = %v, details = %s", e.DeviceID, e.Details)
+		}
+	})
+
+	t.Run("user without a device", func(t *testing.T) {
+		e, _ := newSessionEvent(string(models.SessionStatusActive), models.SessionEventStopped, userActor(userID, ""), nil)
+		if e.DeviceID != nil {
+			t.Errorf("device = %q, want none", *e.DeviceID)
+		}
+	})
+
+	t.Run("admin voiding a session", func(t *testing.T) {
+		e, ok := newSessionEvent(string(models.SessionStatusCompleted), models.SessionEventVoided, adminActor(userID),
+			map[string]interface{}{"reason": "duplicate"})
+		if !ok {
+			t.Fatal("voided should be allowed on a completed session")
+		}
+		if e.FromStatus == nil || *e.FromStatus != string(models.SessionStatusCompleted) || e.ToStatus != string(models.SessionStatusCancelled) {
+			t.Errorf("status = %v -> %s", e.FromStatus, e.ToStatus)
+		}
+		if e.Actor != models.SessionActorAdmin || e.ActorUserID == nil || *e.ActorUserID != userID || e.DeviceID != nil {
+			t.Errorf("actor = %s %v %v", e.Actor, e.ActorUserID, e.DeviceID)
+		}
+		var details map[string]string
+		if err := json.Unmarshal(e.Details, &details); err != nil || details["reason"] != "duplicate" {
+			t.Errorf("details = %s", e.Details)
+		}
+	})
+
+	t.Run("system stopping a session", func(t *testing.T) {
+		end := time.Date(2025, 3, 10, 17, 0, 0, 0, time.UTC)
+		e, ok := newSessionEvent(string(models.SessionStatusActive), models.SessionEventAutoStopped, systemActor,
+			map[string]interface{}{"end_time": end})
+		if !ok {
+			t.Fatal("auto_stopped should be allowed on an active session")
+		}
+		if e.Actor != models.SessionActorSystem || e.ActorUserID != nil {
+			t.Errorf("actor = %s %v", e.Actor, e.ActorUserID)
+		}
+		if string(e.Details) != `{"end_time":"2025-03-10T17:00:00Z"}` {
+			t.Errorf("details = %s", e.Details)
+		}
+	})
+
+	t.Run("transition not allowed", func(t *testing.T) {
+		if e, ok := newSessionEvent(string(models.SessionStatusCancelled), models.SessionEventStopped, systemActor, nil); ok || e != nil {
+			t.Errorf("newSessionEvent() = %v, %v; want nil, false", e, ok)
+		}
+	})
+}
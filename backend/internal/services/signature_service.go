@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"log_book/internal/config"
+	"log_book/internal/mail"
+	"log_book/internal/models"
+	"log_book/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// SignatureService issues emailed sign-off links for submitted timesheets
+// and records the supervisor's attestation when one is used.
+//
+// A link token is "<request id>.<expiry unix>.<HMAC-SHA256 of both>", so a
+// token cannot be forged or have its expiry extended without the secret.
+// Single use is enforced by the request row. Links are bearer credentials,
+// so they are only built when the email is sent and never stored.
+type SignatureService struct {
+	signatureRepo    *repository.SignatureRepository
+	employerRepo     *repository.EmployerRepository
+	userRepo         *repository.UserRepository
+	timesheetService *TimesheetService
+	jobService       *JobService
+	mailer           mail.Sender
+	cfg              config.SigningConfig
+}
+
+func NewSignatureService(signatureRepo *repository.SignatureRepository, employerRepo *repository.EmployerRepository, userRepo *repository.UserRepository, timesheetService *TimesheetService, jobService *JobService, mailer mail.Sender, cfg config.SigningConfig) *SignatureService {
+	return &SignatureService{
+		signatureRepo:    signatureRepo,
+		employerRepo:     employerRepo,
+		userRepo:         userRepo,
+		timesheetService: timesheetService,
+		jobService:       jobService,
+		mailer:           mailer,
+		cfg:              cfg,
+	}
+}
+
+// maxSignerNameLength matches signature_requests.signed_name.
+const maxSignerNameLength = 200
+
+// SignatureMailPayload is the payload of a JobTypeSendSignatureRequest job.
+type SignatureMailPayload struct {
+	SignatureRequestID uuid.UUID `json:"signature_request_id"`
+}
+
+// RequestSignature emails a signing link for a submitted timesheet to its
+// reviewer on record, or to another address given by the owner; such a
+// request is flagged and so is the attestation left through it. Earlier
+// unused links for the timesheet stop working. Only timesheets can be signed
+// this way: reports are exports of the owner's own data, not a record with
+// a review state a signature could settle.
+func (s *SignatureService) RequestSignature(ctx context.Context, clerkID string, timesheetID string, input models.CreateSignatureRequestInput) (*models.SignatureRequest, error) {
+	if s.cfg.Secret == "" {
+		return nil, ErrSigningNotConfigured
+	}
+
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet, err := s.timesheetService.getTimesheet(ctx, timesheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if timesheet.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	if timesheet.Status != string(models.TimesheetSubmitted) {
+		return nil, ErrInvalidTimesheetTransition
+	}
+
+	employer, err := s.employerRepo.GetByID(ctx, timesheet.EmployerID.String())
+	if err != nil {
+		return nil, ErrEmployerNotFound
+	}
+
+	reviewer := timesheet.ReviewerEmail
+	if reviewer == "" {
+		reviewer = employer.SupervisorEmail
+	}
+	email, overridden, err := signatureRecipient(input.SupervisorEmail, reviewer, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &models.SignatureRequest{
+		TimesheetID:         timesheet.ID,
+		RequestedBy:         user.ID,
+		SupervisorEmail:     email,
+		RecipientOverridden: overridden,
+		ExpiresAt:           time.Now().UTC().Add(s.cfg.LinkTTL).Truncate(time.Second),
+	}
+	if err := s.signatureRepo.Create(ctx, req); err != nil {
+		return nil, err
+	}
+
+	payload := SignatureMailPayload{SignatureRequestID: req.ID}
+	if _, err := s.jobService.Enqueue(ctx, JobTypeSendSignatureRequest, payload, JobOptions{}); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// signatureRecipient picks where a signing link goes: the requested address
+// if any, else the reviewer. It reports whether that differs from the
+// reviewer and refuses the owner's own address.
+func signatureRecipient(requested, reviewer, ownerEmail string) (string, bool, error) {
+	email := strings.TrimSpace(requested)
+	if email == "" {
+		email = reviewer
+	}
+	if email == "" {
+		return "", false, ErrNoSupervisorEmail
+	}
+	if ownerEmail != "" && strings.EqualFold(email, ownerEmail) {
+		return "", false, ErrSignerIsOwner
+	}
+	return email, !strings.EqualFold(email, reviewer), nil
+}
+
+// SendSignatureRequest handles JobTypeSendSignatureRequest jobs: it emails
+// the signing link of a request that can still be used.
+func (s *SignatureService) SendSignatureRequest(ctx context.Context, payload SignatureMailPayload) error {
+	req, err := s.signatureRepo.GetByID(ctx, payload.SignatureRequestID)
+	if err != nil {
+		return err
+	}
+	if req.SignedAt != nil || req.RevokedAt != nil || !time.Now().Before(req.ExpiresAt) {
+		return nil
+	}
+
+	timesheet, err := s.timesheetService.getTimesheet(ctx, req.TimesheetID.String())
+	if err != nil {
+		return err
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, timesheet.UserID)
+	if err != nil {
+		return err
+	}
+
+	employer, err := s.employerRepo.GetByID(ctx, timesheet.EmployerID.String())
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.timesheetService.withDetails(ctx, owner, timesheet); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      req.SupervisorEmail,
+		Subject: fmt.Sprintf("%s asks you to sign off hours at %s", actorName(owner), employer.Name),
+		Body: fmt.Sprintf(
+			"%s has submitted a timesheet of %.2f hours worked at %s from %s to %s and asks you, as their supervisor, to confirm it.\n\n"+
+				"Review the hours and sign here:\n%s\n\n"+
+				"The link can be used once and expires on %s UTC. If you were not expecting this email you can ignore it.\n",
+			actorName(owner),
+			float64(timesheet.TotalSeconds)/3600,
+			employer.Name,
+			timesheet.PeriodStart.Format("Jan 2, 2006"),
+			timesheet.PeriodEnd.Format("Jan 2, 2006"),
+			s.signingURL(req),
+			req.ExpiresAt.Format("Jan 2, 2006 15:04"),
+		),
+	})
+}
+
+// ListSignatureRequests returns a timesheet's signature requests.
+func (s *SignatureService) ListSignatureRequests(ctx context.Context, clerkID string, timesheetID string) ([]models.SignatureRequest, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet, err := s.timesheetService.getTimesheet(ctx, timesheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if timesheet.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	return s.signatureRepo.ListByTimesheet(ctx, timesheet.ID)
+}
+
+// GetSigningPage returns what the supervisor reviews for a valid token.
+func (s *SignatureService) GetSigningPage(ctx context.Context, token string) (*models.SigningPage, error) {
+	req, err := s.verifyToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet, err := s.timesheetService.getTimesheet(ctx, req.TimesheetID.String())
+	if err != nil {
+		return nil, ErrSignatureLinkInvalid
+	}
+	if timesheet.Status != string(models.TimesheetSubmitted) {
+		return nil, ErrSignatureLinkInvalid
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, timesheet.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	employer, err := s.employerRepo.GetByID(ctx, timesheet.EmployerID.String())
+	if err != nil {
+		return nil, ErrSignatureLinkInvalid
+	}
+
+	if _, err := s.timesheetService.withDetails(ctx, owner, timesheet); err != nil {
+		return nil, err
+	}
+
+	return &models.SigningPage{
+		Request:      *req,
+		Timesheet:    *timesheet,
+		UserName:     actorName(owner),
+		EmployerName: employer.Name,
+		Location:     owner.Location(),
+	}, nil
+}
+
+// Sign records the supervisor's typed name, IP and user agent and approves
+// the timesheet. The link cannot be used again.
+func (s *SignatureService) Sign(ctx context.Context, token string, input models.SignInput, ip, userAgent string) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || !input.Confirm {
+		return ErrSignatureConfirmation
+	}
+	if utf8.RuneCountInString(name) > maxSignerNameLength {
+		return ErrSignerNameTooLong
+	}
+
+	req, err := s.verifyToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.signatureRepo.Sign(ctx, req.ID, name, ip, userAgent)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSignatureLinkInvalid
+	}
+	return nil
+}
+
+func (s *SignatureService) signingURL(req *models.SignatureRequest) string {
+	payload := req.ID.String() + "." + strconv.FormatInt(req.ExpiresAt.Unix(), 10)
+	return s.cfg.BaseURL + "/sign/" + payload + "." + s.sign(payload)
+}
+
+func (s *SignatureService) sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.Secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyToken checks the token's signature and expiry and returns its
+// request if it is still usable.
+func (s *SignatureService) verifyToken(ctx context.Context, token string) (*models.SignatureRequest, error) {
+	if s.cfg.Secret == "" {
+		return nil, ErrSignatureLinkInvalid
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrSignatureLinkInvalid
+	}
+
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.sign(payload))) {
+		return nil, ErrSignatureLinkInvalid
+	}
+
+	id, err := uuid.Parse(parts[0])
+	if err != nil {
+		return nil, ErrSignatureLinkInvalid
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() >= expires {
+		return nil, ErrSignatureLinkInvalid
+	}
+
+	req, err := s.signatureRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrSignatureLinkInvalid
+	}
+	if req.SignedAt != nil || req.RevokedAt != nil || req.ExpiresAt.Unix() != expires {
+		return nil, ErrSignatureLinkInvalid
+	}
+
+	return req, nil
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"log_book/internal/config"
+	"log_book/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func testSignatureService() *SignatureService {
+	return &SignatureService{cfg: config.SigningConfig{Secret: "test-secret", BaseURL: "https://logbook.example"}}
+}
+
+func TestSigningURL(t *testing.T) {
+	s := testSignatureService()
+	req := &models.SignatureRequest{ID: uuid.New(), ExpiresAt: time.Unix(1741600000, 0)}
+
+	url := s.signingURL(req)
+	token, ok := strings.CutPrefix(url, "https://logbook.example/sign/")
+	if !ok {
+		t.Fatalf("signingURL() = %q, want it under the base URL", url)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != req.ID.String() || parts[1] != "1741600000" {
+		t.Fatalf("token = %q, want <id>.<expiry>.<signature>", token)
+	}
+	if parts[2] != s.sign(parts[0]+"."+parts[1]) {
+		t.Error("token signature does not match its payload")
+	}
+}
+
+// TestVerifyTokenRejects covers tokens turned away before the request is
+// looked up; the service has no repository, so reaching it would panic.
+func TestVerifyTokenRejects(t *testing.T) {
+	s := testSignatureService()
+	id := uuid.New().String()
+	future := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	past := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	valid := func(payload string) string { return payload + "." + s.sign(payload) }
+
+	other := &SignatureService{cfg: config.SigningConfig{Secret: "other-secret"}}
+
+	tests := map[string]string{
+		"empty":            "",
+		"too few parts":    id + "." + future,
+		"bad signature":    id + "." + future + ".AAAA",
+		"extended expiry":  id + "." + future + "." + s.sign(id+"."+past),
+		"other secret":     id + "." + future + "." + other.sign(id+"."+future),
+		"expired":          valid(id + "." + past),
+		"not a uuid":       valid("not-a-uuid." + future),
+		"non-numeric time": valid(id + ".tomorrow"),
+	}
+
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.verifyToken(context.Background(), token); !errors.Is(err, ErrSignatureLinkInvalid) {
+				t.Errorf("verifyToken() error = %v, want %v", err, ErrSignatureLinkInvalid)
+			}
+		})
+	}
+
+	disabled := &SignatureService{}
+	if _, err := disabled.verifyToken(context.Background(), valid(id+"."+future)); !errors.Is(err, ErrSignatureLinkInvalid) {
+		t.Errorf("verifyToken() without a secret error = %v, want %v", err, ErrSignatureLinkInvalid)
+	}
+}
+
+func TestSignValidatesName(t *testing.T) {
+	s := testSignatureService()
+
+	tests := []struct {
+		name  string
+		input models.SignInput
+		want  error
+	}{
+		{"blank name", models.SignInput{Name: "   ", Confirm: true}, ErrSignatureConfirmation},
+		{"not confirmed", models.SignInput{Name: "Pat Lee"}, ErrSignatureConfirmation},
+		{"name too long", models.SignInput{Name: strings.Repeat("é", maxSignerNameLength+1), Confirm: true}, ErrSignerNameTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Sign(context.Background(), "token", tt.input, "203.0.113.7", "test"); !errors.Is(err, tt.want) {
+				t.Errorf("Sign() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignatureRecipient(t *testing.T) {
+	tests := []struct {
+		name           string
+		requested      string
+		reviewer       string
+		want           string
+		wantOverridden bool
+		wantErr        error
+	}{
+		{name: "defaults to reviewer", reviewer: "boss@example.com", want: "boss@example.com"},
+		{name: "reviewer given again", requested: " Boss@Example.com ", reviewer: "boss@example.com", want: "Boss@Example.com"},
+		{name: "other address flagged", requested: "someone@example.com", reviewer: "boss@example.com", want: "someone@example.com", wantOverridden: true},
+		{name: "no reviewer on record", requested: "someone@example.com", want: "someone@example.com", wantOverridden: true},
+		{name: "nowhere to send", wantErr: ErrNoSupervisorEmail},
+		{name: "owner's own address", requested: "Me@example.com", reviewer: "boss@example.com", wantErr: ErrSignerIsOwner},
+		{name: "owner is the reviewer", reviewer: "me@example.com", wantErr: ErrSignerIsOwner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, overridden, err := signatureRecipient(tt.requested, tt.reviewer, "me@example.com")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want || overridden != tt.wantOverridden {
+				t.Errorf("signatureRecipient() = %q, %v, want %q, %v", got, overridden, tt.want, tt.wantOverridden)
+			}
+		})
+	}
+}
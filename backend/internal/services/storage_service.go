@@ -19,25 +19,28 @@ import (
 )
 
 type StorageService struct {
-	mediaRepo     *repository.MediaRepository
-	docRepo       *repository.DocumentRepository
-	userRepo      *repository.UserRepository
-	r2Config      config.R2Config
-	s3Client      *s3.Client
-	presignClient *s3.PresignClient
+	mediaRepo        *repository.MediaRepository
+	docRepo          *repository.DocumentRepository
+	userRepo         *repository.UserRepository
+	timesheetService *TimesheetService
+	r2Config         config.R2Config
+	s3Client         *s3.Client
+	presignClient    *s3.PresignClient
 }
 
 func NewStorageService(
 	mediaRepo *repository.MediaRepository,
 	docRepo *repository.DocumentRepository,
 	userRepo *repository.UserRepository,
+	timesheetService *TimesheetService,
 	r2Config config.R2Config,
 ) *StorageService {
 	svc := &StorageService{
-		mediaRepo: mediaRepo,
-		docRepo:   docRepo,
-		userRepo:  userRepo,
-		r2Config:  r2Config,
+		mediaRepo:        mediaRepo,
+		docRepo:          docRepo,
+		userRepo:         userRepo,
+		timesheetService: timesheetService,
+		r2Config:         r2Config,
 	}
 
 	// Initialize S3 client for R2
@@ -118,6 +121,10 @@ func (s *StorageService) ConfirmUpload(ctx context.Context, clerkID string, inpu
 		return nil, ErrUnauthorized
 	}
 
+	if err := s.timesheetService.CheckUnlocked(ctx, user.ID, doc.LogDate); err != nil {
+		return nil, err
+	}
+
 	docID, _ := uuid.Parse(input.DocumentID)
 
 	media := &models.MediaFile{
@@ -164,6 +171,10 @@ func (s *StorageService) DeleteMedia(ctx context.Context, clerkID string, mediaI
 		return ErrUnauthorized
 	}
 
+	if err := s.checkDocumentUnlocked(ctx, media); err != nil {
+		return err
+	}
+
 	// Delete from R2 storage (best-effort — don't fail if R2 delete fails)
 	_ = s.deleteFromR2(ctx, media.StorageKey)
 
@@ -196,12 +207,26 @@ func (s *StorageService) DeleteMediaByURL(ctx context.Context, clerkID string, p
 		return ErrUnauthorized
 	}
 
+	if err := s.checkDocumentUnlocked(ctx, media); err != nil {
+		return err
+	}
+
 	// Delete from R2 storage (best-effort)
 	_ = s.deleteFromR2(ctx, media.StorageKey)
 
 	return s.mediaRepo.Delete(ctx, media.ID)
 }
 
+// checkDocumentUnlocked refuses to remove media from a document whose date is
+// covered by an approved timesheet.
+func (s *StorageService) checkDocumentUnlocked(ctx context.Context, media *models.MediaFile) error {
+	doc, err := s.docRepo.GetByID(ctx, media.DocumentID.String())
+	if err != nil {
+		return ErrDocumentNotFound
+	}
+	return s.timesheetService.CheckUnlocked(ctx, media.UserID, doc.LogDate)
+}
+
 func getExtension(mimeType string) string {
 	extensions := map[string]string{
 		"image/jpeg":      ".jpg",
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -16,19 +17,23 @@ import (
 )
 
 type SummarizeService struct {
-	documentRepo *repository.DocumentRepository
-	userRepo     *repository.UserRepository
-	adminRepo    *repository.AdminRepository
-	claudeAPIKey string
-	httpClient   *http.Client
+	documentRepo        *repository.DocumentRepository
+	userRepo            *repository.UserRepository
+	adminRepo           *repository.AdminRepository
+	jobService          *JobService
+	trainingPlanService *TrainingPlanService
+	claudeAPIKey        string
+	httpClient          *http.Client
 }
 
-func NewSummarizeService(documentRepo *repository.DocumentRepository, userRepo *repository.UserRepository, adminRepo *repository.AdminRepository, claudeAPIKey string) *SummarizeService {
+func NewSummarizeService(documentRepo *repository.DocumentRepository, userRepo *repository.UserRepository, adminRepo *repository.AdminRepository, jobService *JobService, trainingPlanService *TrainingPlanService, claudeAPIKey string) *SummarizeService {
 	return &SummarizeService{
-		documentRepo: documentRepo,
-		userRepo:     userRepo,
-		adminRepo:    adminRepo,
-		claudeAPIKey: claudeAPIKey,
+		documentRepo:        documentRepo,
+		userRepo:            userRepo,
+		adminRepo:           adminRepo,
+		jobService:          jobService,
+		trainingPlanService: trainingPlanService,
+		claudeAPIKey:        claudeAPIKey,
 		httpClient: &http.Client{
 			Timeout: 120 * time.Second,
 		},
@@ -43,7 +48,7 @@ func (s *SummarizeService) GetMonthlyUsage(ctx context.Context, clerkID string)
 	if err != nil {
 		return 0, maxMonthlyRequests, err
 	}
-	count, err := s.adminRepo.GetMonthlyRequestCount(ctx, user.ID.String())
+	count, err := s.adminRepo.GetMonthlyRequestCount(ctx, user.ID.String(), monthStart(user.Location()))
 	if err != nil {
 		return 0, maxMonthlyRequests, err
 	}
@@ -61,14 +66,9 @@ func (s *SummarizeService) StreamSummary(ctx context.Context, clerkID string, pa
 		return err
 	}
 
-	// Check monthly rate limit
-	count, err := s.adminRepo.GetMonthlyRequestCount(ctx, user.ID.String())
-	if err != nil {
+	if err := s.checkMonthlyLimit(ctx, user); err != nil {
 		return err
 	}
-	if count >= maxMonthlyRequests {
-		return fmt.Errorf("RATE_LIMITED: You've used all %d AI summaries for this month. Resets on the 1st.", maxMonthlyRequests)
-	}
 
 	docs, err := s.documentRepo.SearchWithContent(ctx, user.ID, params)
 	if err != nil {
@@ -104,10 +104,94 @@ func (s *SummarizeService) StreamSummary(ctx context.Context, clerkID string, pa
 		strings.Join(promptParts, "\n\n"),
 	)
 
+	return s.streamCompletion(ctx, user, userMessage, 1000, writer, flusher)
+}
+
+// StreamEvaluationDraft drafts an I-983 self-evaluation for one of an
+// employer's evaluation periods from the log entries tagged to each training
+// goal, and streams it like StreamSummary. Drafts count towards the same
+// monthly limit.
+func (s *SummarizeService) StreamEvaluationDraft(ctx context.Context, clerkID string, employerID string, evalType string, writer io.Writer, flusher http.Flusher) error {
+	if s.claudeAPIKey == "" {
+		return fmt.Errorf("Claude API key not configured")
+	}
+
+	material, err := s.trainingPlanService.GetEvaluationMaterial(ctx, clerkID, employerID, evalType)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkMonthlyLimit(ctx, material.User); err != nil {
+		return err
+	}
+
+	if len(material.Goals) == 0 {
+		fmt.Fprintf(writer, "data: {\"text\":\"Add training plan goals for this employer before drafting an evaluation.\"}\n\n")
+		flusher.Flush()
+		fmt.Fprintf(writer, "data: [DONE]\n\n")
+		flusher.Flush()
+		return nil
+	}
+
+	var goalParts []string
+	for i, goal := range material.Goals {
+		part := fmt.Sprintf("Goal %d: %s", i+1, goal.Title)
+		if goal.Description != "" {
+			part += "\n" + goal.Description
+		}
+
+		docs := material.Documents[goal.ID]
+		if len(docs) == 0 {
+			part += "\n(no log entries tagged to this goal)"
+		}
+		for _, doc := range docs {
+			text := extractText(doc.Content)
+			if text == "" {
+				text = "(empty)"
+			}
+			part += fmt.Sprintf("\n--- %s: %s ---\n%s", doc.LogDate.Format("2006-01-02"), doc.Title, text)
+		}
+		goalParts = append(goalParts, part)
+	}
+
+	period := "12-month"
+	if material.Evaluation.Type == models.EvaluationFinal {
+		period = "final"
+	}
+
+	userMessage := fmt.Sprintf(
+		"Draft the student's %s self-evaluation for Form I-983 (STEM OPT training plan) at %s, covering %s to %s. For each training goal below, describe the progress made, citing concrete activities from the tagged work log entries. Do not invent activities that are not in the logs; where a goal has no entries, say that progress still needs to be described. Write in the first person, in plain paragraphs, one section per goal.\n\n%s",
+		period,
+		material.Employer.Name,
+		material.Evaluation.PeriodStart.Format("2006-01-02"),
+		material.Evaluation.PeriodEnd.Format("2006-01-02"),
+		strings.Join(goalParts, "\n\n"),
+	)
+
+	return s.streamCompletion(ctx, material.User, userMessage, 2000, writer, flusher)
+}
+
+// checkMonthlyLimit returns a RATE_LIMITED error once the user has used this
+// month's AI requests.
+func (s *SummarizeService) checkMonthlyLimit(ctx context.Context, user *models.User) error {
+	count, err := s.adminRepo.GetMonthlyRequestCount(ctx, user.ID.String(), monthStart(user.Location()))
+	if err != nil {
+		return err
+	}
+	if count >= maxMonthlyRequests {
+		return fmt.Errorf("RATE_LIMITED: You've used all %d AI summaries for this month. Resets on the 1st.", maxMonthlyRequests)
+	}
+	return nil
+}
+
+// streamCompletion sends userMessage to Claude and forwards the streamed
+// text to writer as SSE events, ending with [DONE]. Token usage is recorded
+// against the user.
+func (s *SummarizeService) streamCompletion(ctx context.Context, user *models.User, userMessage string, maxTokens int, writer io.Writer, flusher http.Flusher) error {
 	// Call Claude API with streaming
 	reqBody := map[string]interface{}{
 		"model":      "claude-sonnet-4-5-20250929",
-		"max_tokens": 1000,
+		"max_tokens": maxTokens,
 		"stream":     true,
 		"messages": []map[string]string{
 			{"role": "user", "content": userMessage},
@@ -196,14 +280,13 @@ func (s *SummarizeService) StreamSummary(ctx context.Context, clerkID string, pa
 		}
 
 		if event.Type == "message_stop" {
-			// Persist usage stats asynchronously
-			go func(uid string, in, out int) {
-				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-				defer cancel()
-				date := time.Now().Format("2006-01-02")
-				// Log errors?
-				_ = s.adminRepo.UpsertAIUsage(ctx, uid, date, in, out)
-			}(user.ID.String(), inputTokens, outputTokens)
+			// Persist usage stats through the job queue so failed writes are retried
+			s.enqueueUsage(ctx, AIUsagePayload{
+				UserID:       user.ID.String(),
+				Date:         localDate(time.Now(), user.Location()).Format("2006-01-02"),
+				InputTokens:  inputTokens,
+				OutputTokens: outputTokens,
+			})
 
 			break
 		}
@@ -219,6 +302,30 @@ func (s *SummarizeService) StreamSummary(ctx context.Context, clerkID string, pa
 	return nil
 }
 
+// AIUsagePayload is the payload of a JobTypeRecordAIUsage job. Date is the
+// user's local date when the request finished.
+type AIUsagePayload struct {
+	UserID       string `json:"user_id"`
+	Date         string `json:"date"`
+	InputTokens  int    `json:"input_tokens"`
+	OutputTokens int    `json:"output_tokens"`
+}
+
+func (s *SummarizeService) enqueueUsage(ctx context.Context, usage AIUsagePayload) {
+	// The client may already be gone; queue the write regardless
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if _, err := s.jobService.Enqueue(ctx, JobTypeRecordAIUsage, usage, JobOptions{}); err != nil {
+		log.Printf("Failed to queue AI usage for user %s: %v", usage.UserID, err)
+	}
+}
+
+// RecordUsage handles JobTypeRecordAIUsage jobs.
+func (s *SummarizeService) RecordUsage(ctx context.Context, usage AIUsagePayload) error {
+	return s.adminRepo.UpsertAIUsage(ctx, usage.UserID, usage.Date, usage.InputTokens, usage.OutputTokens)
+}
+
 // extractText recursively extracts plain text from Tiptap JSONB content.
 func extractText(content json.RawMessage) string {
 	if content == nil {
@@ -2,31 +2,61 @@ package services
 
 import (
 	"context"
+	"log"
 	"time"
 
 	"log_book/internal/models"
 	"log_book/internal/repository"
+
+	"github.com/google/uuid"
 )
 
 type TimeService struct {
-	sessionRepo *repository.SessionRepository
-	userRepo    *repository.UserRepository
+	sessionRepo      *repository.SessionRepository
+	breakRepo        *repository.BreakRepository
+	userRepo         *repository.UserRepository
+	employerRepo     *repository.EmployerRepository
+	policyService    *PolicyService
+	scheduleService  *ScheduleService
+	timesheetService *TimesheetService
 }
 
-func NewTimeService(sessionRepo *repository.SessionRepository, userRepo *repository.UserRepository) *TimeService {
+func NewTimeService(sessionRepo *repository.SessionRepository, breakRepo *repository.BreakRepository, userRepo *repository.UserRepository, employerRepo *repository.EmployerRepository, policyService *PolicyService, scheduleService *ScheduleService, timesheetService *TimesheetService) *TimeService {
 	return &TimeService{
-		sessionRepo: sessionRepo,
-		userRepo:    userRepo,
+		sessionRepo:      sessionRepo,
+		breakRepo:        breakRepo,
+		userRepo:         userRepo,
+		employerRepo:     employerRepo,
+		policyService:    policyService,
+		scheduleService:  scheduleService,
+		timesheetService: timesheetService,
 	}
 }
 
-func (s *TimeService) StartSession(ctx context.Context, clerkID string, deviceID string) (*models.TimeSession, error) {
+func (s *TimeService) StartSession(ctx context.Context, clerkID string, input models.StartSessionInput) (*models.TimeSession, error) {
 	// Get or create user
 	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
 	if err != nil {
 		return nil, err
 	}
 
+	employerID, err := resolveEmployerID(ctx, s.employerRepo, user.ID, input.EmployerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.startSession(ctx, user, input.DeviceID, employerID, time.Now().UTC(), userActor(user.ID, input.DeviceID))
+}
+
+// StartPlannedSession opens a session for a planned shift start on the
+// user's behalf. It is subject to the same rules as a user-started session.
+func (s *TimeService) StartPlannedSession(ctx context.Context, user *models.User, at time.Time) (*models.TimeSession, error) {
+	actor := systemActor
+	actor.deviceID = models.ServerStartedDeviceID
+	return s.startSession(ctx, user, models.ServerStartedDeviceID, nil, at, actor)
+}
+
+func (s *TimeService) startSession(ctx context.Context, user *models.User, deviceID string, employerID *uuid.UUID, at time.Time, actor sessionActor) (*models.TimeSession, error) {
 	// Check for existing active session
 	activeSession, err := s.sessionRepo.GetActiveSession(ctx, user.ID)
 	if err != nil && err != ErrNoActiveSession {
@@ -36,33 +66,40 @@ func (s *TimeService) StartSession(ctx context.Context, clerkID string, deviceID
 		return nil, ErrSessionAlreadyActive
 	}
 
-	// Check one session per day
-	now := time.Now().UTC()
-	exists, err := s.sessionRepo.HasSessionOnDate(ctx, user.ID, now)
-	if err != nil {
+	// Check the per-day session limit (in the user's time zone)
+	if err := s.policyService.CheckStart(ctx, user, at); err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, ErrSessionExistsForDate
-	}
 
 	// Create new session
 	session := &models.TimeSession{
-		UserID:    user.ID,
-		StartTime: now,
-		Status:    string(models.SessionStatusActive),
-		DeviceID:  deviceID,
+		UserID:     user.ID,
+		StartTime:  at,
+		Status:     string(models.SessionStatusActive),
+		DeviceID:   deviceID,
+		EmployerID: employerID,
 	}
 
-	err = s.sessionRepo.Create(ctx, session)
+	event, _ := newSessionEvent("", models.SessionEventStarted, actor, nil)
+	err = s.sessionRepo.Create(ctx, session, event)
+	if isUniqueViolation(err) {
+		// Another request or instance started one first
+		return nil, ErrSessionAlreadyActive
+	}
 	if err != nil {
-		return nil, err
+		// e.g. a manual session that ends after at
+		return nil, overlapError(ctx, s.sessionRepo, err, user.ID, nil, at, nil)
+	}
+
+	// Recurring auto-stop rules; the session has started either way
+	if err := s.scheduleService.ApplyRules(ctx, user, session); err != nil {
+		log.Printf("Failed to apply schedule rules to session %s: %v", session.ID, err)
 	}
 
 	return session, nil
 }
 
-func (s *TimeService) StopSession(ctx context.Context, clerkID string, sessionID string) (*models.TimeSession, error) {
+func (s *TimeService) StopSession(ctx context.Context, clerkID string, input models.StopSessionInput) (*models.TimeSession, error) {
 	// Get user
 	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
 	if err != nil {
@@ -70,7 +107,7 @@ func (s *TimeService) StopSession(ctx context.Context, clerkID string, sessionID
 	}
 
 	// Get session
-	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	session, err := s.sessionRepo.GetByID(ctx, input.SessionID)
 	if err != nil {
 		return nil, ErrSessionNotFound
 	}
@@ -80,26 +117,200 @@ func (s *TimeService) StopSession(ctx context.Context, clerkID string, sessionID
 		return nil, ErrUnauthorized
 	}
 
-	// Check if already stopped
+	// Only an active session can be stopped
+	now := time.Now().UTC()
+	event, ok := newSessionEvent(session.Status, models.SessionEventStopped, userActor(user.ID, input.DeviceID),
+		map[string]interface{}{"end_time": now})
+	if !ok {
+		return nil, ErrNoActiveSession
+	}
+
+	// Check minimum net worked time (breaks excluded)
+	if err := s.policyService.CheckStop(ctx, user, session, now); err != nil {
+		return nil, err
+	}
+
+	// Stop the session; a paused session ends its break at the same time.
+	// The scheduler may have stopped it since it was read.
+	ok, err = s.sessionRepo.Complete(ctx, session.ID, now, models.EndReasonUser, event)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNoActiveSession
+	}
+
+	return s.sessionRepo.GetByID(ctx, input.SessionID)
+}
+
+// PauseSession starts a break on the user's active session.
+func (s *TimeService) PauseSession(ctx context.Context, clerkID string, sessionID string) (*models.TimeSession, error) {
+	session, err := s.getOwnedActiveSession(ctx, clerkID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Paused {
+		return nil, ErrSessionPaused
+	}
+
+	started, err := s.breakRepo.Start(ctx, session.ID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if started == nil {
+		// Another device paused or stopped the session since it was read
+		return nil, s.breakConflict(ctx, sessionID, ErrSessionPaused)
+	}
+
+	return s.sessionRepo.GetByID(ctx, sessionID)
+}
+
+// ResumeSession ends the open break on the user's active session.
+func (s *TimeService) ResumeSession(ctx context.Context, clerkID string, sessionID string) (*models.TimeSession, error) {
+	session, err := s.getOwnedActiveSession(ctx, clerkID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !session.Paused {
+		return nil, ErrSessionNotPaused
+	}
+
+	ended, err := s.breakRepo.EndOpen(ctx, session.ID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if ended == nil {
+		// Another device resumed or stopped the session since it was read
+		return nil, s.breakConflict(ctx, sessionID, ErrSessionNotPaused)
+	}
+
+	return s.sessionRepo.GetByID(ctx, sessionID)
+}
+
+// breakConflict explains why a pause or resume changed nothing: the session
+// is no longer active, or it is, and stateErr (already paused or not paused)
+// applies.
+func (s *TimeService) breakConflict(ctx context.Context, sessionID string, stateErr error) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return ErrSessionNotFound
+	}
 	if session.Status != string(models.SessionStatusActive) {
+		return ErrNoActiveSession
+	}
+	return stateErr
+}
+
+// RecordHeartbeat stores a device heartbeat for the user's active session.
+func (s *TimeService) RecordHeartbeat(ctx context.Context, clerkID string, input models.HeartbeatInput) (*models.TimeSession, error) {
+	session, err := s.getOwnedActiveSession(ctx, clerkID, input.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.sessionRepo.RecordHeartbeat(ctx, session.ID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
 		return nil, ErrNoActiveSession
 	}
 
-	// Check minimum 4 hours
-	now := time.Now().UTC()
-	if now.Sub(session.StartTime) < 4*time.Hour {
-		return nil, ErrSessionTooShort
+	return s.sessionRepo.GetByID(ctx, input.SessionID)
+}
+
+// CancelSession discards an active session that was started by mistake. The
+// row is kept with status cancelled and no longer counts toward any total.
+func (s *TimeService) CancelSession(ctx context.Context, clerkID string, input models.CancelSessionInput) (*models.TimeSession, error) {
+	session, err := s.getOwnedActiveSession(ctx, clerkID, input.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := input.Reason
+	if reason == "" {
+		reason = "Cancelled by user"
+	}
+
+	event, ok := newSessionEvent(session.Status, models.SessionEventCancelled, userActor(session.UserID, input.DeviceID),
+		map[string]interface{}{"reason": reason})
+	if !ok {
+		return nil, ErrNoActiveSession
 	}
 
-	// Stop the session
-	session.EndTime = &now
-	session.Status = string(models.SessionStatusCompleted)
+	// An open break is closed in the same statement
+	ok, err = s.sessionRepo.Cancel(ctx, session.ID, session.Status, time.Now().UTC(), reason, event)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNoActiveSession
+	}
 
-	err = s.sessionRepo.Update(ctx, session)
+	return s.sessionRepo.GetByID(ctx, input.SessionID)
+}
+
+// VoidSession cancels a completed session. Its times are kept for audit but
+// it is excluded from totals and from the per-day limit.
+func (s *TimeService) VoidSession(ctx context.Context, clerkID string, sessionID string, input models.VoidSessionInput) (*models.TimeSession, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
 	if err != nil {
 		return nil, err
 	}
 
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if session.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	if session.EndTime != nil {
+		if err := s.timesheetService.CheckSessionUnlocked(ctx, user, session.StartTime, *session.EndTime); err != nil {
+			return nil, err
+		}
+	}
+
+	event, ok := newSessionEvent(session.Status, models.SessionEventVoided, userActor(user.ID, input.DeviceID),
+		map[string]interface{}{"reason": input.Reason})
+	if !ok {
+		return nil, ErrSessionNotCompleted
+	}
+
+	ok, err = s.sessionRepo.Cancel(ctx, session.ID, session.Status, time.Now().UTC(), input.Reason, event)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrSessionNotCompleted
+	}
+
+	return s.sessionRepo.GetByID(ctx, sessionID)
+}
+
+func (s *TimeService) getOwnedActiveSession(ctx context.Context, clerkID string, sessionID string) (*models.TimeSession, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if session.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	if session.Status != string(models.SessionStatusActive) {
+		return nil, ErrNoActiveSession
+	}
+
 	return session, nil
 }
 
@@ -123,42 +334,37 @@ func (s *TimeService) CreateManualSession(ctx context.Context, clerkID string, i
 	if !endTime.After(startTime) {
 		return nil, ErrInvalidTimeRange
 	}
+	startTime, endTime = startTime.UTC(), endTime.UTC()
 
-	// Validate: duration >= 4 hours
-	if endTime.Sub(startTime) < 4*time.Hour {
-		return nil, ErrSessionTooShort
-	}
-
-	// Validate: duration <= 24 hours
-	if endTime.Sub(startTime) > 24*time.Hour {
-		return nil, ErrSessionTooLong
-	}
-
-	// Validate: end_time is not in the future
-	if endTime.After(time.Now().UTC()) {
-		return nil, ErrFutureEndTime
+	// Validate duration, future end and per-day limit against the user's policy
+	if err := s.policyService.CheckManual(ctx, user, startTime, endTime, endTime.Sub(startTime)); err != nil {
+		return nil, err
 	}
 
-	// Check one session per day
-	exists, err := s.sessionRepo.HasSessionOnDate(ctx, user.ID, startTime.UTC())
+	employerID, err := resolveEmployerID(ctx, s.employerRepo, user.ID, input.EmployerID)
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, ErrSessionExistsForDate
+
+	if err := s.timesheetService.CheckSessionUnlocked(ctx, user, startTime, endTime); err != nil {
+		return nil, err
 	}
 
+	reason := models.EndReasonManual
 	session := &models.TimeSession{
-		UserID:    user.ID,
-		StartTime: startTime.UTC(),
-		EndTime:   &endTime,
-		Status:    string(models.SessionStatusCompleted),
-		DeviceID:  input.DeviceID,
+		UserID:     user.ID,
+		StartTime:  startTime,
+		EndTime:    &endTime,
+		Status:     string(models.SessionStatusCompleted),
+		DeviceID:   input.DeviceID,
+		EndReason:  &reason,
+		EmployerID: employerID,
 	}
 
-	err = s.sessionRepo.CreateManual(ctx, session)
+	event, _ := newSessionEvent("", models.SessionEventRecorded, userActor(user.ID, input.DeviceID), nil)
+	err = s.sessionRepo.CreateManual(ctx, session, event)
 	if err != nil {
-		return nil, err
+		return nil, overlapError(ctx, s.sessionRepo, err, user.ID, nil, session.StartTime, session.EndTime)
 	}
 
 	return session, nil
@@ -170,7 +376,7 @@ func (s *TimeService) ListSessions(ctx context.Context, clerkID string, params m
 		return nil, 0, err
 	}
 
-	return s.sessionRepo.ListByUser(ctx, user.ID, params)
+	return s.sessionRepo.ListByUser(ctx, user.ID, params, user.Location())
 }
 
 func (s *TimeService) GetActiveSession(ctx context.Context, clerkID string) (*models.TimeSession, error) {
@@ -186,3 +392,53 @@ func (s *TimeService) GetActiveSession(ctx context.Context, clerkID string) (*mo
 
 	return session, nil
 }
+
+// ListEvents returns the state changes of one of the user's sessions, oldest
+// first.
+func (s *TimeService) ListEvents(ctx context.Context, clerkID string, sessionID string) ([]models.SessionEvent, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if session.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	return s.sessionRepo.ListEvents(ctx, session.ID)
+}
+
+// checkOverlap returns a SessionOverlapError when [start, end) overlaps any
+// of the user's sessions other than excludeID. A nil end is open-ended.
+func checkOverlap(ctx context.Context, sessionRepo *repository.SessionRepository, userID uuid.UUID, excludeID *uuid.UUID, start time.Time, end *time.Time) error {
+	conflicts, err := sessionRepo.ListOverlapping(ctx, userID, start, end, excludeID)
+	if err != nil || len(conflicts) == 0 {
+		return err
+	}
+
+	ids := make([]uuid.UUID, len(conflicts))
+	for i, session := range conflicts {
+		ids[i] = session.ID
+	}
+	return &SessionOverlapError{SessionIDs: ids}
+}
+
+// overlapError turns the exclusion violation raised when a write would make
+// the user's sessions overlap into a SessionOverlapError naming the sessions
+// in the way of [start, end). Other errors are returned unchanged.
+func overlapError(ctx context.Context, sessionRepo *repository.SessionRepository, err error, userID uuid.UUID, excludeID *uuid.UUID, start time.Time, end *time.Time) error {
+	if !isExclusionViolation(err) {
+		return err
+	}
+
+	if err := checkOverlap(ctx, sessionRepo, userID, excludeID, start, end); err != nil {
+		return err
+	}
+	// The session in the way was changed again since
+	return &SessionOverlapError{SessionIDs: []uuid.UUID{}}
+}
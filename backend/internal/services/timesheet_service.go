@@ -0,0 +1,362 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"log_book/internal/models"
+	"log_book/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type TimesheetService struct {
+	timesheetRepo *repository.TimesheetRepository
+	employerRepo  *repository.EmployerRepository
+	sessionRepo   *repository.SessionRepository
+	userRepo      *repository.UserRepository
+}
+
+func NewTimesheetService(timesheetRepo *repository.TimesheetRepository, employerRepo *repository.EmployerRepository, sessionRepo *repository.SessionRepository, userRepo *repository.UserRepository) *TimesheetService {
+	return &TimesheetService{
+		timesheetRepo: timesheetRepo,
+		employerRepo:  employerRepo,
+		sessionRepo:   sessionRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// CreateTimesheet opens a draft timesheet for a week (Monday to Sunday) or a
+// calendar month of an employer's sessions.
+func (s *TimesheetService) CreateTimesheet(ctx context.Context, clerkID string, input models.CreateTimesheetInput) (*models.Timesheet, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	employerID, err := resolveEmployerID(ctx, s.employerRepo, user.ID, &input.EmployerID)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+
+	end, err := timesheetPeriodEnd(input.PeriodType, start)
+	if err != nil {
+		return nil, err
+	}
+
+	overlaps, err := s.timesheetRepo.Overlaps(ctx, user.ID, *employerID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if overlaps {
+		return nil, ErrTimesheetExists
+	}
+
+	timesheet := &models.Timesheet{
+		UserID:      user.ID,
+		EmployerID:  *employerID,
+		PeriodType:  input.PeriodType,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Status:      string(models.TimesheetDraft),
+	}
+
+	err = s.timesheetRepo.Create(ctx, timesheet, actorName(user))
+	if isUniqueViolation(err) {
+		return nil, ErrTimesheetExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withDetails(ctx, user, timesheet)
+}
+
+func (s *TimesheetService) ListTimesheets(ctx context.Context, clerkID string) ([]models.Timesheet, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.timesheetRepo.ListByUser(ctx, user.ID)
+}
+
+// ListPendingReviews returns the submitted timesheets the caller supervises.
+func (s *TimesheetService) ListPendingReviews(ctx context.Context, clerkID string) ([]models.Timesheet, error) {
+	user, err := s.userRepo.GetOrCreateByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.timesheetRepo.ListSubmittedForSupervisor(ctx, user.Email)
+}
+
+// GetTimesheet returns a timesheet with its sessions and history. The owner,
+// the employer's supervisor and admins may view it.
+func (s *TimesheetService) GetTimesheet(ctx context.Context, clerkID string, timesheetID string) (*models.Timesheet, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet, err := s.getTimesheet(ctx, timesheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if timesheet.UserID != user.ID {
+		if err := s.checkReviewer(user, timesheet); err != nil {
+			return nil, err
+		}
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, timesheet.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withDetails(ctx, owner, timesheet)
+}
+
+// SubmitTimesheet sends a draft or rejected timesheet for review.
+func (s *TimesheetService) SubmitTimesheet(ctx context.Context, clerkID string, timesheetID string) (*models.Timesheet, error) {
+	return s.ownerTransition(ctx, clerkID, timesheetID, models.TimesheetActionSubmit,
+		[]models.TimesheetStatus{models.TimesheetDraft, models.TimesheetRejected}, models.TimesheetSubmitted)
+}
+
+// WithdrawTimesheet takes a submitted timesheet back to draft.
+func (s *TimesheetService) WithdrawTimesheet(ctx context.Context, clerkID string, timesheetID string) (*models.Timesheet, error) {
+	return s.ownerTransition(ctx, clerkID, timesheetID, models.TimesheetActionWithdraw,
+		[]models.TimesheetStatus{models.TimesheetSubmitted}, models.TimesheetDraft)
+}
+
+// ApproveTimesheet approves a submitted timesheet, locking its period.
+func (s *TimesheetService) ApproveTimesheet(ctx context.Context, clerkID string, timesheetID string, input models.TimesheetReviewInput) (*models.Timesheet, error) {
+	return s.reviewerTransition(ctx, clerkID, timesheetID, models.TimesheetActionApprove, input.Comment,
+		[]models.TimesheetStatus{models.TimesheetSubmitted}, models.TimesheetApproved)
+}
+
+// RejectTimesheet sends a submitted timesheet back to the user with a comment.
+func (s *TimesheetService) RejectTimesheet(ctx context.Context, clerkID string, timesheetID string, input models.TimesheetReviewInput) (*models.Timesheet, error) {
+	if strings.TrimSpace(input.Comment) == "" {
+		return nil, ErrReviewCommentRequired
+	}
+
+	return s.reviewerTransition(ctx, clerkID, timesheetID, models.TimesheetActionReject, input.Comment,
+		[]models.TimesheetStatus{models.TimesheetSubmitted}, models.TimesheetRejected)
+}
+
+// ReopenTimesheet returns an approved timesheet to draft, unlocking its
+// period so the user can make corrections and submit it again.
+func (s *TimesheetService) ReopenTimesheet(ctx context.Context, clerkID string, timesheetID string, input models.TimesheetReviewInput) (*models.Timesheet, error) {
+	return s.reviewerTransition(ctx, clerkID, timesheetID, models.TimesheetActionReopen, input.Comment,
+		[]models.TimesheetStatus{models.TimesheetApproved}, models.TimesheetDraft)
+}
+
+// CheckUnlocked returns ErrPeriodLocked when date is covered by one of the
+// user's approved timesheets. The lock applies to all of the user's work on
+// that date, whichever employer it is for, so detaching work from an
+// employer or deleting the employer does not unlock it.
+func (s *TimesheetService) CheckUnlocked(ctx context.Context, userID uuid.UUID, date time.Time) error {
+	return s.checkDays(ctx, userID, date, date)
+}
+
+// CheckSessionUnlocked is CheckUnlocked for every day of the user's a session
+// from start to end touches, so a session cannot reach into a locked period
+// from the day before or be moved or stretched into one.
+func (s *TimesheetService) CheckSessionUnlocked(ctx context.Context, user *models.User, start, end time.Time) error {
+	from, to := sessionDays(start, end, user.Location())
+	return s.checkDays(ctx, user.ID, from, to)
+}
+
+func (s *TimesheetService) checkDays(ctx context.Context, userID uuid.UUID, from, to time.Time) error {
+	locked, err := s.timesheetRepo.IsLocked(ctx, userID, from, to)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrPeriodLocked
+	}
+	return nil
+}
+
+// sessionDays returns the first and last local day of a session from start
+// to end. A session ending at midnight does not reach into the next day.
+func sessionDays(start, end time.Time, loc *time.Location) (time.Time, time.Time) {
+	last := end
+	if end.After(start) {
+		last = end.Add(-time.Nanosecond)
+	}
+	return localDate(start, loc), localDate(last, loc)
+}
+
+func (s *TimesheetService) ownerTransition(ctx context.Context, clerkID string, timesheetID string, action string, from []models.TimesheetStatus, to models.TimesheetStatus) (*models.Timesheet, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet, err := s.getTimesheet(ctx, timesheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if timesheet.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	event := &models.TimesheetEvent{
+		Action:      action,
+		ActorUserID: &user.ID,
+		ActorName:   actorName(user),
+	}
+	if err := s.transition(ctx, timesheet, from, to, event); err != nil {
+		return nil, err
+	}
+
+	return s.withDetails(ctx, user, timesheet)
+}
+
+func (s *TimesheetService) reviewerTransition(ctx context.Context, clerkID string, timesheetID string, action string, comment string, from []models.TimesheetStatus, to models.TimesheetStatus) (*models.Timesheet, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet, err := s.getTimesheet(ctx, timesheetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users never review their own hours, even as admins
+	if timesheet.UserID == user.ID {
+		return nil, ErrUnauthorized
+	}
+	if err := s.checkReviewer(user, timesheet); err != nil {
+		return nil, err
+	}
+
+	event := &models.TimesheetEvent{
+		Action:      action,
+		ActorUserID: &user.ID,
+		ActorName:   actorName(user),
+		Comment:     strings.TrimSpace(comment),
+	}
+	if err := s.transition(ctx, timesheet, from, to, event); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, timesheet.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withDetails(ctx, owner, timesheet)
+}
+
+func (s *TimesheetService) transition(ctx context.Context, timesheet *models.Timesheet, from []models.TimesheetStatus, to models.TimesheetStatus, event *models.TimesheetEvent) error {
+	ok, err := s.timesheetRepo.Transition(ctx, timesheet.ID, from, to, event)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTimesheetTransition
+	}
+
+	updated, err := s.timesheetRepo.GetByID(ctx, timesheet.ID.String())
+	if err != nil {
+		return err
+	}
+	*timesheet = *updated
+	return nil
+}
+
+// timesheetPeriodEnd returns the last day of the week or month starting on
+// start, which must be a Monday or the 1st.
+func timesheetPeriodEnd(periodType string, start time.Time) (time.Time, error) {
+	switch periodType {
+	case "week":
+		if start.Weekday() == time.Monday {
+			return start.AddDate(0, 0, 6), nil
+		}
+	case "month":
+		if start.Day() == 1 {
+			return start.AddDate(0, 1, -1), nil
+		}
+	}
+	return time.Time{}, ErrInvalidTimesheetPeriod
+}
+
+// checkReviewer allows admins and the user whose email is the supervisor
+// email the timesheet was submitted to.
+func (s *TimesheetService) checkReviewer(user *models.User, timesheet *models.Timesheet) error {
+	if user.Role == "admin" {
+		return nil
+	}
+	if timesheet.ReviewerEmail == "" || !strings.EqualFold(timesheet.ReviewerEmail, user.Email) {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+func (s *TimesheetService) getTimesheet(ctx context.Context, timesheetID string) (*models.Timesheet, error) {
+	timesheet, err := s.timesheetRepo.GetByID(ctx, timesheetID)
+	if err != nil {
+		return nil, ErrTimesheetNotFound
+	}
+	return timesheet, nil
+}
+
+// withDetails fills in the timesheet's sessions, total and history. owner
+// supplies the time zone the period is read in.
+func (s *TimesheetService) withDetails(ctx context.Context, owner *models.User, timesheet *models.Timesheet) (*models.Timesheet, error) {
+	loc := owner.Location()
+	from := time.Date(timesheet.PeriodStart.Year(), timesheet.PeriodStart.Month(), timesheet.PeriodStart.Day(), 0, 0, 0, 0, loc)
+	to := time.Date(timesheet.PeriodEnd.Year(), timesheet.PeriodEnd.Month(), timesheet.PeriodEnd.Day()+1, 0, 0, 0, 0, loc)
+
+	sessions, err := s.sessionRepo.ListCompletedBetween(ctx, owner.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet.Sessions = []models.TimeSession{}
+	for _, session := range sessions {
+		if session.EmployerID == nil || *session.EmployerID != timesheet.EmployerID {
+			continue
+		}
+		timesheet.Sessions = append(timesheet.Sessions, session)
+	}
+
+	// Only the part of a session across the period's edge that falls inside it counts
+	days, err := s.sessionRepo.ListDays(ctx, owner.ID, timesheet.PeriodStart, timesheet.PeriodEnd,
+		string(models.SessionStatusCompleted), &timesheet.EmployerID)
+	if err != nil {
+		return nil, err
+	}
+	timesheet.TotalSeconds = 0
+	for _, day := range days {
+		timesheet.TotalSeconds += day.NetSeconds
+	}
+
+	events, err := s.timesheetRepo.ListEvents(ctx, timesheet.ID)
+	if err != nil {
+		return nil, err
+	}
+	timesheet.Events = events
+
+	return timesheet, nil
+}
+
+// actorName is the name recorded on events by a signed-in user.
+func actorName(user *models.User) string {
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.Email
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"log_book/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestTimesheetPeriodEnd(t *testing.T) {
+	tests := []struct {
+		periodType string
+		start      string
+		want       string
+		wantErr    bool
+	}{
+		{periodType: "week", start: "2025-03-10", want: "2025-03-16"},
+		{periodType: "week", start: "2025-03-11", wantErr: true},
+		{periodType: "month", start: "2025-02-01", want: "2025-02-28"},
+		{periodType: "month", start: "2024-02-01", want: "2024-02-29"},
+		{periodType: "month", start: "2025-02-02", wantErr: true},
+		{periodType: "year", start: "2025-01-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.periodType+" "+tt.start, func(t *testing.T) {
+			got, err := timesheetPeriodEnd(tt.periodType, mustDate(tt.start))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTimesheetPeriod) {
+					t.Errorf("error = %v, want %v", err, ErrInvalidTimesheetPeriod)
+				}
+				return
+			}
+			if err != nil || !got.Equal(mustDate(tt.want)) {
+				t.Errorf("timesheetPeriodEnd() = %s, %v, want %s", got.Format("2006-01-02"), err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsForeignKeyViolation(t *testing.T) {
+	fk := fmt.Errorf("delete employer: %w", &pgconn.PgError{Code: "23503"})
+	if !isForeignKeyViolation(fk) {
+		t.Error("wrapped 23503 not detected")
+	}
+	if isForeignKeyViolation(&pgconn.PgError{Code: "23505"}) {
+		t.Error("unique violation reported as a foreign key violation")
+	}
+	if isForeignKeyViolation(errors.New("23503")) {
+		t.Error("plain error reported as a foreign key violation")
+	}
+}
+
+func TestCheckReviewer(t *testing.T) {
+	s := &TimesheetService{}
+	timesheet := &models.Timesheet{ReviewerEmail: "Boss@Example.com"}
+
+	tests := []struct {
+		name      string
+		user      models.User
+		timesheet *models.Timesheet
+		want      error
+	}{
+		{"recorded reviewer", models.User{Email: "boss@example.com"}, timesheet, nil},
+		{"someone else", models.User{Email: "intern@example.com"}, timesheet, ErrUnauthorized},
+		{"admin", models.User{Email: "ops@example.com", Role: "admin"}, timesheet, nil},
+		{"no reviewer recorded", models.User{Email: "boss@example.com"}, &models.Timesheet{}, ErrUnauthorized},
+		{"no reviewer recorded, admin", models.User{Role: "admin"}, &models.Timesheet{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.checkReviewer(&tt.user, tt.timesheet); err != tt.want {
+				t.Errorf("checkReviewer() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionDays(t *testing.T) {
+	loc := time.FixedZone("EST", -5*3600)
+	at := func(day, hour int) time.Time { return time.Date(2025, 3, day, hour, 0, 0, 0, loc) }
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		from, to   string
+	}{
+		{"same day", at(10, 9), at(10, 17), "2025-03-10", "2025-03-10"},
+		{"across midnight", at(9, 22), at(10, 2), "2025-03-09", "2025-03-10"},
+		{"ends at midnight", at(9, 16), at(10, 0), "2025-03-09", "2025-03-09"},
+		{"late in UTC, early locally", time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 4, 0, 0, 0, time.UTC), "2025-03-09", "2025-03-09"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := sessionDays(tt.start, tt.end, loc)
+			if !from.Equal(mustDate(tt.from)) || !to.Equal(mustDate(tt.to)) {
+				t.Errorf("sessionDays() = %s, %s; want %s, %s", from.Format("2006-01-02"), to.Format("2006-01-02"), tt.from, tt.to)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"log_book/internal/models"
+	"log_book/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// evaluationGraceDays is how long after its period ends an I-983
+// evaluation may still be filed on time.
+const evaluationGraceDays = 10
+
+// maxEvaluationDocuments caps the log entries fed into an evaluation draft.
+const maxEvaluationDocuments = 100
+
+type TrainingPlanService struct {
+	trainingRepo     *repository.TrainingPlanRepository
+	employerRepo     *repository.EmployerRepository
+	documentRepo     *repository.DocumentRepository
+	userRepo         *repository.UserRepository
+	timesheetService *TimesheetService
+}
+
+func NewTrainingPlanService(trainingRepo *repository.TrainingPlanRepository, employerRepo *repository.EmployerRepository, documentRepo *repository.DocumentRepository, userRepo *repository.UserRepository, timesheetService *TimesheetService) *TrainingPlanService {
+	return &TrainingPlanService{
+		trainingRepo:     trainingRepo,
+		employerRepo:     employerRepo,
+		documentRepo:     documentRepo,
+		userRepo:         userRepo,
+		timesheetService: timesheetService,
+	}
+}
+
+// GetTrainingPlan returns an employer's goals and evaluation deadlines.
+func (s *TrainingPlanService) GetTrainingPlan(ctx context.Context, clerkID string, employerID string) (*models.TrainingPlan, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	employer, err := s.getOwnedEmployer(ctx, user, employerID)
+	if err != nil {
+		return nil, err
+	}
+
+	goals, err := s.trainingRepo.ListGoalsByEmployer(ctx, employer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	submitted, err := s.trainingRepo.ListSubmittedEvaluations(ctx, employer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := localDate(time.Now(), user.Location())
+	return &models.TrainingPlan{
+		Employer:    *employer,
+		Goals:       goals,
+		Evaluations: evaluationDeadlines(employer, submitted, today),
+	}, nil
+}
+
+func (s *TrainingPlanService) CreateGoal(ctx context.Context, clerkID string, employerID string, input models.TrainingGoalInput) (*models.TrainingGoal, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	employer, err := s.getOwnedEmployer(ctx, user, employerID)
+	if err != nil {
+		return nil, err
+	}
+
+	goal := &models.TrainingGoal{
+		UserID:      user.ID,
+		EmployerID:  employer.ID,
+		Title:       strings.TrimSpace(input.Title),
+		Description: input.Description,
+	}
+
+	if err := s.trainingRepo.CreateGoal(ctx, goal); err != nil {
+		return nil, err
+	}
+
+	return goal, nil
+}
+
+func (s *TrainingPlanService) UpdateGoal(ctx context.Context, clerkID string, goalID string, input models.TrainingGoalInput) (*models.TrainingGoal, error) {
+	goal, err := s.getOwnedGoal(ctx, clerkID, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	goal.Title = strings.TrimSpace(input.Title)
+	goal.Description = input.Description
+
+	if err := s.trainingRepo.UpdateGoal(ctx, goal); err != nil {
+		return nil, err
+	}
+
+	return goal, nil
+}
+
+// DeleteGoal removes a goal and its document links; the documents are kept.
+func (s *TrainingPlanService) DeleteGoal(ctx context.Context, clerkID string, goalID string) error {
+	goal, err := s.getOwnedGoal(ctx, clerkID, goalID)
+	if err != nil {
+		return err
+	}
+
+	return s.trainingRepo.DeleteGoal(ctx, goal.ID)
+}
+
+// GetDocumentGoals returns the IDs of the goals a document is tagged with.
+func (s *TrainingPlanService) GetDocumentGoals(ctx context.Context, clerkID string, docID string) ([]uuid.UUID, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := s.getOwnedDocument(ctx, user, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.trainingRepo.ListDocumentGoalIDs(ctx, doc.ID)
+}
+
+// SetDocumentGoals replaces the goals a document is tagged with. Every goal
+// must belong to the user, and the document's date must not be locked by an
+// approved timesheet.
+func (s *TrainingPlanService) SetDocumentGoals(ctx context.Context, clerkID string, docID string, input models.DocumentGoalsInput) ([]uuid.UUID, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := s.getOwnedDocument(ctx, user, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.timesheetService.CheckUnlocked(ctx, user.ID, doc.LogDate); err != nil {
+		return nil, err
+	}
+
+	goalIDs := make([]string, 0, len(input.GoalIDs))
+	for _, id := range input.GoalIDs {
+		goal, err := s.trainingRepo.GetGoalByID(ctx, id)
+		if err != nil || goal.UserID != user.ID {
+			return nil, ErrTrainingGoalNotFound
+		}
+		goalIDs = append(goalIDs, goal.ID.String())
+	}
+
+	if err := s.trainingRepo.SetDocumentGoals(ctx, doc.ID, goalIDs); err != nil {
+		return nil, err
+	}
+
+	return s.trainingRepo.ListDocumentGoalIDs(ctx, doc.ID)
+}
+
+// SubmitEvaluation records that an evaluation was filed and returns the
+// updated deadlines.
+func (s *TrainingPlanService) SubmitEvaluation(ctx context.Context, clerkID string, employerID string, evalType string, input models.SubmitEvaluationInput) ([]models.EvaluationDeadline, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	employer, err := s.getOwnedEmployer(ctx, user, employerID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := localDate(time.Now(), user.Location())
+	if _, err := findEvaluation(employer, evalType, today); err != nil {
+		return nil, err
+	}
+
+	submittedOn := today
+	if input.SubmittedOn != "" {
+		submittedOn, err = time.Parse("2006-01-02", input.SubmittedOn)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+	}
+
+	if err := s.trainingRepo.SubmitEvaluation(ctx, employer.ID, models.EvaluationType(evalType), submittedOn); err != nil {
+		return nil, err
+	}
+
+	submitted, err := s.trainingRepo.ListSubmittedEvaluations(ctx, employer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluationDeadlines(employer, submitted, today), nil
+}
+
+// EvaluationMaterial is what an evaluation draft is written from: the
+// employer's goals and the log entries tagged to each during the period.
+type EvaluationMaterial struct {
+	User       *models.User
+	Employer   *models.Employer
+	Evaluation models.EvaluationDeadline
+	Goals      []models.TrainingGoal
+	Documents  map[uuid.UUID][]models.Document
+}
+
+// GetEvaluationMaterial gathers the goals and tagged documents for one of an
+// employer's evaluations.
+func (s *TrainingPlanService) GetEvaluationMaterial(ctx context.Context, clerkID string, employerID string, evalType string) (*EvaluationMaterial, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	employer, err := s.getOwnedEmployer(ctx, user, employerID)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation, err := findEvaluation(employer, evalType, localDate(time.Now(), user.Location()))
+	if err != nil {
+		return nil, err
+	}
+
+	goals, err := s.trainingRepo.ListGoalsByEmployer(ctx, employer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tagged, err := s.trainingRepo.ListGoalDocuments(ctx, employer.ID, evaluation.PeriodStart, evaluation.PeriodEnd, maxEvaluationDocuments)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[uuid.UUID][]models.Document)
+	for _, gd := range tagged {
+		docs[gd.GoalID] = append(docs[gd.GoalID], gd.Document)
+	}
+
+	return &EvaluationMaterial{
+		User:       user,
+		Employer:   employer,
+		Evaluation: evaluation,
+		Goals:      goals,
+		Documents:  docs,
+	}, nil
+}
+
+func (s *TrainingPlanService) getOwnedEmployer(ctx context.Context, user *models.User, employerID string) (*models.Employer, error) {
+	employer, err := s.employerRepo.GetByID(ctx, employerID)
+	if err != nil {
+		return nil, ErrEmployerNotFound
+	}
+
+	if employer.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	return employer, nil
+}
+
+func (s *TrainingPlanService) getOwnedGoal(ctx context.Context, clerkID string, goalID string) (*models.TrainingGoal, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+
+	goal, err := s.trainingRepo.GetGoalByID(ctx, goalID)
+	if err != nil {
+		return nil, ErrTrainingGoalNotFound
+	}
+
+	if goal.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	return goal, nil
+}
+
+func (s *TrainingPlanService) getOwnedDocument(ctx context.Context, user *models.User, docID string) (*models.Document, error) {
+	doc, err := s.documentRepo.GetByID(ctx, docID)
+	if err != nil {
+		return nil, ErrDocumentNotFound
+	}
+
+	if doc.UserID != user.ID {
+		return nil, ErrUnauthorized
+	}
+
+	return doc, nil
+}
+
+// findEvaluation returns the named evaluation of an employer's deadlines.
+func findEvaluation(employer *models.Employer, evalType string, today time.Time) (models.EvaluationDeadline, error) {
+	t := models.EvaluationType(evalType)
+	if t != models.EvaluationTwelveMonth && t != models.EvaluationFinal {
+		return models.EvaluationDeadline{}, ErrInvalidEvaluationType
+	}
+
+	for _, d := range evaluationDeadlines(employer, nil, today) {
+		if d.Type == t {
+			return d, nil
+		}
+	}
+	return models.EvaluationDeadline{}, ErrEvaluationNotApplicable
+}
+
+// evaluationDeadlines derives the I-983 evaluations from the employment
+// dates. The 12-month evaluation covers the first year and only applies if
+// employment lasts longer than that; the final evaluation covers the rest,
+// ending at 24 months or the employer's end date if that is earlier. Each
+// is due evaluationGraceDays after its period's last day.
+func evaluationDeadlines(employer *models.Employer, submitted map[models.EvaluationType]time.Time, today time.Time) []models.EvaluationDeadline {
+	start := employer.StartDate
+	twelveMonthEnd := start.AddDate(1, 0, -1)
+	finalEnd := start.AddDate(2, 0, -1)
+	if employer.EndDate != nil && employer.EndDate.Before(finalEnd) {
+		finalEnd = *employer.EndDate
+	}
+
+	var deadlines []models.EvaluationDeadline
+	finalStart := start
+	if finalEnd.After(twelveMonthEnd) {
+		deadlines = append(deadlines, newEvaluationDeadline(models.EvaluationTwelveMonth, start, twelveMonthEnd, submitted, today))
+		finalStart = twelveMonthEnd.AddDate(0, 0, 1)
+	}
+	deadlines = append(deadlines, newEvaluationDeadline(models.EvaluationFinal, finalStart, finalEnd, submitted, today))
+
+	return deadlines
+}
+
+func newEvaluationDeadline(t models.EvaluationType, periodStart, periodEnd time.Time, submitted map[models.EvaluationType]time.Time, today time.Time) models.EvaluationDeadline {
+	d := models.EvaluationDeadline{
+		Type:        t,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		DueDate:     periodEnd.AddDate(0, 0, evaluationGraceDays),
+	}
+
+	if submittedOn, ok := submitted[t]; ok {
+		d.Status = models.EvaluationSubmitted
+		d.SubmittedOn = &submittedOn
+		return d
+	}
+
+	switch {
+	case today.After(d.DueDate):
+		d.Status = models.EvaluationOverdue
+	case today.After(periodEnd):
+		d.Status = models.EvaluationDue
+	default:
+		d.Status = models.EvaluationUpcoming
+	}
+	return d
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"log_book/internal/models"
+)
+
+func TestEvaluationDeadlines(t *testing.T) {
+	start := mustDate("2024-03-01")
+	shortEnd := mustDate("2024-11-30")
+	midEnd := mustDate("2025-06-30")
+
+	tests := []struct {
+		name      string
+		endDate   *time.Time
+		submitted map[models.EvaluationType]time.Time
+		today     string
+		want      []models.EvaluationDeadline
+	}{
+		{
+			name:  "two years",
+			today: "2025-03-05",
+			want: []models.EvaluationDeadline{
+				{Type: models.EvaluationTwelveMonth, PeriodEnd: mustDate("2025-02-28"), DueDate: mustDate("2025-03-10"), Status: models.EvaluationDue},
+				{Type: models.EvaluationFinal, PeriodEnd: mustDate("2026-02-28"), DueDate: mustDate("2026-03-10"), Status: models.EvaluationUpcoming},
+			},
+		},
+		{
+			name:      "twelve month submitted, ended early",
+			endDate:   &midEnd,
+			submitted: map[models.EvaluationType]time.Time{models.EvaluationTwelveMonth: mustDate("2025-03-02")},
+			today:     "2025-07-20",
+			want: []models.EvaluationDeadline{
+				{Type: models.EvaluationTwelveMonth, PeriodEnd: mustDate("2025-02-28"), DueDate: mustDate("2025-03-10"), Status: models.EvaluationSubmitted},
+				{Type: models.EvaluationFinal, PeriodEnd: midEnd, DueDate: mustDate("2025-07-10"), Status: models.EvaluationOverdue},
+			},
+		},
+		{
+			name:    "shorter than a year",
+			endDate: &shortEnd,
+			today:   "2024-06-01",
+			want: []models.EvaluationDeadline{
+				{Type: models.EvaluationFinal, PeriodEnd: shortEnd, DueDate: mustDate("2024-12-10"), Status: models.EvaluationUpcoming},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employer := &models.Employer{StartDate: start, EndDate: tt.endDate}
+			got := evaluationDeadlines(employer, tt.submitted, mustDate(tt.today))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d deadlines, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				d := got[i]
+				if d.Type != want.Type || !d.PeriodEnd.Equal(want.PeriodEnd) || !d.DueDate.Equal(want.DueDate) || d.Status != want.Status {
+					t.Errorf("deadline %d = %+v, want %+v", i, d, want)
+				}
+			}
+			if len(got) == 2 && !got[1].PeriodStart.Equal(mustDate("2025-03-01")) {
+				t.Errorf("final period starts %s, want the day after the first year", got[1].PeriodStart.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestFindEvaluation(t *testing.T) {
+	end := mustDate("2024-11-30")
+	employer := &models.Employer{StartDate: mustDate("2024-03-01"), EndDate: &end}
+	today := mustDate("2024-06-01")
+
+	if _, err := findEvaluation(employer, "annual", today); !errors.Is(err, ErrInvalidEvaluationType) {
+		t.Errorf("unknown type: error = %v, want %v", err, ErrInvalidEvaluationType)
+	}
+	if _, err := findEvaluation(employer, string(models.EvaluationTwelveMonth), today); !errors.Is(err, ErrEvaluationNotApplicable) {
+		t.Errorf("twelve month under a year: error = %v, want %v", err, ErrEvaluationNotApplicable)
+	}
+	if d, err := findEvaluation(employer, string(models.EvaluationFinal), today); err != nil || !d.PeriodEnd.Equal(end) {
+		t.Errorf("final = %+v, %v", d, err)
+	}
+}
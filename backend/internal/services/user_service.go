@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"log_book/internal/models"
+	"log_book/internal/repository"
+)
+
+type UserService struct {
+	userRepo *repository.UserRepository
+}
+
+func NewUserService(userRepo *repository.UserRepository) *UserService {
+	return &UserService{userRepo: userRepo}
+}
+
+// UpdateProfile applies the non-empty fields of input to the user's profile.
+func (s *UserService) UpdateProfile(ctx context.Context, clerkID string, input models.UpdateUserInput) (*models.User, error) {
+	user, err := s.userRepo.GetByClerkID(ctx, clerkID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if input.Timezone != "" {
+		if !isTimezoneName(input.Timezone) {
+			return nil, ErrInvalidTimezone
+		}
+		// Go and Postgres ship different zone databases; reports run in both
+		known, err := s.userRepo.TimezoneKnown(ctx, input.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		if !known {
+			return nil, ErrInvalidTimezone
+		}
+		user.Timezone = input.Timezone
+	}
+	if input.Name != "" {
+		user.Name = input.Name
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// isTimezoneName reports whether name is an IANA zone Go can load. "Local"
+// is refused: it means the server's zone, not the user's, and Postgres does
+// not know it.
+func isTimezoneName(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
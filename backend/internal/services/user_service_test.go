@@ -0,0 +1,24 @@
+package services
+
+import "testing"
+
+func TestIsTimezoneName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"America/New_York", true},
+		{"Asia/Kolkata", true},
+		{"UTC", true},
+		{"", false},
+		{"Local", false},
+		{"Mars/Olympus_Mons", false},
+		{"../etc/passwd", false},
+	}
+
+	for _, tt := range tests {
+		if got := isTimezoneName(tt.name); got != tt.want {
+			t.Errorf("isTimezoneName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}